@@ -185,6 +185,12 @@ func build(stmt *Statement, parent reflect.Value, types *typeDictionary) (v refl
 				return nilValue, fmt.Errorf("%s: no extension function", ss.Location())
 			}
 			y.addext(ss, v, parent)
+		case types != nil && types.unknownStatement != nil:
+			// The caller decides whether the keyword is an error.
+			n, _ := v.Interface().(Node)
+			if err := types.unknownStatement(n, ss); err != nil {
+				return nilValue, fmt.Errorf("%s: %v", ss.Location(), err)
+			}
 		default:
 			return nilValue, fmt.Errorf("%s: unknown %s field: %s", ss.Location(), stmt.Keyword, ss.Keyword)
 		}
@@ -0,0 +1,106 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+// This file implements the capabilities that a server advertises for the
+// modules that it implements.
+
+import (
+	"sort"
+	"strings"
+)
+
+// Capabilities returns the capability URI of the latest revision of each
+// module within ms, in the form defined by RFC 6020 section 5.6.4 for the
+// capabilities of the NETCONF hello message:
+//
+//	namespace?module=name&revision=date&features=a,b&deviations=c,d
+//
+// The revision is the most recent revision of the module, and is omitted if
+// the module has none. The features are those defined by the module and its
+// submodules, which are all presumed to be supported. The deviations are the
+// modules within ms that define deviations of the nodes of the module. The
+// features and deviations are sorted, and are omitted if there are none. The
+// URIs are ordered by module name. Modules without a namespace, such as those
+// that have not been processed, are skipped.
+func (ms *Modules) Capabilities() []string {
+	deviations := ms.deviationModules()
+
+	var caps []string
+	for _, name := range ms.SortedModuleNames() {
+		m := ms.Modules[name]
+		if m.Namespace == nil {
+			continue
+		}
+		params := []string{"module=" + m.Name}
+		if rev := m.Current(); rev != "" {
+			params = append(params, "revision="+rev)
+		}
+		var features []string
+		for f := range definedFeatures(m) {
+			features = append(features, f)
+		}
+		if len(features) > 0 {
+			sort.Strings(features)
+			params = append(params, "features="+strings.Join(features, ","))
+		}
+		if devs := deviations[m.Name]; len(devs) > 0 {
+			params = append(params, "deviations="+strings.Join(devs, ","))
+		}
+		caps = append(caps, m.Namespace.Name+"?"+strings.Join(params, "&"))
+	}
+	return caps
+}
+
+// deviationModules returns the sorted names of the modules defining
+// deviations of the nodes of each module within ms, keyed by the name of the
+// deviated module. The deviations of a submodule are defined by the module it
+// belongs to. The deviated module is given by the prefix of the first node
+// of the target of each deviation; targets with unknown prefixes are skipped.
+func (ms *Modules) deviationModules() map[string][]string {
+	seen := map[string]map[string]bool{}
+	for _, mods := range []map[string]*Module{ms.Modules, ms.SubModules} {
+		for name, m := range mods {
+			if name != m.Name {
+				continue
+			}
+			for _, d := range m.Deviation {
+				first := strings.SplitN(strings.TrimPrefix(d.Name, "/"), "/", 2)[0]
+				prefix, _ := getPrefix(first)
+				target := FindModuleByPrefix(d, prefix)
+				if target == nil {
+					continue
+				}
+				from, target := module(m), module(target)
+				if from == nil || target == nil {
+					continue
+				}
+				if seen[target.Name] == nil {
+					seen[target.Name] = map[string]bool{}
+				}
+				seen[target.Name][from.Name] = true
+			}
+		}
+	}
+
+	deviations := map[string][]string{}
+	for target, froms := range seen {
+		for from := range froms {
+			deviations[target] = append(deviations[target], from)
+		}
+		sort.Strings(deviations[target])
+	}
+	return deviations
+}
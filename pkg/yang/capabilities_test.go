@@ -0,0 +1,89 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCapabilities(t *testing.T) {
+	ms := NewModules()
+	for name, src := range map[string]string{
+		"example-fw.yang": `
+			module example-fw {
+				yang-version 1.1;
+				prefix "fw";
+				namespace "urn:example:fw";
+				include example-fw-sub;
+				revision 2008-10-14;
+				revision 2007-01-01;
+				feature logging;
+				container c {
+					leaf l { type string; }
+				}
+			}`,
+		"example-fw-sub.yang": `
+			submodule example-fw-sub {
+				yang-version 1.1;
+				belongs-to example-fw { prefix fw; }
+				feature cache;
+			}`,
+		"example-dev.yang": `
+			module example-dev {
+				prefix "dev";
+				namespace "urn:example:dev";
+				import example-fw { prefix f; }
+				include example-dev-sub;
+				deviation /f:c/f:l {
+					deviate not-supported;
+				}
+			}`,
+		"example-dev-sub.yang": `
+			submodule example-dev-sub {
+				belongs-to example-dev { prefix dev; }
+				import example-fw { prefix f; }
+				deviation /f:c {
+					deviate add { config false; }
+				}
+			}`,
+		"other-dev.yang": `
+			module other-dev {
+				prefix "o";
+				namespace "urn:example:other";
+				import example-fw { prefix f; }
+				deviation /f:c {
+					deviate add { config false; }
+				}
+			}`,
+	} {
+		if err := ms.Parse(src, name); err != nil {
+			t.Fatalf("cannot parse %s: %v", name, err)
+		}
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process modules: %v", errs)
+	}
+
+	want := []string{
+		"urn:example:dev?module=example-dev",
+		"urn:example:fw?module=example-fw&revision=2008-10-14&features=cache,logging&deviations=example-dev,other-dev",
+		"urn:example:other?module=other-dev",
+	}
+	if diff := cmp.Diff(want, ms.Capabilities()); diff != "" {
+		t.Errorf("Capabilities (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+// This file implements the extraction of the must and when XPath expressions
+// of a set of modules.
+
+import (
+	"sort"
+)
+
+// A Constraint is a must or when XPath expression within the schema.
+type Constraint struct {
+	Path  string // Path of the entry that the constraint applies to.
+	Kind  string // The kind of the constraint, "must" or "when".
+	XPath string // The XPath expression.
+	// Module is the module or submodule in which the expression was
+	// defined. Prefixes within XPath are resolved against its imports.
+	Module *Module
+	// ErrorMessage and ErrorAppTag are the error-message and error-app-tag
+	// of a must statement, if any.
+	ErrorMessage string
+	ErrorAppTag  string
+}
+
+// AllConstraints returns the must and when constraints of the entries of all
+// modules within ms, sorted by path. The when statement of an augment is
+// reported against the path of the entry that it augments. AllConstraints
+// must be called after ms has been processed.
+func (ms *Modules) AllConstraints() []Constraint {
+	var cs []Constraint
+	seen := map[*Entry]bool{}
+	for _, m := range ms.Modules {
+		cs = appendConstraints(cs, ToEntry(m), seen)
+	}
+	sort.SliceStable(cs, func(i, j int) bool {
+		switch {
+		case cs[i].Path != cs[j].Path:
+			return cs[i].Path < cs[j].Path
+		case cs[i].Kind != cs[j].Kind:
+			return cs[i].Kind < cs[j].Kind
+		default:
+			return cs[i].XPath < cs[j].XPath
+		}
+	})
+	return cs
+}
+
+// appendConstraints appends the constraints of e and its descendants to cs,
+// skipping any entries in seen.
+func appendConstraints(cs []Constraint, e *Entry, seen map[*Entry]bool) []Constraint {
+	if e == nil || seen[e] {
+		return cs
+	}
+	seen[e] = true
+
+	if xpath, ok := e.GetWhenXPath(); ok {
+		cs = append(cs, Constraint{
+			Path:   e.Path(),
+			Kind:   "when",
+			XPath:  xpath,
+			Module: RootNode(e.Node),
+		})
+	}
+	for _, v := range e.Extra["must"] {
+		m, ok := v.(*Must)
+		if !ok {
+			continue
+		}
+		c := Constraint{
+			Path:   e.Path(),
+			Kind:   "must",
+			XPath:  m.Name,
+			Module: RootNode(m),
+		}
+		if m.ErrorMessage != nil {
+			c.ErrorMessage = m.ErrorMessage.Name
+		}
+		if m.ErrorAppTag != nil {
+			c.ErrorAppTag = m.ErrorAppTag.Name
+		}
+		cs = append(cs, c)
+	}
+	for _, a := range e.Augmented {
+		if xpath, ok := a.GetWhenXPath(); ok {
+			cs = append(cs, Constraint{
+				Path:   e.Path(),
+				Kind:   "when",
+				XPath:  xpath,
+				Module: RootNode(a.Node),
+			})
+		}
+	}
+
+	for _, ce := range e.Dir {
+		cs = appendConstraints(cs, ce, seen)
+	}
+	if e.RPC != nil {
+		cs = appendConstraints(cs, e.RPC.Input, seen)
+		cs = appendConstraints(cs, e.RPC.Output, seen)
+	}
+	return cs
+}
@@ -0,0 +1,81 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAllConstraints(t *testing.T) {
+	ms := NewModules()
+	if err := ms.Parse(mustReadFile(filepath.Join("testdata", "when.yang")), "when.yang"); err != nil {
+		t.Fatalf("cannot parse when.yang: %v", err)
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process when.yang: %v", errs)
+	}
+
+	// Module is checked separately since it is self-referential.
+	type constraint struct {
+		Path, Kind, XPath, ErrorMessage, ErrorAppTag string
+	}
+	want := []constraint{{
+		Path:  "/when/interfaces/interface",
+		Kind:  "when",
+		XPath: "w:type = 'tunnel'",
+	}, {
+		Path:         "/when/interfaces/interface/mtu",
+		Kind:         "must",
+		XPath:        ". >= 64",
+		ErrorMessage: "mtu must be at least 64",
+		ErrorAppTag:  "mtu-too-small",
+	}, {
+		Path:  "/when/interfaces/interface/mtu",
+		Kind:  "when",
+		XPath: "../type = 'ethernet'",
+	}, {
+		Path:  "/when/interfaces/interface/vlan",
+		Kind:  "when",
+		XPath: "../type = 'vlan'",
+	}, {
+		Path:  "/when/interfaces/interface/vlan/id",
+		Kind:  "must",
+		XPath: ". != 0 and . != 4095",
+	}, {
+		Path:  "/when/reset/input/delay",
+		Kind:  "must",
+		XPath: ". < 3600",
+	}}
+
+	var got []constraint
+	for _, c := range ms.AllConstraints() {
+		if c.Module == nil || c.Module.Name != "when" {
+			t.Errorf("%s %s %q: got module %v, want when", c.Path, c.Kind, c.XPath, c.Module)
+		}
+		got = append(got, constraint{
+			Path:         c.Path,
+			Kind:         c.Kind,
+			XPath:        c.XPath,
+			ErrorMessage: c.ErrorMessage,
+			ErrorAppTag:  c.ErrorAppTag,
+		})
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("AllConstraints (-want, +got):\n%s", diff)
+	}
+}
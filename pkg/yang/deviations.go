@@ -0,0 +1,100 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+// This file implements reporting of the deviations defined by modules.
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+)
+
+// WriteDeviationReport writes to w a CSV report of the deviations defined by
+// the latest revision of each module and submodule within ms. The first row
+// is a header, followed by one row for each property set by each deviate
+// statement, with the columns:
+//
+//	module    the name of the module or submodule defining the deviation
+//	target    the target path of the deviation, as written
+//	deviate   the type of the deviate statement, i.e., add, replace,
+//	          delete or not-supported
+//	property  the keyword of the property, e.g., default or max-elements
+//	value     the argument of the property
+//
+// A not-supported deviate statement, which removes its target path and the
+// nodes within it, has a single row with an empty property and value. Rows
+// are ordered by module name, and then by the order of the statements within
+// the module.
+func (ms *Modules) WriteDeviationReport(w io.Writer) error {
+	var mods []*Module
+	for _, mm := range []map[string]*Module{ms.Modules, ms.SubModules} {
+		for name, m := range mm {
+			if name == m.Name {
+				mods = append(mods, m)
+			}
+		}
+	}
+	sort.Slice(mods, func(i, j int) bool { return mods[i].Name < mods[j].Name })
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"module", "target", "deviate", "property", "value"}); err != nil {
+		return err
+	}
+	for _, m := range mods {
+		for _, d := range m.Deviation {
+			for _, dv := range d.Deviate {
+				props := deviateProperties(dv)
+				if len(props) == 0 {
+					props = [][2]string{{"", ""}}
+				}
+				for _, p := range props {
+					if err := cw.Write([]string{m.Name, d.Name, dv.Name, p[0], p[1]}); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// deviateProperties returns the keyword and argument of each property set
+// by d, in the order in which they are listed within RFC 7950.
+func deviateProperties(d *Deviate) [][2]string {
+	var props [][2]string
+	add := func(keyword string, v *Value) {
+		if v != nil {
+			props = append(props, [2]string{keyword, v.Name})
+		}
+	}
+	if d.Type != nil {
+		props = append(props, [2]string{"type", d.Type.Name})
+	}
+	add("units", d.Units)
+	for _, m := range d.Must {
+		props = append(props, [2]string{"must", m.Name})
+	}
+	for _, u := range d.Unique {
+		add("unique", u)
+	}
+	add("default", d.Default)
+	add("config", d.Config)
+	add("mandatory", d.Mandatory)
+	add("min-elements", d.MinElements)
+	add("max-elements", d.MaxElements)
+	return props
+}
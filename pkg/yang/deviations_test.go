@@ -0,0 +1,212 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+import (
+	"bytes"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteDeviationReport(t *testing.T) {
+	tests := []struct {
+		desc     string
+		inFile   string
+		wantRows []string
+	}{{
+		desc:   "add",
+		inFile: "deviate.yang",
+		wantRows: []string{
+			"deviate,/target/add/config,add,config,false",
+			"deviate,/target/add/default,add,default,a default value",
+			"deviate,/target/add/max-and-min-elements,add,min-elements,42",
+			"deviate,/target/add/max-and-min-elements,add,max-elements,42",
+			"deviate,/target/add/units,add,units,fish per second",
+		},
+	}, {
+		desc:   "replace",
+		inFile: "deviate-replace.yang",
+		wantRows: []string{
+			"deviate,/target/replace/default-list,replace,default,nematodes",
+			"deviate,/target/replace/type,replace,type,uint16",
+		},
+	}, {
+		desc:   "delete",
+		inFile: "deviate-delete.yang",
+		wantRows: []string{
+			"deviate,/target/delete/default,delete,default,fish",
+			"deviate,/target/delete/units,delete,units,nanofish per millenium",
+		},
+	}, {
+		desc:   "not-supported",
+		inFile: "deviate-notsupported.yang",
+		wantRows: []string{
+			"deviate,/target,not-supported,,",
+			"deviate,/target-list,not-supported,,",
+			"deviate,/a-leaf,not-supported,,",
+			"deviate,/a-leaflist,not-supported,,",
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ms := NewModules()
+			if err := ms.Parse(mustReadFile(filepath.Join("testdata", tt.inFile)), tt.inFile); err != nil {
+				t.Fatalf("cannot parse %s: %v", tt.inFile, err)
+			}
+			if errs := ms.Process(); len(errs) > 0 {
+				t.Fatalf("cannot process %s: %v", tt.inFile, errs)
+			}
+			var buf bytes.Buffer
+			if err := ms.WriteDeviationReport(&buf); err != nil {
+				t.Fatalf("WriteDeviationReport: %v", err)
+			}
+			rows := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+			if got, want := rows[0], "module,target,deviate,property,value"; got != want {
+				t.Errorf("header: got %q, want %q", got, want)
+			}
+			got := map[string]bool{}
+			for _, r := range rows[1:] {
+				got[r] = true
+			}
+			for _, r := range tt.wantRows {
+				if !got[r] {
+					t.Errorf("missing row %q, got:\n%s", r, buf.String())
+				}
+			}
+		})
+	}
+
+	// Modules without deviations have only a header.
+	ms := NewModules()
+	if err := ms.Parse(`module test { prefix "t"; namespace "urn:t"; leaf l { type string; } }`, "test.yang"); err != nil {
+		t.Fatalf("cannot parse module: %v", err)
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process module: %v", errs)
+	}
+	var buf bytes.Buffer
+	if err := ms.WriteDeviationReport(&buf); err != nil {
+		t.Fatalf("WriteDeviationReport: %v", err)
+	}
+	if got, want := buf.String(), "module,target,deviate,property,value\n"; got != want {
+		t.Errorf("WriteDeviationReport: got %q, want %q", got, want)
+	}
+}
+
+func TestDeviationsApplied(t *testing.T) {
+	ms := NewModules()
+	for name, src := range map[string]string{
+		"base.yang": `
+			module base {
+				prefix "b";
+				namespace "urn:b";
+				container c {
+					leaf added { type string; }
+					leaf replaced { type string; }
+					leaf deleted { type string; units "fish"; }
+					leaf removed { type string; }
+					leaf twice { type string; }
+					leaf untouched { type string; }
+				}
+			}`,
+		"dev.yang": `
+			module dev {
+				prefix "d";
+				namespace "urn:d";
+				import base { prefix b; }
+				deviation /b:c/b:added {
+					deviate add { config false; }
+				}
+				deviation /b:c/b:replaced {
+					deviate replace { type uint32; }
+				}
+				deviation /b:c/b:deleted {
+					deviate delete { units "fish"; }
+				}
+				deviation /b:c/b:removed {
+					deviate not-supported;
+				}
+				deviation /b:c/b:twice {
+					deviate add { default "a"; }
+				}
+			}`,
+		"dev2.yang": `
+			module dev2 {
+				prefix "d2";
+				namespace "urn:d2";
+				import base { prefix b; }
+				deviation /b:c/b:twice {
+					deviate add { units "b"; }
+				}
+			}`,
+	} {
+		if err := ms.Parse(src, name); err != nil {
+			t.Fatalf("cannot parse %s: %v", name, err)
+		}
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process modules: %v", errs)
+	}
+	c := ToEntry(ms.Modules["base"]).Dir["c"]
+
+	tests := []struct {
+		desc string
+		in   *Entry
+		want []*AppliedDeviation
+	}{{
+		desc: "add",
+		in:   c.Dir["added"],
+		want: []*AppliedDeviation{{Module: "dev", Type: DeviationAdd, Target: "/base/c/added"}},
+	}, {
+		desc: "replace",
+		in:   c.Dir["replaced"],
+		want: []*AppliedDeviation{{Module: "dev", Type: DeviationReplace, Target: "/base/c/replaced"}},
+	}, {
+		desc: "delete",
+		in:   c.Dir["deleted"],
+		want: []*AppliedDeviation{{Module: "dev", Type: DeviationDelete, Target: "/base/c/deleted"}},
+	}, {
+		desc: "not-supported is recorded on the parent",
+		in:   c,
+		want: []*AppliedDeviation{{Module: "dev", Type: DeviationNotSupported, Target: "/base/c/removed"}},
+	}, {
+		desc: "deviated by two modules",
+		in:   c.Dir["twice"],
+		want: []*AppliedDeviation{
+			{Module: "dev", Type: DeviationAdd, Target: "/base/c/twice"},
+			{Module: "dev2", Type: DeviationAdd, Target: "/base/c/twice"},
+		},
+	}, {
+		desc: "not deviated",
+		in:   c.Dir["untouched"],
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got := tt.in.DeviationsApplied()
+			// The deviations of different modules are applied in no
+			// particular order.
+			sort.SliceStable(got, func(i, j int) bool { return got[i].Module < got[j].Module })
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("DeviationsApplied (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+// This file implements the output of an Entry tree as a GraphViz DOT graph.
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// WriteDOTOptions contains options for WriteDOT.
+type WriteDOTOptions struct {
+	// IncludeRPCs includes RPCs and actions, and their input and output,
+	// in the graph, which otherwise contains only data nodes.
+	IncludeRPCs bool
+}
+
+// IsWriteDOTOpt ensures that WriteDOTOptions satisfies the WriteDOTOpt
+// interface.
+func (WriteDOTOptions) IsWriteDOTOpt() {}
+
+// WriteDOTOpt is an interface that can be used in function arguments.
+type WriteDOTOpt interface {
+	IsWriteDOTOpt()
+}
+
+// dotAttrs are the DOT attributes of each kind of node, as returned by
+// jsonSchemaKind. Configuration and state data are further distinguished by
+// their fill color.
+var dotAttrs = map[string]string{
+	"module":    `shape=folder`,
+	"container": `shape=box`,
+	"list":      `shape=box3d`,
+	"leaf":      `shape=ellipse`,
+	"leaf-list": `shape=ellipse peripheries=2`,
+	"choice":    `shape=diamond style="filled,dashed"`,
+	"case":      `shape=box style="filled,dashed,rounded"`,
+	"anydata":   `shape=note`,
+	"anyxml":    `shape=note`,
+	"rpc":       `shape=cds`,
+	"input":     `shape=box style="filled,rounded"`,
+	"output":    `shape=box style="filled,rounded"`,
+}
+
+// WriteDOT writes the subtree rooted at e to w as a GraphViz DOT directed
+// graph. Each entry is a node, identified by its path and labelled with its
+// name, and each has an edge from its parent. The shape of a node is given
+// by its kind, choice and case nodes are dashed, and configuration data is
+// filled light blue while state data is filled light grey. Nodes and edges
+// are written in the order of a depth first walk of the tree, with the
+// children of each entry sorted by name, so the output for a given schema is
+// stable. Only data nodes, and the choice and case entries that contain
+// them, are included, unless the IncludeRPCs option is given. Notifications
+// are never included.
+func (e *Entry) WriteDOT(w io.Writer, opts ...WriteDOTOpt) error {
+	var o WriteDOTOptions
+	for _, opt := range opts {
+		if do, ok := opt.(WriteDOTOptions); ok {
+			o = do
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "digraph %q {\n", e.Path())
+	var write func(e *Entry)
+	write = func(e *Entry) {
+		kind := jsonSchemaKind(e)
+		attrs, ok := dotAttrs[kind]
+		if !ok {
+			attrs = `shape=box`
+		}
+		fill := "lightgrey"
+		if e.isConfigData() {
+			fill = "lightblue"
+		}
+		if !strings.Contains(attrs, "style=") {
+			attrs += ` style=filled`
+		}
+		fmt.Fprintf(&b, "\t%q [label=%q %s fillcolor=%s];\n", e.Path(), e.Name, attrs, fill)
+
+		var children []*Entry
+		for _, ce := range e.Dir {
+			switch {
+			case ce.Kind == NotificationEntry:
+			case ce.RPC != nil && !o.IncludeRPCs:
+			default:
+				children = append(children, ce)
+			}
+		}
+		sort.Slice(children, func(i, j int) bool { return children[i].Name < children[j].Name })
+		if e.RPC != nil {
+			for _, ioe := range []*Entry{e.RPC.Input, e.RPC.Output} {
+				if ioe != nil {
+					children = append(children, ioe)
+				}
+			}
+		}
+		for _, ce := range children {
+			fmt.Fprintf(&b, "\t%q -> %q;\n", e.Path(), ce.Path())
+			write(ce)
+		}
+	}
+	write(e)
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,142 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteDOT(t *testing.T) {
+	ms := NewModules()
+	if err := ms.Parse(`
+		module test {
+			yang-version 1.1;
+			prefix "t";
+			namespace "urn:t";
+			container c {
+				leaf name { type string; }
+				leaf-list tags { type string; }
+				list l {
+					key "k";
+					leaf k { type string; }
+					action reset;
+				}
+				choice ch {
+					case a { leaf a1 { type string; } }
+				}
+				container state {
+					config false;
+					leaf counter { type uint64; }
+				}
+			}
+			rpc r {
+				input { leaf in { type string; } }
+			}
+			notification n {
+				leaf note { type string; }
+			}
+		}`, "test.yang"); err != nil {
+		t.Fatalf("cannot parse module: %v", err)
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process module: %v", errs)
+	}
+	root := ToEntry(ms.Modules["test"])
+
+	tests := []struct {
+		desc    string
+		in      *Entry
+		inOpts  []WriteDOTOpt
+		want    []string
+		notWant []string
+	}{{
+		desc: "data nodes",
+		in:   root,
+		want: []string{
+			`digraph "/test" {`,
+			`"/test" [label="test" shape=folder style=filled fillcolor=lightblue];`,
+			`"/test/c" [label="c" shape=box style=filled fillcolor=lightblue];`,
+			`"/test/c/name" [label="name" shape=ellipse style=filled fillcolor=lightblue];`,
+			`"/test/c/tags" [label="tags" shape=ellipse peripheries=2 style=filled fillcolor=lightblue];`,
+			`"/test/c/l" [label="l" shape=box3d style=filled fillcolor=lightblue];`,
+			`"/test/c/ch" [label="ch" shape=diamond style="filled,dashed" fillcolor=lightblue];`,
+			`"/test/c/ch/a" [label="a" shape=box style="filled,dashed,rounded" fillcolor=lightblue];`,
+			`"/test/c/state/counter" [label="counter" shape=ellipse style=filled fillcolor=lightgrey];`,
+			`"/test" -> "/test/c";`,
+			`"/test/c" -> "/test/c/ch";`,
+			`"/test/c/ch" -> "/test/c/ch/a";`,
+			`"/test/c/ch/a" -> "/test/c/ch/a/a1";`,
+			`"/test/c/state" -> "/test/c/state/counter";`,
+		},
+		notWant: []string{`"/test/r"`, `"/test/n"`, `"/test/c/l/reset"`},
+	}, {
+		desc:   "with rpcs",
+		in:     root,
+		inOpts: []WriteDOTOpt{WriteDOTOptions{IncludeRPCs: true}},
+		want: []string{
+			`"/test/r" [label="r" shape=cds style=filled fillcolor=lightgrey];`,
+			`"/test/r/input" [label="input" shape=box style="filled,rounded" fillcolor=lightgrey];`,
+			`"/test" -> "/test/r";`,
+			`"/test/r" -> "/test/r/input";`,
+			`"/test/r/input" -> "/test/r/input/in";`,
+			`"/test/c/l" -> "/test/c/l/reset";`,
+			`"/test/c/l/reset" [label="reset" shape=cds style=filled fillcolor=lightgrey];`,
+		},
+		notWant: []string{`"/test/n"`},
+	}, {
+		desc: "subtree",
+		in:   root.Dir["c"].Dir["state"],
+		want: []string{
+			`digraph "/test/c/state" {`,
+			`"/test/c/state" [label="state" shape=box style=filled fillcolor=lightgrey];`,
+			`"/test/c/state" -> "/test/c/state/counter";`,
+		},
+		notWant: []string{`"/test/c/name"`},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := tt.in.WriteDOT(&buf, tt.inOpts...); err != nil {
+				t.Fatalf("WriteDOT: %v", err)
+			}
+			got := buf.String()
+			for _, s := range tt.want {
+				if !strings.Contains(got, s) {
+					t.Errorf("WriteDOT: output does not contain %s, got:\n%s", s, got)
+				}
+			}
+			for _, s := range tt.notWant {
+				if strings.Contains(got, s) {
+					t.Errorf("WriteDOT: output contains %s, got:\n%s", s, got)
+				}
+			}
+			if !strings.HasSuffix(got, "}\n") {
+				t.Errorf("WriteDOT: output is not terminated, got:\n%s", got)
+			}
+
+			// The output is deterministic.
+			var again bytes.Buffer
+			if err := tt.in.WriteDOT(&again, tt.inOpts...); err != nil {
+				t.Fatalf("WriteDOT: %v", err)
+			}
+			if again.String() != got {
+				t.Errorf("WriteDOT: output differs between calls")
+			}
+		})
+	}
+}
@@ -117,6 +117,13 @@ type Entry struct {
 	Augmented  []*Entry                   `json:",omitempty"` // Augments merged into this entry.
 	Deviations []*DeviatedEntry           `json:"-"`          // Deviations associated with this entry.
 	Deviate    map[deviationType][]*Entry `json:"-"`
+	// RemovedByDeviation lists the paths of the entries that were removed
+	// from the schema by the deviate not-supported statements of this
+	// entry. It is set only on module entries, by ApplyDeviate.
+	RemovedByDeviation []string `json:",omitempty"`
+	// deviationsApplied records the deviate statements applied to this
+	// entry by ApplyDeviate.
+	deviationsApplied []*AppliedDeviation
 	// deviationPresence tracks whether certain attributes for a DeviateEntry-type
 	// Entry have been given deviation values.
 	deviatePresence deviationPresence
@@ -139,6 +146,13 @@ type Entry struct {
 }
 
 // An RPCEntry contains information related to an RPC Node.
+//
+// Once ToEntry has built the entry of an rpc or action, Input and Output are
+// never nil: an rpc or action that has no input or output statement is given
+// an empty one, with no Node, so that augments may target it. Whether an rpc
+// or action has an input or output is therefore given by whether it has
+// children, not by whether it is nil, as an input or output statement must
+// define at least one data node.
 type RPCEntry struct {
 	Input  *Entry
 	Output *Entry
@@ -220,6 +234,86 @@ func (e *Entry) IsList() bool {
 	return e.IsDir() && e.ListAttr != nil
 }
 
+// IsSingletonList returns true if e is a list with a max-elements of 1, and
+// so has at most one instance, much like an optional container. The
+// max-elements statement is parsed, and "unbounded" handled, when e is
+// created, so a deviation of max-elements is also reflected.
+func (e *Entry) IsSingletonList() bool {
+	return e.IsList() && e.ListAttr.MaxElements == 1
+}
+
+// IsOrderedByUser returns true if e is a list or leaf-list that is
+// "ordered-by user", as given by ListAttr.OrderedByUser, and false otherwise,
+// including if e is not a list or leaf-list.
+func (e *Entry) IsOrderedByUser() bool {
+	return e.ListAttr != nil && e.ListAttr.OrderedByUser
+}
+
+// Presence returns the argument of the presence statement of e, which
+// describes the meaning of the container's existence, and true if e is a
+// presence container. It returns "" and false if e is a container without a
+// presence statement, or is not a container.
+func (e *Entry) Presence() (string, bool) {
+	if !e.IsContainer() {
+		return "", false
+	}
+	for _, v := range e.Extra["presence"] {
+		if v, ok := v.(*Value); ok {
+			return v.Name, true
+		}
+	}
+	return "", false
+}
+
+// IsKey returns true if e is a leaf that is named within the key statement
+// of its parent list. Each leaf of a composite key is a key. Key leaves are
+// always direct children of the list, so leaves within a choice of the list,
+// or within a container that is not a list, are not keys.
+func (e *Entry) IsKey() bool {
+	if !e.IsLeaf() || e.Parent == nil || !e.Parent.IsList() {
+		return false
+	}
+	for _, name := range e.Parent.keyNames() {
+		if name == e.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// keyNames returns the names of the keys of the list e, in the order of its
+// key statement, with any prefixes removed.
+func (e *Entry) keyNames() []string {
+	var names []string
+	for _, k := range strings.Fields(e.Key) {
+		_, name := getPrefix(k)
+		names = append(names, name)
+	}
+	return names
+}
+
+// KeyEntries returns the key leaves of the list e, in the order in which they
+// are named by its key statement, or nil if e has no keys. An error is
+// returned if e is not a list, or if a key does not name a leaf that is a
+// child of e.
+func (e *Entry) KeyEntries() ([]*Entry, error) {
+	if !e.IsList() {
+		return nil, fmt.Errorf("%s: not a list", e.Path())
+	}
+	var keys []*Entry
+	for _, name := range e.keyNames() {
+		ke := e.Dir[name]
+		switch {
+		case ke == nil:
+			return nil, fmt.Errorf("%s: key %s is not a child of the list", e.Path(), name)
+		case !ke.IsLeaf():
+			return nil, fmt.Errorf("%s: key %s is not a leaf", e.Path(), name)
+		}
+		keys = append(keys, ke)
+	}
+	return keys, nil
+}
+
 // IsContainer returns true if e is a container.
 func (e *Entry) IsContainer() bool {
 	return e.Kind == DirectoryEntry && e.ListAttr == nil
@@ -235,6 +329,28 @@ func (e *Entry) IsCase() bool {
 	return e.Kind == CaseEntry
 }
 
+// IsDataNode returns true if e is a container, list, leaf, leaf-list, anydata
+// or anyxml node that is addressable within the data tree. Nodes within the
+// input or output of an RPC are not data nodes. Nodes within a notification
+// are data nodes, as they are addressed by subscriptions to the notification,
+// though the notification itself is not.
+func (e *Entry) IsDataNode() bool {
+	if e.Parent == nil || e.RPC != nil {
+		return false
+	}
+	switch e.Kind {
+	case LeafEntry, DirectoryEntry, AnyDataEntry, AnyXMLEntry:
+	default:
+		return false
+	}
+	for p := e.Parent; p != nil; p = p.Parent {
+		if p.Kind == InputEntry || p.Kind == OutputEntry {
+			return false
+		}
+	}
+	return true
+}
+
 // Print prints e to w in human readable form.
 func (e *Entry) Print(w io.Writer) {
 	if e.Description != "" {
@@ -425,47 +541,161 @@ func (e *Entry) delete(key string) {
 	delete(e.Dir, key)
 }
 
-// GetWhenXPath returns the when XPath statement of e if able.
+// GetWhenXPath returns the when XPath statement of e if able. The when
+// statement of e is that of the node from which e was created, or for an
+// entry created from a grouping by a uses statement, that of the uses
+// statement. The when statements of the uses and augment statements that
+// added e to its parent are not returned, although they are also within
+// e.Extra["when"].
 func (e *Entry) GetWhenXPath() (string, bool) {
-	switch n := e.Node.(type) {
-	case *Container:
-		if n.When != nil && n.When.Statement() != nil {
-			return n.When.Statement().Arg()
-		}
-	case *Leaf:
-		if n.When != nil && n.When.Statement() != nil {
-			return n.When.Statement().Arg()
+	if v := e.whenValue(); v != nil {
+		return v.Statement().Arg()
+	}
+	return "", false
+}
+
+// whenValue returns e's own when statement from e.Extra["when"], or nil if
+// e has none. ToEntry stores the when statement of every node that can
+// have one within Extra, which merge extends with the when statements of
+// the uses and augment statements that add the node to its parent.
+func (e *Entry) whenValue() *Value {
+	if e.Node == nil {
+		return nil
+	}
+	for _, w := range e.Extra["when"] {
+		v, ok := w.(*Value)
+		if !ok || v.Parent == nil || v.Statement() == nil {
+			continue
 		}
-	case *LeafList:
-		if n.When != nil && n.When.Statement() != nil {
-			return n.When.Statement().Arg()
+		if _, ok := v.Parent.(*Uses); ok {
+			if _, ok := e.Node.(*Grouping); ok {
+				return v
+			}
+			continue
 		}
-	case *List:
-		if n.When != nil && n.When.Statement() != nil {
-			return n.When.Statement().Arg()
+		// A leaf-list entry is created from a leaf that shares the
+		// statement of the leaf-list, so the statements are compared
+		// rather than the nodes.
+		if v.Parent.Statement() == e.Node.Statement() {
+			return v
 		}
-	case *Choice:
-		if n.When != nil && n.When.Statement() != nil {
-			return n.When.Statement().Arg()
+	}
+	return nil
+}
+
+// GetWhenXPathModules returns the modules referenced by the prefixes used
+// within e's when statement, keyed by prefix. The prefixes are resolved in
+// the context of the module in which the when statement was defined, which
+// for a node added by an augment, or by a uses of a grouping from another
+// module, is not the module of the node's parent. An error is returned if
+// any prefix cannot be resolved.
+func (e *Entry) GetWhenXPathModules() (map[string]*Module, error) {
+	xpath, ok := e.GetWhenXPath()
+	if !ok {
+		return nil, nil
+	}
+	if e.Node == nil || RootNode(e.Node) == nil {
+		return nil, fmt.Errorf("%s: cannot resolve prefixes of when statement %q without a module", e.Path(), xpath)
+	}
+	mods := map[string]*Module{}
+	for _, pfx := range xpathPrefixes(xpath) {
+		m := FindModuleByPrefix(e.Node, pfx)
+		if m == nil {
+			return nil, fmt.Errorf("%s: unknown prefix %q in when statement %q of %s", Source(e.Node), pfx, xpath, RootNode(e.Node).Name)
 		}
-	case *Case:
-		if n.When != nil && n.When.Statement() != nil {
-			return n.When.Statement().Arg()
+		mods[pfx] = m
+	}
+	return mods, nil
+}
+
+// WhenAlwaysFalse returns true if e can be statically determined to never be
+// present in the data tree, given that the features named in features are
+// enabled or disabled. This is the case if the when statement of e is a
+// constant false expression (e.g., "false()" or "not(true())"), or if an
+// if-feature statement of e names a single feature that is disabled within
+// features. Features are looked up by their name both with and without their
+// prefix. WhenAlwaysFalse is conservative: if the result cannot be
+// determined, such as for a when statement that refers to other nodes, an
+// if-feature statement with a boolean expression, or a feature that is not
+// within features, it returns false.
+func (e *Entry) WhenAlwaysFalse(features map[string]bool) bool {
+	if xpath, ok := e.GetWhenXPath(); ok && xpathConstantFalse(xpath) {
+		return true
+	}
+	for _, v := range e.Extra["if-feature"] {
+		f, ok := v.(*Value)
+		if !ok || strings.ContainsAny(f.Name, " \t\n()") {
+			continue
 		}
-	case *AnyXML:
-		if n.When != nil && n.When.Statement() != nil {
-			return n.When.Statement().Arg()
+		enabled, ok := features[f.Name]
+		if !ok {
+			_, name := getPrefix(f.Name)
+			enabled, ok = features[name]
 		}
-	case *AnyData:
-		if n.When != nil && n.When.Statement() != nil {
-			return n.When.Statement().Arg()
+		if ok && !enabled {
+			return true
 		}
-	case *Augment:
-		if n.When != nil && n.When.Statement() != nil {
-			return n.When.Statement().Arg()
+	}
+	return false
+}
+
+// xpathConstantFalse returns true if the XPath expression s is a constant
+// expression that always evaluates to false. Only a few simple forms are
+// recognized.
+func xpathConstantFalse(s string) bool {
+	s = strings.Join(strings.Fields(s), "")
+	for strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		s = s[1 : len(s)-1]
+	}
+	switch s {
+	case "false()", "not(true())", "0", "''", `""`:
+		return true
+	}
+	return false
+}
+
+// checkWhenPrefixes calls f with the error returned by GetWhenXPathModules
+// for every entry in the tree e, including its augments, that has a when
+// statement with an unresolvable prefix. seen records the entries already
+// visited, as augmented entries are reachable from more than one place.
+func (e *Entry) checkWhenPrefixes(seen map[*Entry]bool, f func(error)) {
+	if e == nil || seen[e] {
+		return
+	}
+	seen[e] = true
+	if _, err := e.GetWhenXPathModules(); err != nil {
+		f(err)
+	}
+	for _, ce := range e.Dir {
+		ce.checkWhenPrefixes(seen, f)
+	}
+	for _, a := range e.Augments {
+		a.checkWhenPrefixes(seen, f)
+	}
+	for _, a := range e.Augmented {
+		a.checkWhenPrefixes(seen, f)
+	}
+	if e.RPC != nil {
+		e.RPC.Input.checkWhenPrefixes(seen, f)
+		e.RPC.Output.checkWhenPrefixes(seen, f)
+	}
+}
+
+// xpathPrefixes returns the prefixes of the names used in the XPath
+// expression s, in the order in which they first appear. String literals and
+// axis specifiers (e.g., "child::") are skipped.
+func xpathPrefixes(s string) []string {
+	// The names before an unterminated literal are still checked.
+	toks, _ := xpathTokens(s)
+	var pfxs []string
+	seen := map[string]bool{}
+	for _, t := range toks {
+		if pfx := t.prefix(); pfx != "" && !seen[pfx] {
+			seen[pfx] = true
+			pfxs = append(pfxs, pfx)
 		}
 	}
-	return "", false
+	return pfxs
 }
 
 // deviationType specifies an enumerated value covering the different substatements
@@ -517,6 +747,26 @@ type DeviatedEntry struct {
 	*Entry
 }
 
+// An AppliedDeviation records a deviate statement that ApplyDeviate applied
+// to an entry.
+type AppliedDeviation struct {
+	Module string        // Module is the name of the module or submodule defining the deviation.
+	Type   deviationType // Type is the type of the deviate statement.
+	Target string        // Target is the schema path of the deviated entry.
+}
+
+// DeviationsApplied returns the deviate statements that were applied to e by
+// ApplyDeviate, in the order in which they were applied. The deviate
+// not-supported statements that removed a child of e are recorded on e, as
+// the removed child is no longer within the schema. Nil is returned if no
+// deviations were applied to e.
+func (e *Entry) DeviationsApplied() []*AppliedDeviation {
+	if len(e.deviationsApplied) == 0 {
+		return nil
+	}
+	return append([]*AppliedDeviation{}, e.deviationsApplied...)
+}
+
 // semCheckMaxElements checks whether the max-element argument is valid, and returns the specified value.
 func semCheckMaxElements(v *Value) (uint64, error) {
 	if v == nil || v.Name == "unbounded" {
@@ -612,6 +862,12 @@ func ToEntry(n Node) (e *Entry) {
 		addExtraKeywordsToLeafEntry(n, e)
 		e.Mandatory, err = tristateValue(s.Mandatory)
 		e.addError(err)
+		// RFC 7950 section 7.6.4: a default statement must not be present
+		// in a leaf that is mandatory. A default inherited from a typedef
+		// is permitted, as it is ignored.
+		if s.Default != nil && e.Mandatory == TSTrue {
+			e.addError(fmt.Errorf("%s: leaf %s has both a default and mandatory true", Source(n), s.Name))
+		}
 		return e
 	case *LeafList:
 		// Create the equivalent leaf element that we are a list of.
@@ -733,7 +989,12 @@ func ToEntry(n Node) (e *Entry) {
 			}
 		case "action":
 			for _, r := range fv.Interface().([]*Action) {
-				e.add(r.Name, ToEntry(r))
+				e.addError(checkYang11(r))
+				a := ToEntry(r)
+				// As for an rpc, an action without input or
+				// output is still an operation.
+				a.addOperationChildren()
+				e.add(r.Name, a)
 			}
 		case "augment":
 			for _, a := range fv.Interface().([]*Augment) {
@@ -743,6 +1004,7 @@ func ToEntry(n Node) (e *Entry) {
 			}
 		case "anydata":
 			for _, a := range fv.Interface().([]*AnyData) {
+				e.addError(checkYang11(a))
 				e.add(a.Name, ToEntry(a))
 			}
 		case "anyxml":
@@ -838,14 +1100,9 @@ func ToEntry(n Node) (e *Entry) {
 			// seems fine to ignore them for now, we are
 			// just interested in the tree structure.
 			for _, r := range fv.Interface().([]*RPC) {
-				switch rpc := ToEntry(r); {
-				case rpc.RPC == nil:
-					// When "rpc" has no "input" or "output" children
-					rpc.RPC = &RPCEntry{}
-					fallthrough
-				default:
-					e.add(r.Name, rpc)
-				}
+				rpc := ToEntry(r)
+				rpc.addOperationChildren()
+				e.add(r.Name, rpc)
 			}
 
 		case "input":
@@ -1106,7 +1363,11 @@ func (e *Entry) Augment(addErrors bool) (processed, skipped int) {
 		target := a.Find(a.Name)
 		if target == nil {
 			if addErrors {
-				e.errorf("%s: augment %s not found", Source(a.Node), a.Name)
+				e.addError(&augmentNotFoundError{
+					source:      Source(a.Node),
+					target:      a.Name,
+					conditional: len(a.Extra["if-feature"]) > 0,
+				})
 			}
 			skipped++
 			unapplied = append(unapplied, a)
@@ -1124,6 +1385,17 @@ func (e *Entry) Augment(addErrors bool) (processed, skipped int) {
 	return processed, skipped
 }
 
+// DeviatedRemovals returns the paths of the entries that were removed from
+// the schema by the deviate not-supported statements of the module that e
+// belongs to. Since removed entries are no longer found in the tree, this
+// allows the unsupported parts of a schema to be reported.
+func (e *Entry) DeviatedRemovals() []string {
+	for e.Parent != nil {
+		e = e.Parent
+	}
+	return append([]string{}, e.RemovedByDeviation...)
+}
+
 // ApplyDeviate walks the deviations within the supplied entry, and applies them to the
 // schema.
 func (e *Entry) ApplyDeviate(deviateOpts ...DeviateOpt) []error {
@@ -1138,8 +1410,10 @@ func (e *Entry) ApplyDeviate(deviateOpts ...DeviateOpt) []error {
 
 		for dt, dv := range d.Deviate {
 			for _, devSpec := range dv {
+				applied := &AppliedDeviation{Module: e.Name, Type: dt, Target: deviatedNode.Path()}
 				switch dt {
 				case DeviationAdd, DeviationReplace:
+					deviatedNode.deviationsApplied = append(deviatedNode.deviationsApplied, applied)
 					if devSpec.Config != TSUnset {
 						deviatedNode.Config = devSpec.Config
 					}
@@ -1190,6 +1464,31 @@ func (e *Entry) ApplyDeviate(deviateOpts ...DeviateOpt) []error {
 						deviatedNode.Type = devSpec.Type
 					}
 
+					// must and unique statements may only be added or
+					// deleted: https://tools.ietf.org/html/rfc7950#section-7.20.3.2
+					if dt == DeviationReplace {
+						for _, keyword := range []string{"must", "unique"} {
+							if len(devSpec.Extra[keyword]) > 0 {
+								appendErr(fmt.Errorf("%s: tried to deviate replace a %s statement, which may only be added or deleted", Source(e.Node), keyword))
+							}
+						}
+						continue
+					}
+
+					if musts := devSpec.Extra["must"]; len(musts) > 0 {
+						// The existing statements are kept, and copied
+						// as they may be shared.
+						deviatedNode.Extra["must"] = append(append([]interface{}{}, deviatedNode.Extra["must"]...), musts...)
+					}
+
+					if uniques := devSpec.Extra["unique"]; len(uniques) > 0 {
+						if !deviatedNode.IsList() {
+							appendErr(fmt.Errorf("tried to deviate unique on a non-list type %s", deviatedNode.Kind))
+							continue
+						}
+						deviatedNode.Extra["unique"] = append(append([]interface{}{}, deviatedNode.Extra["unique"]...), uniques...)
+					}
+
 				case DeviationNotSupported:
 					dp := deviatedNode.Parent
 					if dp == nil {
@@ -1197,9 +1496,12 @@ func (e *Entry) ApplyDeviate(deviateOpts ...DeviateOpt) []error {
 						continue
 					}
 					if !hasIgnoreDeviateNotSupported(deviateOpts) {
+						e.RemovedByDeviation = append(e.RemovedByDeviation, deviatedNode.Path())
 						dp.delete(deviatedNode.Name)
+						dp.deviationsApplied = append(dp.deviationsApplied, applied)
 					}
 				case DeviationDelete:
+					deviatedNode.deviationsApplied = append(deviatedNode.deviationsApplied, applied)
 					if devSpec.Config != TSUnset {
 						deviatedNode.Config = TSUnset
 					}
@@ -1232,7 +1534,8 @@ func (e *Entry) ApplyDeviate(deviateOpts ...DeviateOpt) []error {
 						if deviatedNode.ListAttr.MinElements != devSpec.ListAttr.MinElements {
 							// Argument value must match:
 							// https://tools.ietf.org/html/rfc7950#section-7.20.3.2
-							appendErr(fmt.Errorf("min-element value %d differs from deviation's min-element value %d for entry %v", devSpec.ListAttr.MinElements, deviatedNode.ListAttr.MinElements, d.DeviatedPath))
+							appendErr(fmt.Errorf("min-element value %d differs from deviation's min-element value %d for entry %v", deviatedNode.ListAttr.MinElements, devSpec.ListAttr.MinElements, d.DeviatedPath))
+							continue
 						}
 						deviatedNode.ListAttr.MinElements = 0
 					}
@@ -1243,11 +1546,24 @@ func (e *Entry) ApplyDeviate(deviateOpts ...DeviateOpt) []error {
 							continue
 						}
 						if deviatedNode.ListAttr.MaxElements != devSpec.ListAttr.MaxElements {
-							appendErr(fmt.Errorf("max-element value %d differs from deviation's max-element value %d for entry %v", devSpec.ListAttr.MaxElements, deviatedNode.ListAttr.MaxElements, d.DeviatedPath))
+							appendErr(fmt.Errorf("max-element value %d differs from deviation's max-element value %d for entry %v", deviatedNode.ListAttr.MaxElements, devSpec.ListAttr.MaxElements, d.DeviatedPath))
+							continue
 						}
 						deviatedNode.ListAttr.MaxElements = math.MaxUint64
 					}
 
+					for _, keyword := range []string{"must", "unique"} {
+						for _, v := range devSpec.Extra[keyword] {
+							arg := extraArgument(v)
+							remaining, ok := deleteExtraArgument(deviatedNode.Extra[keyword], arg)
+							if !ok {
+								appendErr(fmt.Errorf("%s: tried to deviate delete a %s statement %q that doesn't exist", Source(e.Node), keyword, arg))
+								continue
+							}
+							deviatedNode.Extra[keyword] = remaining
+						}
+					}
+
 				default:
 					appendErr(fmt.Errorf("invalid deviation type %s", dt))
 				}
@@ -1258,6 +1574,33 @@ func (e *Entry) ApplyDeviate(deviateOpts ...DeviateOpt) []error {
 	return errs
 }
 
+// extraArgument returns the argument of v, a must or unique statement within
+// the Extra of an entry. The whitespace separating the descendant schema node
+// identifiers of a unique statement is normalised.
+func extraArgument(v interface{}) string {
+	switch v := v.(type) {
+	case *Must:
+		return v.Name
+	case *Value:
+		return strings.Join(strings.Fields(v.Name), " ")
+	}
+	return ""
+}
+
+// deleteExtraArgument returns a copy of vals without the first statement
+// whose argument, as returned by extraArgument, is arg, and true. If there is
+// no such statement, vals and false are returned. vals is not modified, as it
+// may be shared by the entries duplicated from the same node.
+func deleteExtraArgument(vals []interface{}, arg string) ([]interface{}, bool) {
+	for i, v := range vals {
+		if extraArgument(v) == arg {
+			remaining := append([]interface{}{}, vals[:i]...)
+			return append(remaining, vals[i+1:]...), true
+		}
+	}
+	return vals, false
+}
+
 // FixChoice inserts missing Case entries for non-case entries within a choice
 // entry.
 func (e *Entry) FixChoice() {
@@ -1289,6 +1632,199 @@ func (e *Entry) FixChoice() {
 	}
 }
 
+// checkYang11 returns an error if n, an anydata or action statement, is
+// defined in a module or submodule that does not declare yang-version 1.1, as
+// these statements were introduced by RFC7950. A module without a
+// yang-version statement is a YANG 1.0 module.
+func checkYang11(n Node) error {
+	m := RootNode(n)
+	if m == nil || m.Version() == "1.1" {
+		return nil
+	}
+	return fmt.Errorf("%s: %s %s is only allowed in yang-version 1.1 modules", Source(n), n.Kind(), n.NName())
+}
+
+// checkChoiceDefaults calls f with an error for every choice in the tree e
+// whose default statement does not name one of its cases, skipping entries
+// that are in seen. It must be called after FixChoice, so that shorthand
+// cases exist.
+func (e *Entry) checkChoiceDefaults(seen map[*Entry]bool, f func(error)) {
+	if e == nil || seen[e] {
+		return
+	}
+	seen[e] = true
+	if e.IsChoice() && len(e.Default) > 0 {
+		name := e.Default[0]
+		if e.Node != nil {
+			name = trimLocalPrefix(e.Node, name)
+		}
+		if ce := e.Dir[name]; ce == nil || !ce.IsCase() {
+			f(fmt.Errorf("%s: default %q of choice %s is not one of its cases", Source(e.Node), e.Default[0], e.Path()))
+		}
+	}
+	for _, ce := range e.Dir {
+		ce.checkChoiceDefaults(seen, f)
+	}
+	if e.RPC != nil {
+		e.RPC.Input.checkChoiceDefaults(seen, f)
+		e.RPC.Output.checkChoiceDefaults(seen, f)
+	}
+}
+
+// checkActionPlacement calls f with an error for every action in the tree e
+// that has an rpc, action or notification as an ancestor, which RFC 7950
+// section 7.15 forbids, skipping entries that are in seen. within is the
+// closest such ancestor of e, or nil if there is none. It must be called
+// after augments are applied, as they may add actions.
+func (e *Entry) checkActionPlacement(within *Entry, seen map[*Entry]bool, f func(error)) {
+	if e == nil || seen[e] {
+		return
+	}
+	seen[e] = true
+	if e.Node != nil {
+		switch kind := e.Node.Kind(); {
+		case kind == "action" && within != nil:
+			f(fmt.Errorf("%s: action %s is not allowed within %s %s", Source(e.Node), e.Name, within.Node.Kind(), within.Path()))
+		case kind == "action", kind == "rpc", kind == "notification":
+			within = e
+		}
+	}
+	for _, ce := range e.Dir {
+		ce.checkActionPlacement(within, seen, f)
+	}
+	if e.RPC != nil {
+		e.RPC.Input.checkActionPlacement(within, seen, f)
+		e.RPC.Output.checkActionPlacement(within, seen, f)
+	}
+}
+
+// Musts returns the must statements of e, in the order they were given,
+// including those of nodes instantiated from groupings and added by augments,
+// which keep the must statements of their definition. The error-message and
+// error-app-tag of each are available as its ErrorMessage and ErrorAppTag.
+// Nil is returned if e has no must statements.
+func (e *Entry) Musts() []*Must {
+	var musts []*Must
+	for _, m := range e.Extra["must"] {
+		if m, ok := m.(*Must); ok {
+			musts = append(musts, m)
+		}
+	}
+	return musts
+}
+
+// Unique returns the arguments of the unique statements of the list e, in
+// the order they were given, each split into the descendant schema node
+// identifiers that it contains, e.g., the statement unique "ip port" is
+// returned as []string{"ip", "port"}. Nil is returned if e has no unique
+// statements. The identifiers are validated by Process, which reports those
+// that do not refer to a leaf within e as errors of e.
+func (e *Entry) Unique() [][]string {
+	var unique [][]string
+	for _, u := range e.Extra["unique"] {
+		if v, ok := u.(*Value); ok {
+			unique = append(unique, strings.Fields(v.Name))
+		}
+	}
+	return unique
+}
+
+// checkUnique adds an error to every list in the tree e that has a unique
+// statement naming a descendant schema node identifier that does not refer
+// to a leaf within the list, skipping entries that are in seen. It must be
+// called after FixChoice, so that shorthand cases exist.
+func (e *Entry) checkUnique(seen map[*Entry]bool) {
+	if e == nil || seen[e] {
+		return
+	}
+	seen[e] = true
+	if e.IsList() {
+		for _, u := range e.Extra["unique"] {
+			v, ok := u.(*Value)
+			if !ok {
+				continue
+			}
+			for _, id := range strings.Fields(v.Name) {
+				if err := e.uniqueLeaf(id); err != nil {
+					e.addError(fmt.Errorf("%s: unique %q of list %s: %v", Source(v), v.Name, e.Name, err))
+				}
+			}
+		}
+	}
+	for _, ce := range e.Dir {
+		ce.checkUnique(seen)
+	}
+	if e.RPC != nil {
+		e.RPC.Input.checkUnique(seen)
+		e.RPC.Output.checkUnique(seen)
+	}
+}
+
+// checkKeys calls f with an error for every list in the tree e, skipping
+// entries in seen, that violates the constraints on keys of RFC7950 section
+// 7.8.2: each key must name a leaf that is a child of the list, and must not
+// be named more than once, the keys of a list that is configuration data must
+// not be config false, and a list that is configuration data must have a key.
+func (e *Entry) checkKeys(seen map[*Entry]bool, f func(error)) {
+	if e == nil || seen[e] {
+		return
+	}
+	seen[e] = true
+	if e.IsList() {
+		config := e.isConfigData()
+		if config && e.Key == "" {
+			f(fmt.Errorf("%s: list %s is configuration data but has no key", Source(e.Node), e.Name))
+		}
+		named := map[string]bool{}
+		for _, name := range e.keyNames() {
+			ke := e.Dir[name]
+			switch {
+			case named[name]:
+				f(fmt.Errorf("%s: key %s of list %s is named more than once", Source(e.Node), name, e.Name))
+			case ke == nil:
+				f(fmt.Errorf("%s: key %s of list %s is not a child of the list", Source(e.Node), name, e.Name))
+			case !ke.IsLeaf():
+				f(fmt.Errorf("%s: key %s of list %s is not a leaf", Source(e.Node), name, e.Name))
+			case config && ke.ReadOnly():
+				f(fmt.Errorf("%s: key %s of list %s is config false, but the list is configuration data", Source(ke.Node), name, e.Name))
+			}
+			named[name] = true
+		}
+	}
+	for _, ce := range e.Dir {
+		ce.checkKeys(seen, f)
+	}
+	if e.RPC != nil {
+		e.RPC.Input.checkKeys(seen, f)
+		e.RPC.Output.checkKeys(seen, f)
+	}
+}
+
+// uniqueLeaf returns an error if id, a descendant schema node identifier
+// from a unique statement of the list e, does not refer to a leaf within e.
+// The identifier may name choice and case entries, or omit them.
+func (e *Entry) uniqueLeaf(id string) error {
+	if strings.HasPrefix(id, "/") {
+		return fmt.Errorf("%s is not a descendant schema node identifier", id)
+	}
+	ce := e
+	for _, elem := range strings.Split(id, "/") {
+		_, name := getPrefix(elem)
+		next := ce.Dir[name]
+		if next == nil {
+			next = ce.findDataChild(name)
+		}
+		if next == nil {
+			return fmt.Errorf("%s does not exist", id)
+		}
+		ce = next
+	}
+	if !ce.IsLeaf() {
+		return fmt.Errorf("%s is not a leaf", id)
+	}
+	return nil
+}
+
 // ReadOnly returns true if e is a read-only variable (config == false).
 // If Config is unset in e, then false is returned if e has no parent,
 // otherwise the value parent's ReadOnly is returned.
@@ -1306,6 +1842,20 @@ func (e *Entry) ReadOnly() bool {
 	}
 }
 
+// isConfigData returns true if e is configuration data, i.e., it is not
+// config false and is not within an RPC or notification.
+func (e *Entry) isConfigData() bool {
+	if e.ReadOnly() {
+		return false
+	}
+	for p := e; p != nil; p = p.Parent {
+		if p.RPC != nil || p.Kind == InputEntry || p.Kind == OutputEntry || p.Kind == NotificationEntry {
+			return false
+		}
+	}
+	return true
+}
+
 // Find finds the Entry named by name relative to e.
 func (e *Entry) Find(name string) *Entry {
 	if e == nil || name == "" {
@@ -1317,25 +1867,8 @@ func (e *Entry) Find(name string) *Entry {
 	// and we need to find our parent.
 	if parts[0] == "" {
 		parts = parts[1:]
-		contextNode := e.Node
-		for e.Parent != nil {
-			e = e.Parent
-		}
-		if prefix, _ := getPrefix(parts[0]); prefix != "" {
-			mod := FindModuleByPrefix(contextNode, prefix)
-			if mod == nil {
-				e.addError(fmt.Errorf("cannot find module giving prefix %q within context entry %q", prefix, e.Path()))
-				return nil
-			}
-			m := module(mod)
-			if m == nil {
-				e.addError(fmt.Errorf("cannot find which module %q belongs to within context entry %q",
-					mod.NName(), e.Path()))
-				return nil
-			}
-			if m != e.Node.(*Module) {
-				e = ToEntry(m)
-			}
+		if e = e.absoluteRoot(parts[0]); e == nil {
+			return nil
 		}
 	}
 
@@ -1350,22 +1883,8 @@ func (e *Entry) Find(name string) *Entry {
 			_, part = getPrefix(part)
 			switch part {
 			case "input":
-				if e.RPC.Input == nil {
-					e.RPC.Input = &Entry{
-						Name: "input",
-						Kind: InputEntry,
-						Dir:  make(map[string]*Entry),
-					}
-				}
 				e = e.RPC.Input
 			case "output":
-				if e.RPC.Output == nil {
-					e.RPC.Output = &Entry{
-						Name: "output",
-						Kind: OutputEntry,
-						Dir:  make(map[string]*Entry),
-					}
-				}
 				e = e.RPC.Output
 			}
 		default:
@@ -1382,6 +1901,196 @@ func (e *Entry) Find(name string) *Entry {
 	return e
 }
 
+// addOperationChildren gives the RPC or action e an RPCEntry and an empty
+// input and output, if it does not define them, so that the tree is complete
+// when it is built. In particular, augments and deviations may target the
+// implicit input or output of e, and Find need not change the tree, so that it
+// may be called concurrently.
+func (e *Entry) addOperationChildren() {
+	if e.RPC == nil {
+		e.RPC = &RPCEntry{}
+	}
+	if e.RPC.Input == nil {
+		e.RPC.Input = newOperationChild(e, InputEntry)
+	}
+	if e.RPC.Output == nil {
+		e.RPC.Output = newOperationChild(e, OutputEntry)
+	}
+}
+
+// definedOperationChildren returns the input and output of the RPC or action
+// e that have children, omitting the empty ones that addOperationChildren
+// gives an operation that does not define them.
+func (e *Entry) definedOperationChildren() []*Entry {
+	var children []*Entry
+	if e.RPC != nil {
+		for _, io := range []*Entry{e.RPC.Input, e.RPC.Output} {
+			if io != nil && len(io.Dir) > 0 {
+				children = append(children, io)
+			}
+		}
+	}
+	return children
+}
+
+// newOperationChild returns an empty input or output entry, of kind kind, of
+// the RPC or action e.
+func newOperationChild(e *Entry, kind EntryKind) *Entry {
+	name := "input"
+	if kind == OutputEntry {
+		name = "output"
+	}
+	return &Entry{
+		Parent: e,
+		Name:   name,
+		Kind:   kind,
+		Dir:    make(map[string]*Entry),
+		Extra:  map[string][]interface{}{},
+	}
+}
+
+// FindAll returns the entries matched by pattern, which is a path as used by
+// Find in which an element may also be a wildcard. The wildcard "*" matches
+// each child of an entry, and "**" matches an entry and each of its
+// descendants, i.e., zero or more path elements. As with Find, the prefix
+// of the first element of an absolute pattern selects the module whose tree
+// is searched. The prefix of every other element is also resolved within the
+// module of e, and the element then only matches entries instantiated by
+// that module, so that "/t:c/o:l" does not match a leaf l that is defined in
+// the module with prefix t. An unprefixed element matches an entry of any
+// module. The children of an entry include its choice and case entries and,
+// for an RPC or action, its input and output. For example, "/t:c/*/t:l"
+// matches leaf l within each child of container c, and "/t:c/**" matches c
+// and all of its descendants. The entries are returned in the order that
+// they are matched, with the children of each entry ordered by name, and
+// without duplicates. Nil is returned if nothing matches.
+func (e *Entry) FindAll(pattern string) []*Entry {
+	if e == nil || pattern == "" {
+		return nil
+	}
+	contextNode := e.Node
+	parts := strings.Split(pattern, "/")
+	if parts[0] == "" {
+		parts = parts[1:]
+		if e = e.absoluteRoot(parts[0]); e == nil {
+			return nil
+		}
+	}
+
+	matches := []*Entry{e}
+	for _, part := range parts {
+		var next []*Entry
+		seen := map[*Entry]bool{}
+		add := func(e *Entry) {
+			if e != nil && !seen[e] {
+				seen[e] = true
+				next = append(next, e)
+			}
+		}
+		var addAll func(e *Entry)
+		addAll = func(e *Entry) {
+			add(e)
+			for _, ce := range e.findAllChildren() {
+				addAll(ce)
+			}
+		}
+		for _, m := range matches {
+			switch part {
+			case ".":
+				add(m)
+			case "..":
+				add(m.Parent)
+			case "*":
+				for _, ce := range m.findAllChildren() {
+					add(ce)
+				}
+			case "**":
+				addAll(m)
+			default:
+				prefix, name := getPrefix(part)
+				var mod string
+				if prefix != "" {
+					pm := FindModuleByPrefix(contextNode, prefix)
+					if pm == nil {
+						return nil
+					}
+					mod = module(pm).Name
+				}
+				for _, ce := range m.findAllChildren() {
+					if ce.Name != name {
+						continue
+					}
+					if im, err := ce.InstantiatingModule(); mod == "" || (err == nil && im == mod) {
+						add(ce)
+					}
+				}
+			}
+		}
+		if matches = next; len(matches) == 0 {
+			return nil
+		}
+	}
+	return matches
+}
+
+// findAllChildren returns the children of e, as matched by the elements of
+// the patterns of FindAll, ordered by name.
+func (e *Entry) findAllChildren() []*Entry {
+	var children []*Entry
+	for _, ce := range e.Dir {
+		children = append(children, ce)
+	}
+	if e.RPC != nil {
+		for _, io := range []*Entry{e.RPC.Input, e.RPC.Output} {
+			if io != nil {
+				children = append(children, io)
+			}
+		}
+	}
+	sort.Slice(children, func(i, j int) bool { return children[i].Name < children[j].Name })
+	return children
+}
+
+// absoluteRoot returns the module entry that an absolute path from e, whose
+// first element is first, is rooted at. The prefix of first, if any, names
+// the module. An error is added to the root of e, and nil is returned, if the
+// prefix cannot be resolved.
+func (e *Entry) absoluteRoot(first string) *Entry {
+	contextNode := e.Node
+	for e.Parent != nil {
+		e = e.Parent
+	}
+	if prefix, _ := getPrefix(first); prefix != "" {
+		mod := FindModuleByPrefix(contextNode, prefix)
+		if mod == nil {
+			if root := RootNode(contextNode); root != nil && root.BelongsTo != nil {
+				e.addError(fmt.Errorf("%s: cannot find module giving prefix %q within submodule %s, which refers to module %s by its belongs-to prefix %q",
+					Source(contextNode), prefix, root.Name, root.BelongsTo.Name, root.GetPrefix()))
+				return nil
+			}
+			e.addError(fmt.Errorf("%s: cannot find module giving prefix %q within context entry %q", Source(contextNode), prefix, e.Path()))
+			return nil
+		}
+		m := module(mod)
+		if m == nil {
+			e.addError(fmt.Errorf("cannot find which module %q belongs to within context entry %q",
+				mod.NName(), e.Path()))
+			return nil
+		}
+		if m != e.Node.(*Module) {
+			e = ToEntry(m)
+		}
+	} else if root, ok := e.Node.(*Module); ok && root.BelongsTo != nil && root.Modules != nil {
+		// An unprefixed path within a submodule refers to the
+		// module that the submodule belongs to, which holds the
+		// nodes of all of its submodules.
+		if m := root.Modules.Modules[root.BelongsTo.Name]; m != nil {
+			e = ToEntry(m)
+		}
+	}
+	return e
+}
+
 // Path returns the path to e. A nil Entry returns "".
 func (e *Entry) Path() string {
 	if e == nil {
@@ -1390,6 +2099,36 @@ func (e *Entry) Path() string {
 	return e.Parent.Path() + "/" + e.Name
 }
 
+// Depth returns the number of ancestors of e, so the depth of a module is 0
+// and that of its top-level nodes is 1. Depth measures the schema tree, as
+// does Path, so choice and case entries, and the input and output of RPCs,
+// are counted as ancestors. A nil Entry returns -1.
+func (e *Entry) Depth() int {
+	d := -1
+	for ; e != nil; e = e.Parent {
+		d++
+	}
+	return d
+}
+
+// SubtreeSize returns the number of entries in the tree rooted at e,
+// including e itself. Like Depth, it counts the entries of the schema tree,
+// so choice and case entries are counted, as are the input and output of
+// RPCs and actions and their descendants. A nil Entry returns 0.
+func (e *Entry) SubtreeSize() int {
+	if e == nil {
+		return 0
+	}
+	n := 1
+	for _, ce := range e.Dir {
+		n += ce.SubtreeSize()
+	}
+	if e.RPC != nil {
+		n += e.RPC.Input.SubtreeSize() + e.RPC.Output.SubtreeSize()
+	}
+	return n
+}
+
 // Namespace returns the YANG/XML namespace Value for e as mounted in the Entry
 // tree (e.g., as placed by grouping statements).
 //
@@ -1441,6 +2180,365 @@ func (e *Entry) InstantiatingModule() (string, error) {
 	return module.Name, nil
 }
 
+// JSONName returns the name of the member used to represent e when
+// serialising data to JSON as per RFC7951. The name is qualified with the
+// name of the instantiating module when e is a top-level data node, or when
+// its instantiating module differs from that of its nearest ancestor that is
+// a data node (choice and case entries are not data nodes). The unqualified
+// name is returned if the instantiating module cannot be determined.
+func (e *Entry) JSONName() string {
+	p := e.Parent
+	for p != nil && (p.IsChoice() || p.IsCase()) {
+		p = p.Parent
+	}
+	mod, err := e.InstantiatingModule()
+	if err != nil {
+		return e.Name
+	}
+	if p == nil || p.Parent == nil {
+		return mod + ":" + e.Name
+	}
+	if pmod, err := p.InstantiatingModule(); err != nil || pmod != mod {
+		return mod + ":" + e.Name
+	}
+	return e.Name
+}
+
+// ContributingModules returns the sorted names of the modules whose
+// definitions contribute to the tree rooted at e: the modules that define
+// the nodes of the tree, including modules that augment the tree and modules
+// whose groupings are used within it, and all the modules that those modules
+// import, directly or indirectly. Definitions from a submodule are reported
+// as the module to which the submodule belongs, and the modules imported by
+// the submodules of a reported module are also reported.
+func (e *Entry) ContributingModules() []string {
+	seen := map[*Module]bool{}
+	var mods []*Module
+	add := func(m *Module) {
+		if m != nil && !seen[m] {
+			seen[m] = true
+			mods = append(mods, m)
+		}
+	}
+
+	var walk func(e *Entry)
+	walk = func(e *Entry) {
+		if e.Node != nil {
+			add(RootNode(e.Node))
+		}
+		for _, a := range e.Augmented {
+			walk(a)
+		}
+		for _, ce := range e.Dir {
+			walk(ce)
+		}
+		if e.RPC != nil {
+			for _, ce := range []*Entry{e.RPC.Input, e.RPC.Output} {
+				if ce != nil {
+					walk(ce)
+				}
+			}
+		}
+	}
+	walk(e)
+
+	// Follow the includes and imports of each module found. mods grows
+	// as new modules are found.
+	for i := 0; i < len(mods); i++ {
+		m := mods[i]
+		if m.BelongsTo != nil && m.Modules != nil {
+			add(m.Modules.Modules[m.BelongsTo.Name])
+		}
+		for _, in := range m.Include {
+			add(in.Module)
+		}
+		for _, im := range m.Import {
+			add(im.Module)
+		}
+	}
+
+	names := map[string]bool{}
+	for _, m := range mods {
+		name := m.Name
+		if m.BelongsTo != nil {
+			name = m.BelongsTo.Name
+		}
+		names[name] = true
+	}
+	var out []string
+	for name := range names {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// TypeSignature returns a concise, human readable description of the type of
+// e and its restrictions, e.g., "uint16 [1..100]", "string {pattern 'a.*'}
+// (length 1..24)", "enumeration {A, B, C}" or "union<string, uint32>". An
+// empty string is returned if e has no type.
+func (e *Entry) TypeSignature() string {
+	return e.Type.signature()
+}
+
+// EffectiveType returns the type of e after all deviations have been applied,
+// or nil if e has no type. A deviate replace statement replaces the type of e
+// in its entirety, so where several deviations replace the type of e, the
+// type is that of the last to be applied, and no restrictions of the types
+// that it replaced are retained.
+func (e *Entry) EffectiveType() *YangType {
+	return e.Type
+}
+
+// TypeName returns the name of the type of e as declared by its type
+// statement, or "" if e has no type. This is the name of the typedef that is
+// used, without its prefix, or the name of the built-in type, such as
+// "string", "union" or "leafref", if no typedef is used. Where a typedef is
+// derived from other typedefs, the name is that of the outermost typedef,
+// i.e., the one named by the type statement of e, and it is not changed by
+// restrictions added by the type statement. A union defined by a typedef is
+// named after the typedef, while a union defined inline is named "union".
+func (e *Entry) TypeName() string {
+	if e.Type == nil {
+		return ""
+	}
+	return e.Type.Name
+}
+
+// TypeChain returns the chain of types from the type of e to the built-in
+// type that it is derived from, or nil if e has no type. The first element is
+// the type of e, and each following element is the type that the previous
+// one is derived from, as given by the type statement of its typedef. The
+// last element is the type of the type statement that names the built-in
+// type. Each element is named after the type that its type statement names,
+// and holds the restrictions in effect at that layer, so the layer at which
+// a range or pattern was introduced is the last element to hold it. For
+// example, the chain of a leaf of type gain-adjustment-type, which is a
+// typedef of optical-dB, which is a typedef of decimal64, holds types named
+// gain-adjustment-type, optical-dB and decimal64.
+func (e *Entry) TypeChain() []*YangType {
+	var chain []*YangType
+	seen := map[*YangType]bool{}
+	for y := e.Type; y != nil && !seen[y]; {
+		seen[y] = true
+		chain = append(chain, y)
+		if BaseTypedefs[y.Name] != nil || y.Base == nil {
+			break
+		}
+		y = y.Base.YangType
+	}
+	return chain
+}
+
+// EffectiveEnum returns the enumeration of e after all deviations have been
+// applied, and true, if the effective type of e is an enumeration, or a
+// typedef of one. Otherwise it returns nil and false. The enumeration of a
+// union is not returned, as the union may have several.
+func (e *Entry) EffectiveEnum() (*EnumType, bool) {
+	if t := e.EffectiveType(); t != nil && t.Kind == Yenum && t.Enum != nil {
+		return t.Enum, true
+	}
+	return nil, false
+}
+
+// EnumTypeKey returns a key for the enumeration defined inline by the type
+// statement of e, and true, if e is a leaf or leaf-list of an inline
+// enumeration. Otherwise it returns "" and false; an enumeration defined by a
+// typedef is identified by the typedef. The key depends only on the names of
+// the enums and their values, so two entries have the same key if, and only
+// if, their enumerations are equal, regardless of the names of the entries or
+// the order in which the enums are listed. It is intended for use as the key
+// when deduplicating the types generated for inline enumerations.
+func (e *Entry) EnumTypeKey() (string, bool) {
+	t := e.EffectiveType()
+	if t == nil || t.Kind != Yenum || t.Name != "enumeration" || t.Enum == nil {
+		return "", false
+	}
+	return t.Enum.key(), true
+}
+
+// ExtensionValue returns the argument of the first extension statement on e
+// that is the extension named name defined in the module named module, and
+// whether such a statement was found. The prefix of each extension statement
+// is resolved against the imports of the module in which it was used, so the
+// canonical module name, rather than a prefix, is supplied.
+func (e *Entry) ExtensionValue(module, name string) (string, bool) {
+	exts, err := MatchingEntryExtensions(e, module, name)
+	if err != nil || len(exts) == 0 {
+		return "", false
+	}
+	return exts[0].Argument, true
+}
+
+// HasExtension returns true if e has an extension statement that is the
+// extension named name defined in the module named module.
+func (e *Entry) HasExtension(module, name string) bool {
+	_, ok := e.ExtensionValue(module, name)
+	return ok
+}
+
+// WalkData calls visit for each data node (container, list, leaf, leaf-list,
+// anydata or anyxml) in the subtree rooted at e, in depth first order with
+// the children of each node visited in order of name. Choice and case entries
+// are descended through without being visited, and the subtrees of RPCs and
+// notifications are skipped. If visit returns false then the children of the
+// entry it was called with are not visited. e itself is visited if it is a
+// data node.
+func (e *Entry) WalkData(visit func(*Entry) bool) {
+	if e == nil || e.RPC != nil {
+		return
+	}
+	switch e.Kind {
+	case NotificationEntry, InputEntry, OutputEntry, DeviateEntry:
+		return
+	case LeafEntry, DirectoryEntry, AnyDataEntry, AnyXMLEntry:
+		if e.Parent != nil && !visit(e) {
+			return
+		}
+	}
+	var names []string
+	for name := range e.Dir {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		e.Dir[name].WalkData(visit)
+	}
+}
+
+// MandatoryDescendants returns the descendants of e that must be provided
+// whenever e exists, as defined in RFC7950 section 3: leaves, anydata,
+// anyxml and choices that are mandatory true, and lists and leaf-lists with a
+// min-elements greater than zero. Non-presence containers are descended
+// into, as they exist whenever their parent does, while the descendants of
+// presence containers, lists, and the cases of choices are not, as they are
+// only required if the node that contains them exists. The descendants are
+// returned in depth first order, with the children of each node in order of
+// name. RPCs and notifications are skipped.
+func (e *Entry) MandatoryDescendants() []*Entry {
+	var es []*Entry
+	var walk func(e *Entry)
+	walk = func(e *Entry) {
+		var names []string
+		for name := range e.Dir {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			ce := e.Dir[name]
+			switch {
+			case ce.RPC != nil || ce.Kind == NotificationEntry:
+			case ce.ListAttr != nil:
+				if ce.ListAttr.MinElements > 0 {
+					es = append(es, ce)
+				}
+			case ce.Kind == DirectoryEntry:
+				if len(ce.Extra["presence"]) == 0 {
+					walk(ce)
+				}
+			case ce.Mandatory == TSTrue:
+				es = append(es, ce)
+			}
+		}
+	}
+	walk(e)
+	return es
+}
+
+// Children returns the children of e, i.e., the non-nil values of e.Dir,
+// sorted by name, so that they are iterated over in the same order each
+// time. The input and output of an RPC or action are not children, as they
+// are held by e.RPC rather than e.Dir, so are not included. Use
+// OrderedChildNames for the order of the YANG source instead.
+func (e *Entry) Children() []*Entry {
+	children := make([]*Entry, 0, len(e.Dir))
+	for _, ce := range e.Dir {
+		if ce != nil {
+			children = append(children, ce)
+		}
+	}
+	sort.Slice(children, func(i, j int) bool {
+		return children[i].Name < children[j].Name
+	})
+	return children
+}
+
+// OrderedChildNames returns the names of the children of e, i.e., the keys
+// of e.Dir, in the order in which they appear in the YANG source. The
+// children defined by a uses statement are placed where the uses statement
+// is, in the order in which they appear in the grouping, and the top-level
+// nodes of the submodules of a module follow those of the module. Children
+// whose position is not known from the source of e, such as those added by
+// augments, follow the others in sorted order.
+func (e *Entry) OrderedChildNames() []string {
+	byStmt := map[*Statement]string{}
+	for name, ce := range e.Dir {
+		if ce.Node != nil {
+			if s := ce.Node.Statement(); s != nil {
+				byStmt[s] = name
+			}
+		}
+	}
+
+	var names []string
+	seen := map[string]bool{}
+	uses := map[*Statement]*Uses{}
+	addUses := func(n Node) {
+		walkUses(n, func(u *Uses) {
+			if u.Source != nil {
+				uses[u.Source] = u
+			}
+		})
+	}
+	expanding := map[*Grouping]bool{}
+	var walk func(s *Statement)
+	walk = func(s *Statement) {
+		for _, ss := range s.SubStatements() {
+			if name, ok := byStmt[ss]; ok {
+				if !seen[name] {
+					seen[name] = true
+					names = append(names, name)
+				}
+				continue
+			}
+			u := uses[ss]
+			if u == nil {
+				continue
+			}
+			g := FindGrouping(u, u.Name, map[string]bool{})
+			if g == nil || g.Source == nil || expanding[g] {
+				continue
+			}
+			expanding[g] = true
+			addUses(g)
+			walk(g.Source)
+			delete(expanding, g)
+		}
+	}
+	if e.Node != nil && e.Node.Statement() != nil {
+		addUses(e.Node)
+		walk(e.Node.Statement())
+	}
+	// The top-level nodes of a module include those of its submodules.
+	if m, ok := e.Node.(*Module); ok {
+		for _, in := range m.Include {
+			if in.Module != nil && in.Module.Source != nil {
+				addUses(in.Module)
+				walk(in.Module.Source)
+			}
+		}
+	}
+
+	var rest []string
+	for name := range e.Dir {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	return append(names, rest...)
+}
+
 // shallowDup makes a shallow duplicate of e (only direct children are
 // duplicated; grandchildren and deeper descendants are deleted).
 func (e *Entry) shallowDup() *Entry {
@@ -1504,6 +2602,15 @@ func (e *Entry) merge(prefix *Value, namespace *Value, oe *Entry) {
 			v.namespace = namespace
 		}
 		if se := e.Dir[k]; se != nil {
+			if a, ok := oe.Node.(*Augment); ok {
+				mod := RootNode(a).Name
+				if m := module(a); m != nil {
+					mod = m.Name
+				}
+				e.addError(fmt.Errorf("%s: augment of %s from module %s adds node %q at %s, which shadows the node %q defined at %s",
+					Source(a), a.Name, mod, k, Source(v.Node), se.Name, Source(se.Node)))
+				continue
+			}
 			er := newError(oe.Node, `Duplicate node %q in %q from:
    %s: %s
    %s: %s`, k, e.Name, Source(v.Node), v.Name, Source(se.Node), se.Name)
@@ -1632,13 +2739,29 @@ func (e *Entry) SingleDefaultValue() (string, bool) {
 
 // DefaultValues returns all default values for the leaf entry. This is useful
 // for determining the default values for a leaf-list, which may have more than
-// one default value. If the entry has no explicit default, its type default
-// (if any) will be used. nil is returned when no default value exists.
+// one default value, in the order of its default statements. If the entry has
+// no explicit default, its type default (if any) will be used. A typedef has
+// at most one default, and the default of the nearest typedef within the
+// chain of typedefs of the type that has one is used. As the values of a
+// leaf-list that is configuration data are unique (RFC7950 section 7.7), a
+// value that is repeated by the default statements of such a leaf-list is
+// only returned once. nil is returned when no default value exists.
 //
 // For a leaf entry, use SingleDefaultValue() instead.
 func (e *Entry) DefaultValues() []string {
 	if len(e.Default) > 0 {
-		return append([]string{}, e.Default...)
+		if !e.IsLeafList() || e.ReadOnly() {
+			return append([]string{}, e.Default...)
+		}
+		var defaults []string
+		seen := map[string]bool{}
+		for _, d := range e.Default {
+			if !seen[d] {
+				seen[d] = true
+				defaults = append(defaults, d)
+			}
+		}
+		return defaults
 	}
 
 	if typ := e.Type; typ != nil && typ.HasDefault {
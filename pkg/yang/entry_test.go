@@ -311,6 +311,56 @@ func TestUsesParent(t *testing.T) {
 	}
 }
 
+func TestContributingModules(t *testing.T) {
+	ms := NewModules()
+	for _, tt := range parentTestModules {
+		if err := ms.Parse(tt.in, tt.name); err != nil {
+			t.Fatalf("could not parse module %s: %v", tt.name, err)
+		}
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("could not process modules: %v", errs)
+	}
+	foo, _ := ms.GetModule("foo")
+	bar, _ := ms.GetModule("bar")
+
+	tests := []struct {
+		desc string
+		in   *Entry
+		want []string
+	}{{
+		// qux-augment belongs to qux, which is not loaded, but its
+		// augment is applied, so qux is reported.
+		desc: "module with augment and grouping from other modules",
+		in:   foo,
+		want: []string{"bar", "baz", "foo", "qux"},
+	}, {
+		desc: "container augmented by another module",
+		in:   foo.Dir["foo-c"],
+		want: []string{"bar", "baz", "foo", "qux"},
+	}, {
+		desc: "node from a grouping in another module",
+		in:   foo.Dir["foo-c"].Dir["test1"],
+		want: []string{"bar"},
+	}, {
+		desc: "node from an augment imports the augmented module",
+		in:   foo.Dir["foo-c"].Dir["baz-direct-leaf"],
+		want: []string{"bar", "baz", "foo"},
+	}, {
+		desc: "module without imports",
+		in:   bar,
+		want: []string{"bar"},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, tt.in.ContributingModules()); diff != "" {
+				t.Errorf("ContributingModules (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
 func TestPrefixes(t *testing.T) {
 	ms := NewModules()
 	for _, tt := range parentTestModules {
@@ -449,6 +499,7 @@ var testWhenModules = []struct {
 		name: "when.yang",
 		in: `
 module when {
+  yang-version 1.1;
   namespace "urn:when";
   prefix "when";
 
@@ -493,10 +544,12 @@ module when {
   }
 
   grouping iota {
+    leaf iota-leaf { type string; }
   }
 
   augment "../alpha" {
     when "../condition = 'kappa'";
+    leaf kappa-leaf { type string; }
   }
 }
 `,
@@ -525,6 +578,7 @@ func TestGetWhenXPath(t *testing.T) {
 		choiceName    string
 		isAugment     bool
 		augmentTarget string
+		isUses        bool
 	}{
 		{
 			descr:     "extract when statement from *Container",
@@ -557,6 +611,10 @@ func TestGetWhenXPath(t *testing.T) {
 			childName:     "kappa",
 			isAugment:     true,
 			augmentTarget: "alpha",
+		}, {
+			descr:     "extract when statement from *Uses",
+			childName: "iota",
+			isUses:    true,
 		},
 	}
 
@@ -565,9 +623,12 @@ func TestGetWhenXPath(t *testing.T) {
 		t.Run(tc.descr, func(t *testing.T) {
 			var child *Entry
 
-			if tc.isAugment {
+			switch {
+			case tc.isAugment:
 				child = parentEntry.Dir[tc.augmentTarget].Augmented[0]
-			} else {
+			case tc.isUses:
+				child = parentEntry.Uses[0].Grouping
+			default:
 				if tc.isCase {
 					parentEntry = parentEntry.Dir[tc.choiceName]
 				}
@@ -581,6 +642,38 @@ func TestGetWhenXPath(t *testing.T) {
 			} else if gotWhen != expectedWhen {
 				t.Errorf("Expected when XPath %v, but got %v", expectedWhen, gotWhen)
 			}
+			if len(child.Extra["when"]) == 0 {
+				t.Errorf("when statement of child entry %v is not within Extra", tc.childName)
+			}
+		})
+	}
+
+	// The when statements of the uses and augment statements that add a
+	// node are within Extra, but are not the when statement of the node.
+	for _, tc := range []struct {
+		descr    string
+		in       *Entry
+		wantWhen string
+	}{{
+		descr:    "node added by uses",
+		in:       when.Dir["iota-leaf"],
+		wantWhen: "../condition = 'iota'",
+	}, {
+		descr:    "node added by augment",
+		in:       when.Dir["alpha"].Dir["kappa-leaf"],
+		wantWhen: "../condition = 'kappa'",
+	}} {
+		t.Run(tc.descr, func(t *testing.T) {
+			if gotWhen, ok := tc.in.GetWhenXPath(); ok {
+				t.Errorf("GetWhenXPath: got %q, want none", gotWhen)
+			}
+			var got []string
+			for _, w := range tc.in.Extra["when"] {
+				got = append(got, w.(*Value).Name)
+			}
+			if diff := cmp.Diff([]string{tc.wantWhen}, got); diff != "" {
+				t.Errorf("Extra[\"when\"] (-want, +got):\n%s", diff)
+			}
 		})
 	}
 }
@@ -867,6 +960,252 @@ func TestUsesEntry(t *testing.T) {
 	}
 }
 
+func TestGetWhenXPathModules(t *testing.T) {
+	ms := NewModules()
+	for _, tt := range testAugmentAndUsesModules {
+		if err := ms.Parse(tt.in, tt.name); err != nil {
+			t.Fatalf("could not parse module %s: %v", tt.name, err)
+		}
+	}
+
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("could not process modules: %v", errs)
+	}
+
+	orig, _ := ms.GetModule("original")
+
+	tests := []struct {
+		desc     string
+		inEntry  *Entry
+		wantMods map[string]string
+	}{{
+		desc:     "augment when resolved in augmenting module",
+		inEntry:  orig.Dir["alpha"].Augmented[0],
+		wantMods: map[string]string{"orig": "original"},
+	}, {
+		desc:    "augmented child without when",
+		inEntry: orig.Dir["alpha"].Dir["charlie"],
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := tt.inEntry.GetWhenXPathModules()
+			if err != nil {
+				t.Fatalf("GetWhenXPathModules: unexpected error: %v", err)
+			}
+			gotMods := map[string]string{}
+			for pfx, m := range got {
+				gotMods[pfx] = m.Name
+			}
+			if diff := cmp.Diff(tt.wantMods, gotMods, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("GetWhenXPathModules (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWhenPrefixResolution(t *testing.T) {
+	tests := []struct {
+		desc          string
+		inModules     map[string]string
+		wantErrSubstr string
+	}{{
+		desc: "augment when uses prefix of augmenting module's import",
+		inModules: map[string]string{
+			"a.yang": `
+				module a {
+					prefix "a";
+					namespace "urn:a";
+
+					container c { leaf l { type string; } }
+				}`,
+			"b.yang": `
+				module b {
+					prefix "b";
+					namespace "urn:b";
+					import a { prefix "x"; }
+
+					augment "/x:c" {
+						when "x:l = 'on'";
+						leaf m {
+							when "../x:l != 'off' and child::x:l";
+							type string;
+						}
+					}
+				}`,
+		},
+	}, {
+		desc: "augment when uses prefix of target module only",
+		inModules: map[string]string{
+			"a.yang": `
+				module a {
+					prefix "a";
+					namespace "urn:a";
+
+					container c { leaf l { type string; } }
+				}`,
+			"b.yang": `
+				module b {
+					prefix "b";
+					namespace "urn:b";
+					import a { prefix "x"; }
+
+					augment "/x:c" {
+						when "a:l = 'on'";
+						leaf m { type string; }
+					}
+				}`,
+		},
+		wantErrSubstr: `unknown prefix "a" in when statement`,
+	}, {
+		desc: "augmented leaf when uses unknown prefix",
+		inModules: map[string]string{
+			"a.yang": `
+				module a {
+					prefix "a";
+					namespace "urn:a";
+
+					container c { leaf l { type string; } }
+				}`,
+			"b.yang": `
+				module b {
+					prefix "b";
+					namespace "urn:b";
+					import a { prefix "x"; }
+
+					augment "/x:c" {
+						leaf m {
+							when "../y:l = 'a:b'";
+							type string;
+						}
+					}
+				}`,
+		},
+		wantErrSubstr: `unknown prefix "y" in when statement`,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ms := NewModules()
+			for n, m := range tt.inModules {
+				if err := ms.Parse(m, n); err != nil {
+					t.Fatalf("cannot parse module %s, err: %v", n, err)
+				}
+			}
+			var err error
+			if errs := ms.Process(); len(errs) > 0 {
+				err = errs[0]
+			}
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestXPathPrefixes(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{in: "beta = 'holaWorld'"},
+		{in: "orig:beta = 'helloWorld'", want: []string{"orig"}},
+		{in: "current()/orig:beta = 'a:b'", want: []string{"orig"}},
+		{in: `../a:x/b:y = "c:z" or ../a:w`, want: []string{"a", "b"}},
+		{in: "child::a:b and ancestor-or-self::*", want: []string{"a"}},
+		{in: "count(x:y) > 1.5", want: []string{"x"}},
+		{in: "derived-from-or-self(../t:type, 'ift:ethernet')", want: []string{"t"}},
+	}
+
+	for _, tt := range tests {
+		if diff := cmp.Diff(tt.want, xpathPrefixes(tt.in)); diff != "" {
+			t.Errorf("xpathPrefixes(%q) (-want, +got):\n%s", tt.in, diff)
+		}
+	}
+}
+
+func TestJSONName(t *testing.T) {
+	modules := map[string]string{
+		"a.yang": `
+			module a {
+				prefix "a";
+				namespace "urn:a";
+
+				container c {
+					leaf l { type string; }
+					choice ch {
+						case one { leaf x { type string; } }
+					}
+				}
+			}`,
+		"b.yang": `
+			module b {
+				prefix "b";
+				namespace "urn:b";
+				import a { prefix "a"; }
+
+				augment "/a:c" {
+					container d { leaf y { type string; } }
+				}
+				augment "/a:c/a:ch" {
+					case two { leaf z { type string; } }
+				}
+			}`,
+	}
+
+	ms := NewModules()
+	for n, m := range modules {
+		if err := ms.Parse(m, n); err != nil {
+			t.Fatalf("cannot parse module %s, err: %v", n, err)
+		}
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process modules, errs: %v", errs)
+	}
+	a, errs := ms.GetModule("a")
+	if errs != nil {
+		t.Fatalf("cannot get module a, errs: %v", errs)
+	}
+
+	tests := []struct {
+		desc string
+		in   *Entry
+		want string
+	}{{
+		desc: "top-level container",
+		in:   a.Dir["c"],
+		want: "a:c",
+	}, {
+		desc: "child in same module",
+		in:   a.Dir["c"].Dir["l"],
+		want: "l",
+	}, {
+		desc: "child within choice in same module",
+		in:   a.Dir["c"].Dir["ch"].Dir["one"].Dir["x"],
+		want: "x",
+	}, {
+		desc: "augmented child",
+		in:   a.Dir["c"].Dir["d"],
+		want: "b:d",
+	}, {
+		desc: "child of augmented child",
+		in:   a.Dir["c"].Dir["d"].Dir["y"],
+		want: "y",
+	}, {
+		desc: "augmented case child",
+		in:   a.Dir["c"].Dir["ch"].Dir["two"].Dir["z"],
+		want: "b:z",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if got := tt.in.JSONName(); got != tt.want {
+				t.Errorf("JSONName() got: %q, want: %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestShallowDup(t *testing.T) {
 	testModule := struct {
 		name string
@@ -1059,6 +1398,19 @@ module defaults {
     default "";
   }
 
+  typedef string-overridedefault {
+    type string-default;
+    default "overriding default value";
+  }
+
+  typedef string-inheriteddefault {
+    type string-default;
+  }
+
+  typedef string-inheriteddefault2 {
+    type string-inheriteddefault;
+  }
+
   grouping common {
     container common-nodefault {
       leaf string {
@@ -1163,6 +1515,30 @@ module defaults {
     leaf-list nodefault {
       type string;
     }
+    leaf-list stringlist-overridedefault {
+      type string-overridedefault;
+    }
+    leaf-list stringlist-inheriteddefault {
+      type string-inheriteddefault2;
+    }
+    leaf-list stringlist-typedef-withdefaults {
+      type string-default;
+      default "first";
+      default "second";
+    }
+    leaf-list stringlist-repeateddefaults {
+      type string;
+      default "b";
+      default "a";
+      default "b";
+    }
+    leaf-list stringlist-state-repeateddefaults {
+      type string;
+      config false;
+      default "b";
+      default "a";
+      default "b";
+    }
     uses leaflist-common;
   }
 
@@ -1270,6 +1646,33 @@ module defaults {
 			wantSingle:   "",
 			wantDefaults: nil,
 		},
+		{
+			path:         []string{"leaflist-defaults", "stringlist-overridedefault"},
+			wantSingle:   "overriding default value",
+			wantDefaults: []string{"overriding default value"},
+			wantSingleOk: true,
+		},
+		{
+			path:         []string{"leaflist-defaults", "stringlist-inheriteddefault"},
+			wantSingle:   "typedef default value",
+			wantDefaults: []string{"typedef default value"},
+			wantSingleOk: true,
+		},
+		{
+			path:         []string{"leaflist-defaults", "stringlist-typedef-withdefaults"},
+			wantSingle:   "",
+			wantDefaults: []string{"first", "second"},
+		},
+		{
+			path:         []string{"leaflist-defaults", "stringlist-repeateddefaults"},
+			wantSingle:   "",
+			wantDefaults: []string{"b", "a"},
+		},
+		{
+			path:         []string{"leaflist-defaults", "stringlist-state-repeateddefaults"},
+			wantSingle:   "",
+			wantDefaults: []string{"b", "a", "b"},
+		},
 		{
 			path:         []string{"leaflist-defaults", "common-nodefault", "string"},
 			wantSingle:   "",
@@ -1306,6 +1709,22 @@ module defaults {
 			t.Errorf("[%d_%s] DefaultValues (-got, +want):\n%s", i, tname, diff)
 		}
 	}
+
+	// A typedef may only have one default, so a leaf-list cannot inherit
+	// several defaults from its type.
+	err := NewModules().Parse(`
+module multi-defaults {
+  namespace "urn:multi-defaults";
+  prefix "md";
+  typedef string-defaults {
+    type string;
+    default "first";
+    default "second";
+  }
+}`, "multi-defaults.yang")
+	if diff := errdiff.Substring(err, "default: already set"); diff != "" {
+		t.Errorf("typedef with two defaults: %s", diff)
+	}
 }
 
 func TestFullModuleProcess(t *testing.T) {
@@ -1664,6 +2083,31 @@ func TestFullModuleProcess(t *testing.T) {
 			if diff := cmp.Diff([]*Statement{}, module.Dir["c"].Dir["c2"].Dir["l2"].Exts, cmpopts.IgnoreUnexported(Statement{}), less, cmpopts.EquateEmpty()); diff != "" {
 				t.Errorf("leaf l2 Exts (-want, +got):\n%s", diff)
 			}
+
+			for _, tc := range []struct {
+				entry   *Entry
+				name    string
+				wantVal string
+				wantOK  bool
+			}{
+				{entry: module.Dir["c"], name: "c-define", wantVal: "c's extension", wantOK: true},
+				{entry: module.Dir["c"], name: "l-define"},
+				{entry: module.Dir["c"].Dir["l"], name: "l-define", wantVal: "l's extension", wantOK: true},
+				{entry: module.Dir["c"].Dir["l"], name: "u-define", wantVal: "uses's extension", wantOK: true},
+				{entry: module.Dir["c"].Dir["s"], name: "sg-define", wantVal: "son-group's extension", wantOK: true},
+				{entry: module.Dir["c"].Dir["c2"].Dir["l2"], name: "g-define"},
+			} {
+				gotVal, gotOK := tc.entry.ExtensionValue("extensions", tc.name)
+				if gotVal != tc.wantVal || gotOK != tc.wantOK {
+					t.Errorf("%s: ExtensionValue(extensions, %s), got: (%q, %v), want: (%q, %v)", tc.entry.Name, tc.name, gotVal, gotOK, tc.wantVal, tc.wantOK)
+				}
+				if got := tc.entry.HasExtension("extensions", tc.name); got != tc.wantOK {
+					t.Errorf("%s: HasExtension(extensions, %s), got: %v, want: %v", tc.entry.Name, tc.name, got, tc.wantOK)
+				}
+			}
+			if module.Dir["c"].HasExtension("ext", "c-define") {
+				t.Errorf("HasExtension matched on the prefix rather than the module name")
+			}
 		},
 	}}
 
@@ -1721,6 +2165,7 @@ func TestAnyDataAnyXML(t *testing.T) {
 		inModule      string
 		wantNodeKind  string
 		wantEntryKind EntryKind
+		wantMandatory TriState
 	}{
 		{
 			name:          "test anyxml",
@@ -1741,6 +2186,7 @@ func TestAnyDataAnyXML(t *testing.T) {
 			wantNodeKind:  "anydata",
 			wantEntryKind: AnyDataEntry,
 			inModule: `module test {
+  yang-version 1.1;
   namespace "urn:test";
   prefix "test";
   container c {
@@ -1750,57 +2196,126 @@ func TestAnyDataAnyXML(t *testing.T) {
   }
 }`,
 		},
-	}
-	for _, tt := range tests {
-		ms := NewModules()
-		if err := ms.Parse(tt.inModule, "test"); err != nil {
-			t.Errorf("%s: error parsing module 'test', got: %v, want: nil", tt.name, err)
-		}
-
-		if errs := ms.Process(); len(errs) > 0 {
-			t.Errorf("%s: got module parsing errors", tt.name)
-			for i, err := range errs {
-				t.Errorf("%s: error #%d: %v", tt.name, i, err)
-			}
-			continue
-		}
-
-		mod, ok := ms.Modules["test"]
-		if !ok {
-			t.Errorf("%s: did not find `test` module", tt.name)
-			continue
-		}
-		e := ToEntry(mod)
-		c := e.Dir["c"]
-		if c == nil {
-			t.Errorf("%s: did not find container c", tt.name)
-			continue
-		}
-		data := c.Dir["data"]
-		if data == nil {
-			t.Errorf("%s: did not find leaf c/data", tt.name)
-			continue
-		}
-		if got := data.Node.Kind(); got != tt.wantNodeKind {
-			t.Errorf("%s: want Node.Kind(): %q, got: %q", tt.name, tt.wantNodeKind, got)
-		}
-		if got := data.Kind; got != tt.wantEntryKind {
-			t.Errorf("%s: want Kind: %v, got: %v", tt.name, tt.wantEntryKind, got)
-		}
-		if got := data.Description; got != tt.wantNodeKind {
-			t.Errorf("%s: want data.Description: %q, got: %q", tt.name, tt.wantNodeKind, got)
-		}
-	}
-}
-
-func getEntry(root *Entry, path []string) *Entry {
-	for _, elem := range path {
-		if root = root.Dir[elem]; root == nil {
-			break
-		}
-	}
-	return root
-}
+		{
+			name:          "test mandatory anyxml",
+			wantNodeKind:  "anyxml",
+			wantEntryKind: AnyXMLEntry,
+			wantMandatory: TSTrue,
+			inModule: `module test {
+  namespace "urn:test";
+  prefix "test";
+  container c {
+    anyxml data {
+      description "anyxml";
+      mandatory true;
+    }
+  }
+}`,
+		},
+		{
+			name:          "test mandatory anydata",
+			wantNodeKind:  "anydata",
+			wantEntryKind: AnyDataEntry,
+			wantMandatory: TSTrue,
+			inModule: `module test {
+  yang-version 1.1;
+  namespace "urn:test";
+  prefix "test";
+  container c {
+    anydata data {
+      description "anydata";
+      mandatory true;
+    }
+  }
+}`,
+		},
+		{
+			name:          "test non-mandatory anyxml",
+			wantNodeKind:  "anyxml",
+			wantEntryKind: AnyXMLEntry,
+			wantMandatory: TSFalse,
+			inModule: `module test {
+  namespace "urn:test";
+  prefix "test";
+  container c {
+    anyxml data {
+      description "anyxml";
+      mandatory false;
+    }
+  }
+}`,
+		},
+		{
+			name:          "test non-mandatory anydata",
+			wantNodeKind:  "anydata",
+			wantEntryKind: AnyDataEntry,
+			wantMandatory: TSFalse,
+			inModule: `module test {
+  yang-version 1.1;
+  namespace "urn:test";
+  prefix "test";
+  container c {
+    anydata data {
+      description "anydata";
+      mandatory false;
+    }
+  }
+}`,
+		},
+	}
+	for _, tt := range tests {
+		ms := NewModules()
+		if err := ms.Parse(tt.inModule, "test"); err != nil {
+			t.Errorf("%s: error parsing module 'test', got: %v, want: nil", tt.name, err)
+		}
+
+		if errs := ms.Process(); len(errs) > 0 {
+			t.Errorf("%s: got module parsing errors", tt.name)
+			for i, err := range errs {
+				t.Errorf("%s: error #%d: %v", tt.name, i, err)
+			}
+			continue
+		}
+
+		mod, ok := ms.Modules["test"]
+		if !ok {
+			t.Errorf("%s: did not find `test` module", tt.name)
+			continue
+		}
+		e := ToEntry(mod)
+		c := e.Dir["c"]
+		if c == nil {
+			t.Errorf("%s: did not find container c", tt.name)
+			continue
+		}
+		data := c.Dir["data"]
+		if data == nil {
+			t.Errorf("%s: did not find leaf c/data", tt.name)
+			continue
+		}
+		if got := data.Node.Kind(); got != tt.wantNodeKind {
+			t.Errorf("%s: want Node.Kind(): %q, got: %q", tt.name, tt.wantNodeKind, got)
+		}
+		if got := data.Kind; got != tt.wantEntryKind {
+			t.Errorf("%s: want Kind: %v, got: %v", tt.name, tt.wantEntryKind, got)
+		}
+		if got := data.Description; got != tt.wantNodeKind {
+			t.Errorf("%s: want data.Description: %q, got: %q", tt.name, tt.wantNodeKind, got)
+		}
+		if got := data.Mandatory; got != tt.wantMandatory {
+			t.Errorf("%s: want data.Mandatory: %v, got: %v", tt.name, tt.wantMandatory, got)
+		}
+	}
+}
+
+func getEntry(root *Entry, path []string) *Entry {
+	for _, elem := range path {
+		if root = root.Dir[elem]; root == nil {
+			break
+		}
+	}
+	return root
+}
 
 func TestActionRPC(t *testing.T) {
 	tests := []struct {
@@ -1809,14 +2324,18 @@ func TestActionRPC(t *testing.T) {
 		operationPath []string
 		wantNodeKind  string
 		wantError     string
-		noInput       bool
-		noOutput      bool
+		// wantProcessError is a substring of the wanted errors
+		// from processing the module.
+		wantProcessError string
+		noInput          bool
+		noOutput         bool
 	}{
 		{
 			name:          "test action in container",
 			wantNodeKind:  "action",
 			operationPath: []string{"c", "operation"},
 			inModule: `module test {
+  yang-version 1.1;
   namespace "urn:test";
   prefix "test";
   container c {
@@ -1834,6 +2353,7 @@ func TestActionRPC(t *testing.T) {
 			wantNodeKind:  "action",
 			operationPath: []string{"list", "operation"},
 			inModule: `module test {
+  yang-version 1.1;
   namespace "urn:test";
   prefix "test";
   list list {
@@ -1851,6 +2371,7 @@ func TestActionRPC(t *testing.T) {
 			wantNodeKind:  "action",
 			operationPath: []string{"c", "operation"},
 			inModule: `module test {
+  yang-version 1.1;
   namespace "urn:test";
   prefix "test";
   grouping g {
@@ -1869,6 +2390,7 @@ func TestActionRPC(t *testing.T) {
 			wantNodeKind:  "action",
 			operationPath: []string{"list", "operation"},
 			inModule: `module test {
+  yang-version 1.1;
   namespace "urn:test";
   prefix "test";
   grouping g {
@@ -1916,6 +2438,24 @@ func TestActionRPC(t *testing.T) {
 			noOutput: true,
 		},
 
+		{
+			name:          "minimal action",
+			wantNodeKind:  "action",
+			operationPath: []string{"c", "operation"},
+			inModule: `module test {
+  yang-version 1.1;
+  namespace "urn:test";
+  prefix "test";
+  container c {
+    action operation {
+      description "action";
+    }
+  }
+}`,
+			noInput:  true,
+			noOutput: true,
+		},
+
 		{
 			name:          "input-only rpc",
 			wantNodeKind:  "rpc",
@@ -1987,6 +2527,76 @@ func TestActionRPC(t *testing.T) {
     // error: "operation" is not a valid sub-statement to "leaf-list"
     action operation;
   }
+}`,
+		},
+
+		// test cases with errors (in module processing)
+		{
+			name:             "action within rpc input",
+			wantProcessError: "test:9:9: action operation is not allowed within rpc /test/r",
+			inModule: `module test {
+  yang-version 1.1;
+  namespace "urn:test";
+  prefix "test";
+  rpc r {
+    input {
+      container c {
+        // error: an action may not be within an rpc
+        action operation;
+      }
+    }
+  }
+}`,
+		},
+
+		{
+			name:             "action within notification",
+			wantProcessError: "test:8:7: action operation is not allowed within notification /test/n",
+			inModule: `module test {
+  yang-version 1.1;
+  namespace "urn:test";
+  prefix "test";
+  notification n {
+    list l {
+      // error: an action may not be within a notification
+      action operation;
+    }
+  }
+}`,
+		},
+
+		{
+			name:             "action within notification via grouping",
+			wantProcessError: "test:7:7: action operation is not allowed within notification /test/n",
+			inModule: `module test {
+  yang-version 1.1;
+  namespace "urn:test";
+  prefix "test";
+  grouping g {
+    container c {
+      action operation;
+    }
+  }
+  notification n { uses g; }
+}`,
+		},
+
+		{
+			name:             "action within action output",
+			wantProcessError: "test:9:11: action operation is not allowed within action /test/c/outer",
+			inModule: `module test {
+  yang-version 1.1;
+  namespace "urn:test";
+  prefix "test";
+  container c {
+    action outer {
+      output {
+        container o {
+          action operation;
+        }
+      }
+    }
+  }
 }`,
 		},
 	}
@@ -1999,7 +2609,14 @@ func TestActionRPC(t *testing.T) {
 			continue
 		}
 
-		if errs := ms.Process(); len(errs) > 0 {
+		errs := ms.Process()
+		if tt.wantProcessError != "" {
+			if diff := errdiff.Substring(fmt.Errorf("%v", errs), tt.wantProcessError); len(errs) == 0 || diff != "" {
+				t.Errorf("%s: did not get expected process errors %v, want error containing %q", tt.name, errs, tt.wantProcessError)
+			}
+			continue
+		}
+		if len(errs) > 0 {
 			t.Errorf("%s: got %d module parsing errors", tt.name, len(errs))
 			for i, err := range errs {
 				t.Errorf("%s: error #%d: %v", tt.name, i, err)
@@ -2036,6 +2653,7 @@ var testIfFeatureModules = []struct {
 	{
 		name: "if-feature.yang",
 		in: `module if-feature {
+  yang-version 1.1;
   namespace "urn:if-feature";
   prefix "feat";
 
@@ -2304,6 +2922,124 @@ func TestIfFeature(t *testing.T) {
 	}
 }
 
+func TestIfFeatureUndefined(t *testing.T) {
+	const other = `
+		module other {
+			prefix "o";
+			namespace "urn:o";
+			feature remote;
+		}`
+
+	tests := []struct {
+		desc          string
+		in            []string
+		wantErrSubstr string
+	}{{
+		desc: "defined features",
+		in: []string{other, `
+			module test {
+				yang-version 1.1;
+				prefix "t";
+				namespace "urn:t";
+				import other { prefix o; }
+				include test-sub;
+				feature local;
+				leaf a { type string; if-feature local; }
+				leaf b { type string; if-feature t:local; }
+				leaf c { type string; if-feature o:remote; }
+				leaf d { type string; if-feature "(local or sub) and not o:remote"; }
+			}`, `
+			submodule test-sub {
+				yang-version 1.1;
+				belongs-to test { prefix t; }
+				feature sub;
+				leaf e { type string; if-feature "local and sub"; }
+			}`},
+	}, {
+		desc: "undefined local feature",
+		in: []string{`
+			module test {
+				prefix "t";
+				namespace "urn:t";
+				feature local;
+				leaf a { type string; if-feature locl; }
+			}`},
+		wantErrSubstr: `test0.yang:6:27: if-feature "locl" refers to undefined feature locl`,
+	}, {
+		desc: "undefined feature within a grouping",
+		in: []string{`
+			module test {
+				prefix "t";
+				namespace "urn:t";
+				grouping g {
+					leaf a { type string; if-feature missing; }
+				}
+			}`},
+		wantErrSubstr: `if-feature "missing" refers to undefined feature missing`,
+	}, {
+		desc: "undefined imported feature",
+		in: []string{other, `
+			module test {
+				prefix "t";
+				namespace "urn:t";
+				import other { prefix o; }
+				leaf a { type string; if-feature o:local; }
+			}`},
+		wantErrSubstr: `if-feature "o:local" refers to undefined feature o:local`,
+	}, {
+		desc: "undefined feature within an expression",
+		in: []string{`
+			module test {
+				yang-version 1.1;
+				prefix "t";
+				namespace "urn:t";
+				feature local;
+				leaf a { type string; if-feature "local or (not absent)"; }
+			}`},
+		wantErrSubstr: `if-feature "local or (not absent)" refers to undefined feature absent`,
+	}, {
+		desc: "feature of the module within a submodule",
+		in: []string{`
+			module test {
+				prefix "t";
+				namespace "urn:t";
+				include test-sub;
+			}`, `
+			submodule test-sub {
+				belongs-to test { prefix t; }
+				leaf a { type string; if-feature t:missing; }
+			}`},
+		wantErrSubstr: `if-feature "t:missing" refers to undefined feature t:missing`,
+	}, {
+		desc: "unknown prefix",
+		in: []string{`
+			module test {
+				prefix "t";
+				namespace "urn:t";
+				leaf a { type string; if-feature x:remote; }
+			}`},
+		wantErrSubstr: `if-feature "x:remote" refers to feature x:remote with unknown prefix "x"`,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ms := NewModules()
+			for i, src := range tt.in {
+				if err := ms.Parse(src, fmt.Sprintf("test%d.yang", i)); err != nil {
+					t.Fatalf("cannot parse module %d: %v", i, err)
+				}
+			}
+			var err error
+			if errs := ms.Process(); len(errs) > 0 {
+				err = fmt.Errorf("%v", errs)
+			}
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Errorf("Process: %s", diff)
+			}
+		})
+	}
+}
+
 var testNotificationModules = []struct {
 	name string
 	in   string
@@ -2410,6 +3146,7 @@ func TestEntryFind(t *testing.T) {
 		inModules: map[string]string{
 			"test.yang": `
 				module test {
+					yang-version 1.1;
 					prefix "t";
 					namespace "urn:t";
 
@@ -2467,6 +3204,7 @@ func TestEntryFind(t *testing.T) {
 		inModules: map[string]string{
 			"test.yang": `
 				module test {
+					yang-version 1.1;
 					prefix "t";
 					namespace "urn:t";
 
@@ -2624,32 +3362,161 @@ func TestEntryFind(t *testing.T) {
 	}
 }
 
-func TestEntryTypes(t *testing.T) {
-	leafSchema := &Entry{Name: "leaf-schema", Kind: LeafEntry, Type: &YangType{Kind: Ystring}}
-
-	containerSchema := &Entry{
-		Name: "container-schema",
-		Kind: DirectoryEntry,
-		Dir: map[string]*Entry{
-			"config": {
-				Dir: map[string]*Entry{
-					"leaf1": {
-						Kind: LeafEntry,
-						Name: "Leaf1Name",
-						Type: &YangType{Kind: Ystring},
-					},
-				},
-			},
-		},
+func TestFindAll(t *testing.T) {
+	ms := NewModules()
+	for name, src := range map[string]string{
+		"test.yang": `
+			module test {
+				prefix "t";
+				namespace "urn:t";
+				import other { prefix o; }
+				container c {
+					container a { leaf l { type string; } leaf m { type string; } }
+					container b { leaf l { type string; } }
+					list x { key "l"; leaf l { type string; } }
+					leaf l { type string; }
+				}
+				rpc r {
+					input { leaf l { type string; } }
+					output { leaf l { type string; } }
+				}
+			}`,
+		"other.yang": `
+			module other {
+				prefix "o";
+				namespace "urn:o";
+				container oc { leaf ol { type string; } }
+			}`,
+		"aug.yang": `
+			module aug {
+				prefix "a";
+				namespace "urn:a";
+				import test { prefix t; }
+				augment "/t:c" { leaf aug { type string; } }
+			}`,
+	} {
+		if err := ms.Parse(src, name); err != nil {
+			t.Fatalf("cannot parse %s: %v", name, err)
+		}
 	}
-
-	emptyContainerSchema := &Entry{
-		Name: "empty-container-schema",
-		Kind: DirectoryEntry,
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process modules: %v", errs)
 	}
+	c := ToEntry(ms.Modules["test"]).Dir["c"]
 
-	leafListSchema := &Entry{
-		Kind:     LeafEntry,
+	tests := []struct {
+		desc      string
+		inPattern string
+		want      []string
+	}{{
+		desc:      "single segment wildcard",
+		inPattern: "/t:c/*/t:l",
+		want:      []string{"/test/c/a/l", "/test/c/b/l", "/test/c/x/l"},
+	}, {
+		desc:      "unprefixed",
+		inPattern: "/c/*/l",
+		want:      []string{"/test/c/a/l", "/test/c/b/l", "/test/c/x/l"},
+	}, {
+		desc:      "recursive wildcard",
+		inPattern: "/t:c/**",
+		want: []string{
+			"/test/c",
+			"/test/c/a", "/test/c/a/l", "/test/c/a/m",
+			"/test/c/aug",
+			"/test/c/b", "/test/c/b/l",
+			"/test/c/l",
+			"/test/c/x", "/test/c/x/l",
+		},
+	}, {
+		desc:      "recursive wildcard followed by a name",
+		inPattern: "/**/l",
+		want: []string{
+			"/test/c/l", "/test/c/a/l", "/test/c/b/l", "/test/c/x/l",
+			"/test/r/input/l", "/test/r/output/l",
+		},
+	}, {
+		desc:      "rpc input and output",
+		inPattern: "/t:r/*",
+		want:      []string{"/test/r/input", "/test/r/output"},
+	}, {
+		desc:      "relative",
+		inPattern: "*/m",
+		want:      []string{"/test/c/a/m"},
+	}, {
+		desc:      "parent",
+		inPattern: "a/../*/l",
+		want:      []string{"/test/c/a/l", "/test/c/b/l", "/test/c/x/l"},
+	}, {
+		desc:      "concrete path",
+		inPattern: "/t:c/a/l",
+		want:      []string{"/test/c/a/l"},
+	}, {
+		desc:      "imported module",
+		inPattern: "/o:oc/*",
+		want:      []string{"/other/oc/ol"},
+	}, {
+		desc:      "mismatched prefix",
+		inPattern: "/t:c/o:a/t:l",
+	}, {
+		desc:      "mismatched prefix after a wildcard",
+		inPattern: "/t:c/*/o:l",
+	}, {
+		desc:      "unprefixed augmented child",
+		inPattern: "/t:c/aug",
+		want:      []string{"/test/c/aug"},
+	}, {
+		desc:      "augmented child with the prefix of the augmented module",
+		inPattern: "/t:c/t:aug",
+	}, {
+		desc:      "unknown prefix after the first element",
+		inPattern: "/t:c/x:a",
+	}, {
+		desc:      "no match",
+		inPattern: "/t:c/*/nope",
+	}, {
+		desc:      "unknown prefix",
+		inPattern: "/x:c/*",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var got []string
+			for _, e := range c.FindAll(tt.inPattern) {
+				got = append(got, e.Path())
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("FindAll(%q) (-want, +got):\n%s", tt.inPattern, diff)
+			}
+		})
+	}
+}
+
+func TestEntryTypes(t *testing.T) {
+	leafSchema := &Entry{Name: "leaf-schema", Kind: LeafEntry, Type: &YangType{Kind: Ystring}}
+
+	containerSchema := &Entry{
+		Name: "container-schema",
+		Kind: DirectoryEntry,
+		Dir: map[string]*Entry{
+			"config": {
+				Dir: map[string]*Entry{
+					"leaf1": {
+						Kind: LeafEntry,
+						Name: "Leaf1Name",
+						Type: &YangType{Kind: Ystring},
+					},
+				},
+			},
+		},
+	}
+
+	emptyContainerSchema := &Entry{
+		Name: "empty-container-schema",
+		Kind: DirectoryEntry,
+	}
+
+	leafListSchema := &Entry{
+		Kind:     LeafEntry,
 		ListAttr: &ListAttr{MinElements: 0},
 		Type:     &YangType{Kind: Ystring},
 		Name:     "leaf-list-schema",
@@ -2972,6 +3839,10 @@ func TestDeviation(t *testing.T) {
 	type deviationTest struct {
 		path  string
 		entry *Entry // entry is the entry that is wanted at a particular path, if a field is left as nil, it is not checked.
+		// musts and unique are the arguments of the must and unique
+		// statements wanted at the path, if nil, they are not checked.
+		musts  []string
+		unique []string
 	}
 	tests := []struct {
 		desc                    string
@@ -3483,6 +4354,151 @@ func TestDeviation(t *testing.T) {
 				}`,
 		},
 		wantProcessErrSubstring: "differs from deviation's max-element value",
+	}, {
+		desc: "deviation of min-elements and max-elements on leaf-lists and lists",
+		inFiles: map[string]string{
+			"deviate": `
+				module deviate {
+					prefix "d";
+					namespace "urn:d";
+
+					container add {
+						leaf-list ll { type string; }
+						list l { key "k"; leaf k { type string; } }
+					}
+
+					container replace {
+						leaf-list ll {
+							type string;
+							min-elements 1;
+							max-elements 10;
+						}
+						list l {
+							key "k";
+							min-elements 1;
+							max-elements 10;
+							leaf k { type string; }
+						}
+					}
+
+					container delete {
+						leaf-list ll {
+							type string;
+							min-elements 1;
+							max-elements 10;
+						}
+						list l {
+							key "k";
+							min-elements 1;
+							max-elements 10;
+							leaf k { type string; }
+						}
+					}
+
+					deviation /add/ll {
+						deviate add {
+							min-elements 2;
+							max-elements 20;
+						}
+					}
+
+					deviation /add/l {
+						deviate add {
+							min-elements 2;
+							max-elements 20;
+						}
+					}
+
+					deviation /replace/ll {
+						deviate replace {
+							min-elements 3;
+							max-elements 30;
+						}
+					}
+
+					deviation /replace/l {
+						deviate replace {
+							min-elements 3;
+							max-elements 30;
+						}
+					}
+
+					deviation /delete/ll {
+						deviate delete {
+							min-elements 1;
+							max-elements 10;
+						}
+					}
+
+					deviation /delete/l {
+						deviate delete {
+							min-elements 1;
+							max-elements 10;
+						}
+					}
+				}`,
+		},
+		wants: map[string][]deviationTest{
+			"deviate": {{
+				path: "/add/ll",
+				entry: &Entry{
+					ListAttr:        &ListAttr{MinElements: 2, MaxElements: 20},
+					deviatePresence: deviationPresence{hasMinElements: true, hasMaxElements: true},
+				},
+			}, {
+				path: "/add/l",
+				entry: &Entry{
+					ListAttr:        &ListAttr{MinElements: 2, MaxElements: 20},
+					deviatePresence: deviationPresence{hasMinElements: true, hasMaxElements: true},
+				},
+			}, {
+				path: "/replace/ll",
+				entry: &Entry{
+					ListAttr:        &ListAttr{MinElements: 3, MaxElements: 30},
+					deviatePresence: deviationPresence{hasMinElements: true, hasMaxElements: true},
+				},
+			}, {
+				path: "/replace/l",
+				entry: &Entry{
+					ListAttr:        &ListAttr{MinElements: 3, MaxElements: 30},
+					deviatePresence: deviationPresence{hasMinElements: true, hasMaxElements: true},
+				},
+			}, {
+				path: "/delete/ll",
+				entry: &Entry{
+					ListAttr:        &ListAttr{MinElements: 0, MaxElements: math.MaxUint64},
+					deviatePresence: deviationPresence{hasMinElements: true, hasMaxElements: true},
+				},
+			}, {
+				path: "/delete/l",
+				entry: &Entry{
+					ListAttr:        &ListAttr{MinElements: 0, MaxElements: math.MaxUint64},
+					deviatePresence: deviationPresence{hasMinElements: true, hasMaxElements: true},
+				},
+			}},
+		},
+	}, {
+		desc: "error case - deviation delete of max-elements on a list has different keyword value",
+		inFiles: map[string]string{
+			"deviate": `
+				module deviate {
+					prefix "d";
+					namespace "urn:d";
+
+					list a {
+						key "k";
+						max-elements 100;
+						leaf k { type string; }
+					}
+
+					deviation /a {
+						deviate delete {
+							max-elements 42;
+						}
+					}
+				}`,
+		},
+		wantProcessErrSubstring: "max-element value 100 differs from deviation's max-element value 42",
 	}, {
 		desc: "deviation using locally defined typedef",
 		inFiles: map[string]string{
@@ -3568,6 +4584,180 @@ func TestDeviation(t *testing.T) {
 				},
 			}},
 		},
+	}, {
+		desc: "deviation with add and delete of must and unique",
+		inFiles: map[string]string{
+			"deviate": `
+			module deviate {
+				prefix "d";
+				namespace "urn:d";
+
+				container add {
+					leaf a {
+						type string;
+						must "../b";
+					}
+					leaf b { type string; }
+					list l {
+						key "k";
+						leaf k { type string; }
+						leaf x { type string; }
+						leaf y { type string; }
+						unique "x";
+					}
+				}
+
+				container delete {
+					leaf a {
+						type string;
+						must "../b";
+						must "../c";
+					}
+					leaf b { type string; }
+					leaf c { type string; }
+					list l {
+						key "k";
+						leaf k { type string; }
+						leaf x { type string; }
+						leaf y { type string; }
+						unique "x y";
+						unique "y";
+					}
+				}
+
+				deviation /add/a {
+					deviate add { must "../b != 'x'"; }
+				}
+				deviation /add/l {
+					deviate add { unique "y"; unique "x y"; }
+				}
+				deviation /delete/a {
+					deviate delete { must "../b"; }
+				}
+				deviation /delete/l {
+					deviate delete { unique "x   y"; unique "y"; }
+				}
+			}`,
+		},
+		wants: map[string][]deviationTest{
+			"deviate": {{
+				path:  "/add/a",
+				entry: &Entry{},
+				musts: []string{"../b", "../b != 'x'"},
+			}, {
+				path:   "/add/l",
+				entry:  &Entry{},
+				unique: []string{"x", "y", "x y"},
+			}, {
+				path:  "/delete/a",
+				entry: &Entry{},
+				musts: []string{"../c"},
+			}, {
+				path:   "/delete/l",
+				entry:  &Entry{},
+				unique: []string{},
+			}},
+		},
+	}, {
+		desc: "error case - deviation delete of a must that doesn't exist",
+		inFiles: map[string]string{
+			"deviate": `
+				module deviate {
+					prefix "d";
+					namespace "urn:d";
+
+					leaf a {
+						type string;
+						must "../b";
+					}
+					leaf b { type string; }
+
+					deviation /a {
+						deviate delete { must "../c"; }
+					}
+				}`,
+		},
+		wantProcessErrSubstring: `tried to deviate delete a must statement "../c" that doesn't exist`,
+	}, {
+		desc: "error case - deviation replace of a must",
+		inFiles: map[string]string{
+			"deviate": `
+				module deviate {
+					prefix "d";
+					namespace "urn:d";
+
+					leaf a {
+						type string;
+						must "../b";
+					}
+					leaf b { type string; }
+					leaf q { type string; }
+
+					deviation /a {
+						deviate replace { must "../q"; }
+					}
+				}`,
+		},
+		wantProcessErrSubstring: "tried to deviate replace a must statement, which may only be added or deleted",
+	}, {
+		desc: "error case - deviation replace of a unique",
+		inFiles: map[string]string{
+			"deviate": `
+				module deviate {
+					prefix "d";
+					namespace "urn:d";
+
+					list l {
+						key "k";
+						leaf k { type string; }
+						leaf x { type string; }
+						unique "k x";
+					}
+
+					deviation /l {
+						deviate replace { unique "x"; }
+					}
+				}`,
+		},
+		wantProcessErrSubstring: "tried to deviate replace a unique statement, which may only be added or deleted",
+	}, {
+		desc: "error case - deviation delete of a unique that doesn't exist",
+		inFiles: map[string]string{
+			"deviate": `
+				module deviate {
+					prefix "d";
+					namespace "urn:d";
+
+					list l {
+						key "k";
+						leaf k { type string; }
+						leaf x { type string; }
+					}
+
+					deviation /l {
+						deviate delete { unique "x"; }
+					}
+				}`,
+		},
+		wantProcessErrSubstring: `tried to deviate delete a unique statement "x" that doesn't exist`,
+	}, {
+		desc: "error case - deviation add of unique to a non-list",
+		inFiles: map[string]string{
+			"deviate": `
+				module deviate {
+					prefix "d";
+					namespace "urn:d";
+
+					container c {
+						leaf x { type string; }
+					}
+
+					deviation /c {
+						deviate add { unique "x"; }
+					}
+				}`,
+		},
+		wantProcessErrSubstring: "tried to deviate unique on a non-list type",
 	}}
 
 	for _, tt := range tests {
@@ -3623,6 +4813,26 @@ func TestDeviation(t *testing.T) {
 						continue
 					}
 
+					if want.musts != nil {
+						var gotMusts []string
+						for _, m := range got.Musts() {
+							gotMusts = append(gotMusts, m.Name)
+						}
+						if diff := cmp.Diff(want.musts, gotMusts, cmpopts.EquateEmpty()); diff != "" {
+							t.Errorf("%d (%s): did not get expected must statements, (-want, +got): %s", idx, want.path, diff)
+						}
+					}
+
+					if want.unique != nil {
+						var gotUnique []string
+						for _, u := range got.Extra["unique"] {
+							gotUnique = append(gotUnique, u.(*Value).Name)
+						}
+						if diff := cmp.Diff(want.unique, gotUnique, cmpopts.EquateEmpty()); diff != "" {
+							t.Errorf("%d (%s): did not get expected unique statements, (-want, +got): %s", idx, want.path, diff)
+						}
+					}
+
 					if got.Config != want.entry.Config {
 						t.Errorf("%d (%s): did not get expected config statement, got: %v, want: %v", idx, want.path, got.Config, want.entry.Config)
 					}
@@ -3671,18 +4881,99 @@ func TestDeviation(t *testing.T) {
 	}
 }
 
-func TestLeafEntry(t *testing.T) {
+func TestDeviatedRemovals(t *testing.T) {
 	tests := []struct {
-		name                string
-		inModules           map[string]string
-		wantEntryPath       string
-		wantEntryCustomTest func(t *testing.T, e *Entry)
-		wantErrSubstr       string
+		desc           string
+		inFiles        map[string]string
+		inParseOptions Options
+		inModule       string
+		wantRemovals   []string
+		wantGone       []string
 	}{{
-		name: "direct decimal64 type",
-		inModules: map[string]string{
-			"test.yang": `
-			module test {
+		desc:         "not-supported deviations within the same module",
+		inFiles:      map[string]string{"deviate": mustReadFile(filepath.Join("testdata", "deviate-notsupported.yang"))},
+		inModule:     "deviate",
+		wantRemovals: []string{"/deviate/target", "/deviate/target-list", "/deviate/a-leaf", "/deviate/a-leaflist"},
+		wantGone:     []string{"/target", "/target-list", "/a-leaf", "/a-leaflist"},
+	}, {
+		desc: "not-supported deviation in a device module",
+		inFiles: map[string]string{
+			"target": `
+				module target {
+					prefix "t";
+					namespace "urn:t";
+
+					container c {
+						leaf a { type string; }
+						leaf b { type string; }
+					}
+				}`,
+			"device": `
+				module device {
+					prefix "d";
+					namespace "urn:d";
+
+					import target { prefix t; }
+
+					deviation /t:c/t:b {
+						deviate not-supported;
+					}
+				}`,
+		},
+		inModule:     "device",
+		wantRemovals: []string{"/target/c/b"},
+	}, {
+		desc:    "not-supported deviations ignored by option",
+		inFiles: map[string]string{"deviate": mustReadFile(filepath.Join("testdata", "deviate-notsupported.yang"))},
+		inParseOptions: Options{
+			DeviateOptions: DeviateOptions{
+				IgnoreDeviateNotSupported: true,
+			},
+		},
+		inModule: "deviate",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ms := NewModules()
+			ms.ParseOptions = tt.inParseOptions
+			for name, mod := range tt.inFiles {
+				if err := ms.Parse(mod, name); err != nil {
+					t.Fatalf("cannot parse module %s, err: %v", name, err)
+				}
+			}
+			if errs := ms.Process(); len(errs) > 0 {
+				t.Fatalf("cannot process modules, errs: %v", errs)
+			}
+
+			m, errs := ms.GetModule(tt.inModule)
+			if errs != nil {
+				t.Fatalf("cannot get module %s, errs: %v", tt.inModule, errs)
+			}
+			for _, p := range tt.wantGone {
+				if e := m.Find(p); e != nil {
+					t.Errorf("entry %s was not removed", p)
+				}
+			}
+			if diff := cmp.Diff(tt.wantRemovals, m.DeviatedRemovals(), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("DeviatedRemovals (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLeafEntry(t *testing.T) {
+	tests := []struct {
+		name                string
+		inModules           map[string]string
+		wantEntryPath       string
+		wantEntryCustomTest func(t *testing.T, e *Entry)
+		wantErrSubstr       string
+	}{{
+		name: "direct decimal64 type",
+		inModules: map[string]string{
+			"test.yang": `
+			module test {
 				prefix "t";
 				namespace "urn:t";
 
@@ -4033,6 +5324,10 @@ func TestOrderedBy(t *testing.T) {
 				leaf-list unordered-leaflist2 {
 					type string;
 				}
+
+				container c {
+					leaf l { type string; }
+				}
 			}
 			`,
 		},
@@ -4042,6 +5337,9 @@ func TestOrderedBy(t *testing.T) {
 				if got, want := e.ListAttr.OrderedByUser, true; got != want {
 					t.Errorf("got %v, want %v", got, want)
 				}
+				if got, want := e.IsOrderedByUser(), true; got != want {
+					t.Errorf("IsOrderedByUser: got %v, want %v", got, want)
+				}
 			},
 		}, {
 			wantEntryPath: "/test/unordered-list",
@@ -4049,6 +5347,9 @@ func TestOrderedBy(t *testing.T) {
 				if got, want := e.ListAttr.OrderedByUser, false; got != want {
 					t.Errorf("got %v, want %v", got, want)
 				}
+				if got, want := e.IsOrderedByUser(), false; got != want {
+					t.Errorf("IsOrderedByUser: got %v, want %v", got, want)
+				}
 			},
 		}, {
 			wantEntryPath: "/test/unordered-list2",
@@ -4056,6 +5357,9 @@ func TestOrderedBy(t *testing.T) {
 				if got, want := e.ListAttr.OrderedByUser, false; got != want {
 					t.Errorf("got %v, want %v", got, want)
 				}
+				if got, want := e.IsOrderedByUser(), false; got != want {
+					t.Errorf("IsOrderedByUser: got %v, want %v", got, want)
+				}
 			},
 		}, {
 			wantEntryPath: "/test/ordered-leaflist",
@@ -4063,6 +5367,9 @@ func TestOrderedBy(t *testing.T) {
 				if got, want := e.ListAttr.OrderedByUser, true; got != want {
 					t.Errorf("got %v, want %v", got, want)
 				}
+				if got, want := e.IsOrderedByUser(), true; got != want {
+					t.Errorf("IsOrderedByUser: got %v, want %v", got, want)
+				}
 			},
 		}, {
 			wantEntryPath: "/test/unordered-leaflist",
@@ -4070,6 +5377,9 @@ func TestOrderedBy(t *testing.T) {
 				if got, want := e.ListAttr.OrderedByUser, false; got != want {
 					t.Errorf("got %v, want %v", got, want)
 				}
+				if got, want := e.IsOrderedByUser(), false; got != want {
+					t.Errorf("IsOrderedByUser: got %v, want %v", got, want)
+				}
 			},
 		}, {
 			wantEntryPath: "/test/unordered-leaflist2",
@@ -4077,6 +5387,23 @@ func TestOrderedBy(t *testing.T) {
 				if got, want := e.ListAttr.OrderedByUser, false; got != want {
 					t.Errorf("got %v, want %v", got, want)
 				}
+				if got, want := e.IsOrderedByUser(), false; got != want {
+					t.Errorf("IsOrderedByUser: got %v, want %v", got, want)
+				}
+			},
+		}, {
+			wantEntryPath: "/test/c",
+			wantEntryCustomTest: func(t *testing.T, e *Entry) {
+				if e.IsOrderedByUser() {
+					t.Errorf("IsOrderedByUser: got true for a container")
+				}
+			},
+		}, {
+			wantEntryPath: "/test/c/l",
+			wantEntryCustomTest: func(t *testing.T, e *Entry) {
+				if e.IsOrderedByUser() {
+					t.Errorf("IsOrderedByUser: got true for a leaf")
+				}
 			},
 		}},
 	}, {
@@ -4139,3 +5466,1659 @@ func TestOrderedBy(t *testing.T) {
 		})
 	}
 }
+
+func TestTypeSignature(t *testing.T) {
+	ms := NewModules()
+	if err := ms.Parse(`
+		module sig {
+			prefix "s";
+			namespace "urn:s";
+
+			identity base-id;
+
+			container c {
+				leaf plain-int { type uint16; }
+				leaf ranged-int { type uint16 { range "1..100"; } }
+				leaf multi-range { type int8 { range "-10..-1|1..10"; } }
+				leaf dec { type decimal64 { fraction-digits 2; } }
+				leaf ranged-dec { type decimal64 { fraction-digits 2; range "0..99.99"; } }
+				leaf str { type string; }
+				leaf pattern-str { type string { pattern "a.*"; length "1..24"; } }
+				leaf bin { type binary { length "4"; } }
+				leaf enum { type enumeration { enum B { value 1; } enum A { value 0; } enum C; } }
+				leaf flags { type bits { bit x { position 1; } bit y { position 0; } } }
+				leaf id { type identityref { base base-id; } }
+				leaf ref { type leafref { path "../str"; } }
+				leaf u {
+					type union {
+						type string;
+						type union {
+							type uint32;
+							type boolean;
+						}
+					}
+				}
+				leaf e { type empty; }
+			}
+		}`, "sig.yang"); err != nil {
+		t.Fatalf("cannot parse module: %v", err)
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process module: %v", errs)
+	}
+	mod, errs := ms.GetModule("sig")
+	if errs != nil {
+		t.Fatalf("cannot get module: %v", errs)
+	}
+
+	for leaf, want := range map[string]string{
+		"plain-int":   "uint16",
+		"ranged-int":  "uint16 [1..100]",
+		"multi-range": "int8 [-10..-1|1..10]",
+		"dec":         "decimal64 {fraction-digits 2}",
+		"ranged-dec":  "decimal64 {fraction-digits 2} [0.00..99.99]",
+		"str":         "string",
+		"pattern-str": "string {pattern 'a.*'} (length 1..24)",
+		"bin":         "binary (length 4)",
+		"enum":        "enumeration {A, B, C}",
+		"flags":       "bits {y, x}",
+		"id":          "identityref {base base-id}",
+		"ref":         "leafref {path ../str}",
+		"u":           "union<string, uint32, boolean>",
+		"e":           "empty",
+	} {
+		if got := mod.Dir["c"].Dir[leaf].TypeSignature(); got != want {
+			t.Errorf("%s: TypeSignature() got: %q, want: %q", leaf, got, want)
+		}
+	}
+	if got := mod.Dir["c"].TypeSignature(); got != "" {
+		t.Errorf("container: TypeSignature() got: %q, want: \"\"", got)
+	}
+}
+
+func TestChoiceDefaultValidation(t *testing.T) {
+	tests := []struct {
+		desc          string
+		inChoice      string
+		wantErrSubstr string
+	}{{
+		desc: "default names a case",
+		inChoice: `
+			choice x {
+				default b;
+				case a { leaf a1 { type string; } }
+				case b { leaf b1 { type string; } }
+			}`,
+	}, {
+		desc: "default names a shorthand case",
+		inChoice: `
+			choice x {
+				default b;
+				leaf a { type string; }
+				container b { leaf b1 { type string; } }
+			}`,
+	}, {
+		desc: "default names a case with a prefix",
+		inChoice: `
+			choice x {
+				default t:a;
+				case a { leaf a1 { type string; } }
+			}`,
+	}, {
+		desc: "default names a node within a case",
+		inChoice: `
+			choice x {
+				default a1;
+				case a { leaf a1 { type string; } }
+			}`,
+		wantErrSubstr: `default "a1" of choice /test/c/x is not one of its cases`,
+	}, {
+		desc: "default names a nonexistent case",
+		inChoice: `
+			choice x {
+				default nonexistent;
+				case a { leaf a1 { type string; } }
+			}`,
+		wantErrSubstr: `default "nonexistent" of choice /test/c/x is not one of its cases`,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ms := NewModules()
+			if err := ms.Parse(`
+				module test {
+					prefix "t";
+					namespace "urn:t";
+					container c {
+						`+tt.inChoice+`
+					}
+				}`, "test.yang"); err != nil {
+				t.Fatalf("cannot parse module: %v", err)
+			}
+			var err error
+			if errs := ms.Process(); len(errs) > 0 {
+				err = errs[0]
+			}
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestWalkData(t *testing.T) {
+	ms := NewModules()
+	if err := ms.Parse(`
+		module test {
+			yang-version 1.1;
+			prefix "t";
+			namespace "urn:t";
+			container c {
+				leaf z { type string; }
+				choice ch {
+					case one {
+						leaf b { type string; }
+					}
+					leaf-list a { type string; }
+				}
+				list l {
+					key "k";
+					leaf k { type string; }
+					anydata d;
+				}
+			}
+			leaf top { type string; }
+			rpc r {
+				input { leaf in { type string; } }
+				output { leaf out { type string; } }
+			}
+			notification n {
+				leaf note { type string; }
+			}
+		}`, "test.yang"); err != nil {
+		t.Fatalf("cannot parse module: %v", err)
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process module: %v", errs)
+	}
+	root := ToEntry(ms.Modules["test"])
+
+	tests := []struct {
+		desc  string
+		in    *Entry
+		prune string
+		want  []string
+	}{{
+		desc: "module",
+		in:   root,
+		want: []string{
+			"/test/c",
+			"/test/c/ch/a/a",
+			"/test/c/ch/one/b",
+			"/test/c/l",
+			"/test/c/l/d",
+			"/test/c/l/k",
+			"/test/c/z",
+			"/test/top",
+		},
+	}, {
+		desc: "container",
+		in:   root.Dir["c"].Dir["l"],
+		want: []string{
+			"/test/c/l",
+			"/test/c/l/d",
+			"/test/c/l/k",
+		},
+	}, {
+		desc:  "pruned",
+		in:    root,
+		prune: "/test/c/l",
+		want: []string{
+			"/test/c",
+			"/test/c/ch/a/a",
+			"/test/c/ch/one/b",
+			"/test/c/l",
+			"/test/c/z",
+			"/test/top",
+		},
+	}, {
+		desc: "rpc",
+		in:   root.Dir["r"],
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var got []string
+			tt.in.WalkData(func(e *Entry) bool {
+				got = append(got, e.Path())
+				return e.Path() != tt.prune
+			})
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("WalkData (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMandatoryDefault(t *testing.T) {
+	tests := []struct {
+		desc          string
+		inLeaf        string
+		wantErrSubstr string
+	}{{
+		desc:          "default and mandatory true",
+		inLeaf:        `leaf l { type string; default "x"; mandatory true; }`,
+		wantErrSubstr: "leaf l has both a default and mandatory true",
+	}, {
+		desc:   "default and mandatory false",
+		inLeaf: `leaf l { type string; default "x"; mandatory false; }`,
+	}, {
+		desc:   "typedef default and mandatory true",
+		inLeaf: `leaf l { type defstring; mandatory true; }`,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ms := NewModules()
+			if err := ms.Parse(`
+				module test {
+					prefix "t";
+					namespace "urn:t";
+					typedef defstring {
+						type string;
+						default "y";
+					}
+					container c {
+						`+tt.inLeaf+`
+					}
+				}`, "test.yang"); err != nil {
+				t.Fatalf("cannot parse module: %v", err)
+			}
+			var err error
+			if errs := ms.Process(); len(errs) > 0 {
+				err = errs[0]
+			}
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestEffectiveType(t *testing.T) {
+	ms := NewModules()
+	if err := ms.Parse(`
+		module test {
+			prefix "t";
+			namespace "urn:t";
+
+			container c {
+				leaf n { type string; }
+				leaf s { type int8; }
+				leaf u { type string; }
+			}
+
+			deviation /c/n {
+				deviate replace { type uint8 { range "1..10"; } }
+			}
+			deviation /c/n {
+				deviate replace { type uint32; }
+			}
+
+			deviation /c/s {
+				deviate replace { type string { pattern "a.*"; length "1..5"; } }
+			}
+			deviation /c/s {
+				deviate replace { type string { length "2..8"; } }
+			}
+		}`, "test.yang"); err != nil {
+		t.Fatalf("cannot parse module: %v", err)
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process module: %v", errs)
+	}
+	c := ToEntry(ms.Modules["test"]).Dir["c"]
+
+	tests := []struct {
+		desc           string
+		inLeaf         string
+		wantKind       TypeKind
+		wantRange      YangRange
+		wantLength     YangRange
+		wantNoPatterns bool
+	}{{
+		desc:      "last replacement's range",
+		inLeaf:    "n",
+		wantKind:  Yuint32,
+		wantRange: Uint32Range,
+	}, {
+		desc:           "no patterns from an earlier replacement",
+		inLeaf:         "s",
+		wantKind:       Ystring,
+		wantLength:     YangRange{R(2, 8)},
+		wantNoPatterns: true,
+	}, {
+		desc:     "not deviated",
+		inLeaf:   "u",
+		wantKind: Ystring,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got := c.Dir[tt.inLeaf].EffectiveType()
+			if got == nil {
+				t.Fatalf("EffectiveType: got nil type")
+			}
+			if got.Kind != tt.wantKind {
+				t.Errorf("EffectiveType: got kind %v, want %v", got.Kind, tt.wantKind)
+			}
+			if tt.wantRange != nil && !got.Range.Equal(tt.wantRange) {
+				t.Errorf("EffectiveType: got range %v, want %v", got.Range, tt.wantRange)
+			}
+			if tt.wantLength != nil && !got.Length.Equal(tt.wantLength) {
+				t.Errorf("EffectiveType: got length %v, want %v", got.Length, tt.wantLength)
+			}
+			if tt.wantNoPatterns && (len(got.Pattern) > 0 || len(got.POSIXPattern) > 0) {
+				t.Errorf("EffectiveType: got patterns %v %v, want none", got.Pattern, got.POSIXPattern)
+			}
+		})
+	}
+}
+
+func TestIsDataNode(t *testing.T) {
+	ms := NewModules()
+	if err := ms.Parse(`
+		module test {
+			yang-version 1.1;
+			prefix "t";
+			namespace "urn:t";
+			container c {
+				leaf l { type string; }
+				choice ch {
+					case a { anydata d; }
+				}
+			}
+			rpc r {
+				input { leaf in { type string; } }
+				output { container o { leaf out { type string; } } }
+			}
+			notification n {
+				leaf note { type string; }
+			}
+		}`, "test.yang"); err != nil {
+		t.Fatalf("cannot parse module: %v", err)
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process module: %v", errs)
+	}
+	root := ToEntry(ms.Modules["test"])
+	rpc := root.Dir["r"]
+
+	tests := []struct {
+		desc string
+		in   *Entry
+		want bool
+	}{
+		{"module", root, false},
+		{"container", root.Dir["c"], true},
+		{"data leaf", root.Dir["c"].Dir["l"], true},
+		{"choice", root.Dir["c"].Dir["ch"], false},
+		{"case", root.Dir["c"].Dir["ch"].Dir["a"], false},
+		{"anydata in case", root.Dir["c"].Dir["ch"].Dir["a"].Dir["d"], true},
+		{"rpc", rpc, false},
+		{"rpc input", rpc.RPC.Input, false},
+		{"rpc input leaf", rpc.RPC.Input.Dir["in"], false},
+		{"rpc output leaf", rpc.RPC.Output.Dir["o"].Dir["out"], false},
+		{"notification", root.Dir["n"], false},
+		{"notification leaf", root.Dir["n"].Dir["note"], true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.in.IsDataNode(); got != tt.want {
+			t.Errorf("%s: IsDataNode() = %v, want %v", tt.desc, got, tt.want)
+		}
+	}
+}
+
+func TestIsSingletonList(t *testing.T) {
+	ms := NewModules()
+	if err := ms.Parse(`
+		module test {
+			prefix "t";
+			namespace "urn:t";
+			list single {
+				key "k";
+				max-elements 1;
+				leaf k { type string; }
+			}
+			list unbounded {
+				key "k";
+				max-elements unbounded;
+				leaf k { type string; }
+			}
+			list unset {
+				key "k";
+				leaf k { type string; }
+			}
+			list two {
+				key "k";
+				max-elements 2;
+				leaf k { type string; }
+			}
+			leaf-list ll {
+				type string;
+				max-elements 1;
+			}
+			container c {}
+		}`, "test.yang"); err != nil {
+		t.Fatalf("cannot parse module: %v", err)
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process module: %v", errs)
+	}
+	root := ToEntry(ms.Modules["test"])
+
+	tests := []struct {
+		desc string
+		in   string
+		want bool
+	}{
+		{"max-elements 1", "single", true},
+		{"max-elements unbounded", "unbounded", false},
+		{"no max-elements", "unset", false},
+		{"max-elements 2", "two", false},
+		{"leaf-list with max-elements 1", "ll", false},
+		{"container", "c", false},
+	}
+	for _, tt := range tests {
+		if got := root.Dir[tt.in].IsSingletonList(); got != tt.want {
+			t.Errorf("%s: IsSingletonList() = %v, want %v", tt.desc, got, tt.want)
+		}
+	}
+}
+
+func TestIsKey(t *testing.T) {
+	ms := NewModules()
+	if err := ms.Parse(`
+		module test {
+			prefix "t";
+			namespace "urn:t";
+			list single {
+				key "k";
+				leaf k { type string; }
+				leaf v { type string; }
+			}
+			list composite {
+				key "a t:b  c";
+				leaf a { type string; }
+				leaf b { type string; }
+				leaf c { type string; }
+				leaf d { type string; }
+			}
+			container c {
+				leaf k { type string; }
+			}
+		}`, "test.yang"); err != nil {
+		t.Fatalf("cannot parse module: %v", err)
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process module: %v", errs)
+	}
+	root := ToEntry(ms.Modules["test"])
+
+	tests := []struct {
+		desc string
+		in   *Entry
+		want bool
+	}{
+		{"single key", root.Dir["single"].Dir["k"], true},
+		{"non-key leaf", root.Dir["single"].Dir["v"], false},
+		{"first leaf of composite key", root.Dir["composite"].Dir["a"], true},
+		{"prefixed leaf of composite key", root.Dir["composite"].Dir["b"], true},
+		{"last leaf of composite key", root.Dir["composite"].Dir["c"], true},
+		{"non-key leaf of composite key list", root.Dir["composite"].Dir["d"], false},
+		{"leaf in container", root.Dir["c"].Dir["k"], false},
+		{"list", root.Dir["single"], false},
+		{"module", root, false},
+	}
+	for _, tt := range tests {
+		if got := tt.in.IsKey(); got != tt.want {
+			t.Errorf("%s: IsKey() = %v, want %v", tt.desc, got, tt.want)
+		}
+	}
+}
+
+func TestKeyEntries(t *testing.T) {
+	ms := NewModules()
+	if err := ms.Parse(`
+		module test {
+			prefix "t";
+			namespace "urn:t";
+			list single {
+				key "k";
+				leaf k { type string; }
+				leaf v { type string; }
+			}
+			list composite {
+				key "b t:a";
+				leaf a { type string; }
+				leaf b { type string; }
+			}
+			list keyless {
+				config false;
+				leaf v { type string; }
+			}
+			list container-key {
+				key "c";
+				container c { }
+			}
+			list leaf-list-key {
+				key "ll";
+				leaf-list ll { type string; }
+			}
+			list missing-key {
+				key "k missing";
+				leaf k { type string; }
+			}
+			container c {
+				leaf k { type string; }
+			}
+		}`, "test.yang"); err != nil {
+		t.Fatalf("cannot parse module: %v", err)
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process module: %v", errs)
+	}
+	root := ToEntry(ms.Modules["test"])
+
+	tests := []struct {
+		desc          string
+		in            *Entry
+		want          []string
+		wantErrSubstr string
+	}{{
+		desc: "single key",
+		in:   root.Dir["single"],
+		want: []string{"/test/single/k"},
+	}, {
+		desc: "composite key in key statement order",
+		in:   root.Dir["composite"],
+		want: []string{"/test/composite/b", "/test/composite/a"},
+	}, {
+		desc: "list without keys",
+		in:   root.Dir["keyless"],
+	}, {
+		desc:          "key is a container",
+		in:            root.Dir["container-key"],
+		wantErrSubstr: "/test/container-key: key c is not a leaf",
+	}, {
+		desc:          "key is a leaf-list",
+		in:            root.Dir["leaf-list-key"],
+		wantErrSubstr: "/test/leaf-list-key: key ll is not a leaf",
+	}, {
+		desc:          "key is missing",
+		in:            root.Dir["missing-key"],
+		wantErrSubstr: "/test/missing-key: key missing is not a child of the list",
+	}, {
+		desc:          "not a list",
+		in:            root.Dir["c"],
+		wantErrSubstr: "/test/c: not a list",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := tt.in.KeyEntries()
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Fatalf("KeyEntries: %s", diff)
+			}
+			var paths []string
+			for _, e := range got {
+				paths = append(paths, e.Path())
+			}
+			if diff := cmp.Diff(tt.want, paths); diff != "" {
+				t.Errorf("KeyEntries (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestAugmentShadowsNode(t *testing.T) {
+	ms := NewModules()
+	for name, src := range map[string]string{
+		"base.yang": `
+			module base {
+				prefix "b";
+				namespace "urn:b";
+				container c {
+					leaf x { type string; }
+				}
+			}`,
+		"aug.yang": `
+			module aug {
+				prefix "a";
+				namespace "urn:a";
+				import base { prefix b; }
+				augment /b:c {
+					leaf x { type string; }
+				}
+			}`,
+	} {
+		if err := ms.Parse(src, name); err != nil {
+			t.Fatalf("cannot parse %s: %v", name, err)
+		}
+	}
+	errs := ms.Process()
+	if len(errs) != 1 {
+		t.Fatalf("Process: got errors %v, want exactly one", errs)
+	}
+	for _, want := range []string{
+		`aug.yang:6:5: augment of /b:c from module aug adds node "x" at aug.yang:7:6`,
+		`which shadows the node "x" defined at base.yang:6:6`,
+	} {
+		if diff := errdiff.Substring(errs[0], want); diff != "" {
+			t.Error(diff)
+		}
+	}
+}
+
+func TestWhenAlwaysFalse(t *testing.T) {
+	ms := NewModules()
+	if err := ms.Parse(`
+		module test {
+			prefix "t";
+			namespace "urn:t";
+
+			feature on;
+			feature off;
+			feature other;
+
+			container c {
+				leaf literal-false { type string; when "false()"; }
+				leaf not-true { type string; when "not( true() )"; }
+				leaf zero { type string; when "(0)"; }
+				leaf indeterminate { type string; when "../literal-false = 'x'"; }
+				leaf literal-true { type string; when "true()"; }
+				leaf disabled { type string; if-feature off; }
+				leaf disabled-prefix { type string; if-feature t:off; }
+				leaf enabled { type string; if-feature on; }
+				leaf unknown-feature { type string; if-feature other; }
+				leaf expression { type string; if-feature "off and on"; }
+				container disabled-container { if-feature off; }
+				leaf plain { type string; }
+			}
+		}`, "test.yang"); err != nil {
+		t.Fatalf("cannot parse module: %v", err)
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process module: %v", errs)
+	}
+	c := ToEntry(ms.Modules["test"]).Dir["c"]
+	features := map[string]bool{"on": true, "off": false}
+
+	for name, want := range map[string]bool{
+		"literal-false":      true,
+		"not-true":           true,
+		"zero":               true,
+		"indeterminate":      false,
+		"literal-true":       false,
+		"disabled":           true,
+		"disabled-prefix":    true,
+		"enabled":            false,
+		"unknown-feature":    false,
+		"expression":         false,
+		"disabled-container": true,
+		"plain":              false,
+	} {
+		if got := c.Dir[name].WhenAlwaysFalse(features); got != want {
+			t.Errorf("%s: WhenAlwaysFalse() = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestMandatoryDescendants(t *testing.T) {
+	ms := NewModules()
+	if err := ms.Parse(`
+		module test {
+			yang-version 1.1;
+			prefix "t";
+			namespace "urn:t";
+			container c {
+				leaf req { type string; mandatory true; }
+				leaf opt { type string; }
+				leaf-list some { type string; min-elements 1; }
+				leaf-list any { type string; }
+				container inner {
+					leaf deep { type string; mandatory true; }
+					anydata data { mandatory true; }
+				}
+				container p {
+					presence "enables p";
+					leaf in-presence { type string; mandatory true; }
+				}
+				list l {
+					key "k";
+					min-elements 2;
+					leaf k { type string; }
+					leaf in-list { type string; mandatory true; }
+				}
+				list optional-list {
+					key "k";
+					leaf k { type string; }
+				}
+				choice must-choose {
+					mandatory true;
+					leaf a { type string; }
+					leaf b { type string; }
+				}
+				choice may-choose {
+					case x { leaf x { type string; mandatory true; } }
+				}
+			}
+			notification n {
+				leaf note { type string; mandatory true; }
+			}
+		}`, "test.yang"); err != nil {
+		t.Fatalf("cannot parse module: %v", err)
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process module: %v", errs)
+	}
+	root := ToEntry(ms.Modules["test"])
+
+	tests := []struct {
+		desc string
+		in   *Entry
+		want []string
+	}{{
+		desc: "module",
+		in:   root,
+		want: []string{
+			"/test/c/inner/data",
+			"/test/c/inner/deep",
+			"/test/c/l",
+			"/test/c/must-choose",
+			"/test/c/req",
+			"/test/c/some",
+		},
+	}, {
+		desc: "presence container",
+		in:   root.Dir["c"].Dir["p"],
+		want: []string{"/test/c/p/in-presence"},
+	}, {
+		desc: "list",
+		in:   root.Dir["c"].Dir["l"],
+		want: []string{"/test/c/l/in-list"},
+	}, {
+		desc: "no mandatory descendants",
+		in:   root.Dir["c"].Dir["optional-list"],
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var got []string
+			for _, e := range tt.in.MandatoryDescendants() {
+				got = append(got, e.Path())
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("MandatoryDescendants (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestYang11Statements(t *testing.T) {
+	tests := []struct {
+		desc          string
+		inVersion     string
+		inBody        string
+		wantErrSubstr string
+	}{{
+		desc:      "anydata in yang 1.1",
+		inVersion: "yang-version 1.1;",
+		inBody:    "anydata d;",
+	}, {
+		desc:      "action in yang 1.1",
+		inVersion: `yang-version "1.1";`,
+		inBody:    "container c { action a; }",
+	}, {
+		desc:          "anydata in yang 1.0",
+		inVersion:     "yang-version 1;",
+		inBody:        "anydata d;",
+		wantErrSubstr: "test.yang:6:1: anydata d is only allowed in yang-version 1.1 modules",
+	}, {
+		desc:          "action without yang-version",
+		inBody:        "list l { action a; }",
+		wantErrSubstr: "test.yang:6:10: action a is only allowed in yang-version 1.1 modules",
+	}, {
+		desc:          "anydata in grouping without yang-version",
+		inBody:        "grouping g { anydata d; } container c { uses g; }",
+		wantErrSubstr: "test.yang:6:14: anydata d is only allowed in yang-version 1.1 modules",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ms := NewModules()
+			if err := ms.Parse(`module test {
+`+tt.inVersion+`
+prefix "t";
+namespace "urn:t";
+
+`+tt.inBody+`
+}`, "test.yang"); err != nil {
+				t.Fatalf("cannot parse module: %v", err)
+			}
+			var err error
+			if errs := ms.Process(); len(errs) > 0 {
+				err = fmt.Errorf("%v", errs)
+			}
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Errorf("Process: %s", diff)
+			}
+		})
+	}
+}
+
+func TestTypeChain(t *testing.T) {
+	ms := NewModules()
+	if err := ms.Parse(`
+		module test {
+			prefix "t";
+			namespace "urn:t";
+
+			typedef optical-dB {
+				type decimal64 { fraction-digits 2; }
+				units "dB";
+			}
+			typedef gain-adjustment-type {
+				type optical-dB { range "-10..10"; }
+			}
+
+			leaf gain { type gain-adjustment-type { range "0..5"; } }
+			leaf direct { type string; }
+			container c;
+		}`, "test.yang"); err != nil {
+		t.Fatalf("cannot parse module: %v", err)
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process module: %v", errs)
+	}
+	root := ToEntry(ms.Modules["test"])
+
+	tests := []struct {
+		desc       string
+		in         *Entry
+		wantNames  []string
+		wantRanges []YangRange
+	}{{
+		desc:      "two level typedef",
+		in:        root.Dir["gain"],
+		wantNames: []string{"gain-adjustment-type", "optical-dB", "decimal64"},
+		wantRanges: []YangRange{
+			{Rf(0, 500, 2)},
+			{Rf(-1000, 1000, 2)},
+			{Rf(MinInt64, MaxInt64, 2)},
+		},
+	}, {
+		desc:       "built-in type",
+		in:         root.Dir["direct"],
+		wantNames:  []string{"string"},
+		wantRanges: []YangRange{nil},
+	}, {
+		desc: "no type",
+		in:   root.Dir["c"],
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			chain := tt.in.TypeChain()
+			var gotNames []string
+			var gotRanges []YangRange
+			for _, y := range chain {
+				gotNames = append(gotNames, y.Name)
+				gotRanges = append(gotRanges, y.Range)
+			}
+			if diff := cmp.Diff(tt.wantNames, gotNames); diff != "" {
+				t.Errorf("TypeChain names (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantRanges, gotRanges); diff != "" {
+				t.Errorf("TypeChain ranges (-want, +got):\n%s", diff)
+			}
+			if len(chain) > 0 && chain[0] != tt.in.Type {
+				t.Errorf("TypeChain()[0] = %v, want the type of the entry", chain[0])
+			}
+		})
+	}
+}
+
+func TestEffectiveEnum(t *testing.T) {
+	ms := NewModules()
+	if err := ms.Parse(`
+		module test {
+			prefix "t";
+			namespace "urn:t";
+
+			typedef colour {
+				type enumeration {
+					enum red;
+					enum green;
+				}
+			}
+
+			container c {
+				leaf deviated {
+					type enumeration {
+						enum up;
+						enum down;
+					}
+				}
+				leaf typedef { type colour; }
+				leaf to-string {
+					type enumeration { enum a; }
+				}
+				leaf plain { type string; }
+				leaf un {
+					type union {
+						type colour;
+						type string;
+					}
+				}
+			}
+
+			deviation /c/deviated {
+				deviate replace {
+					type enumeration {
+						enum up { value 10; }
+						enum down { value 20; }
+						enum testing { value 30; }
+					}
+				}
+			}
+
+			deviation /c/to-string {
+				deviate replace { type string; }
+			}
+		}`, "test.yang"); err != nil {
+		t.Fatalf("cannot parse module: %v", err)
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process module: %v", errs)
+	}
+	c := ToEntry(ms.Modules["test"]).Dir["c"]
+
+	tests := []struct {
+		desc       string
+		in         string
+		wantOK     bool
+		wantValues map[string]int64
+	}{{
+		desc:       "deviated to a different enumeration",
+		in:         "deviated",
+		wantOK:     true,
+		wantValues: map[string]int64{"up": 10, "down": 20, "testing": 30},
+	}, {
+		desc:       "typedef of an enumeration",
+		in:         "typedef",
+		wantOK:     true,
+		wantValues: map[string]int64{"red": 0, "green": 1},
+	}, {
+		desc: "deviated to a string",
+		in:   "to-string",
+	}, {
+		desc: "string",
+		in:   "plain",
+	}, {
+		desc: "union",
+		in:   "un",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, ok := c.Dir[tt.in].EffectiveEnum()
+			if ok != tt.wantOK {
+				t.Fatalf("EffectiveEnum: got ok %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				if got != nil {
+					t.Errorf("EffectiveEnum: got %v, want nil", got)
+				}
+				return
+			}
+			if diff := cmp.Diff(tt.wantValues, got.NameMap()); diff != "" {
+				t.Errorf("EffectiveEnum (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOrderedChildNames(t *testing.T) {
+	ms := NewModules()
+	for name, src := range map[string]string{
+		"test.yang": `
+			module test {
+				prefix "t";
+				namespace "urn:t";
+				include test-sub;
+
+				grouping inner {
+					leaf g2 { type string; }
+					leaf g1 { type string; }
+				}
+				grouping outer {
+					leaf o { type string; }
+					uses inner;
+				}
+
+				container zulu {
+					leaf z { type string; }
+					uses outer;
+					leaf a { type string; }
+					choice ch {
+						leaf short { type string; }
+						case long { leaf l { type string; } }
+					}
+					container m { }
+				}
+				leaf yankee { type string; }
+				container c {
+					uses outer;
+				}
+			}`,
+		"test-sub.yang": `
+			submodule test-sub {
+				belongs-to test { prefix "t"; }
+				leaf bravo { type string; }
+			}`,
+		"aug.yang": `
+			module aug {
+				prefix "a";
+				namespace "urn:a";
+				import test { prefix t; }
+				augment "/t:zulu" {
+					leaf y { type string; }
+					leaf b { type string; }
+				}
+			}`,
+	} {
+		if err := ms.Parse(src, name); err != nil {
+			t.Fatalf("cannot parse %s: %v", name, err)
+		}
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process modules: %v", errs)
+	}
+	root := ToEntry(ms.Modules["test"])
+
+	tests := []struct {
+		desc string
+		in   *Entry
+		want []string
+	}{{
+		desc: "module with a submodule",
+		in:   root,
+		want: []string{"zulu", "yankee", "c", "bravo"},
+	}, {
+		desc: "uses and augments",
+		in:   root.Dir["zulu"],
+		want: []string{"z", "o", "g2", "g1", "a", "ch", "m", "b", "y"},
+	}, {
+		desc: "choice",
+		in:   root.Dir["zulu"].Dir["ch"],
+		want: []string{"short", "long"},
+	}, {
+		desc: "within a grouping",
+		in:   root.Dir["c"],
+		want: []string{"o", "g2", "g1"},
+	}, {
+		desc: "no children",
+		in:   root.Dir["yankee"],
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if diff := cmp.Diff(tt.want, tt.in.OrderedChildNames()); diff != "" {
+				t.Errorf("OrderedChildNames (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTypeName(t *testing.T) {
+	ms := NewModules()
+	for name, src := range map[string]string{
+		"test.yang": `
+			module test {
+				prefix "t";
+				namespace "urn:t";
+				import other { prefix o; }
+
+				typedef inner { type string; }
+				typedef outer { type inner { length "1..8"; } }
+				typedef either { type union { type int8; type inner; } }
+
+				container c {
+					leaf builtin { type string; }
+					leaf restricted-builtin { type uint8 { range "1..10"; } }
+					leaf typedef { type inner; }
+					leaf prefixed-typedef { type t:inner; }
+					leaf typedef-chain { type outer; }
+					leaf restricted-typedef { type inner { length "1..2"; } }
+					leaf imported-typedef { type o:counter; }
+					leaf inline-union { type union { type outer; type int8; } }
+					leaf typedef-union { type either; }
+					leaf ref { type leafref { path "../builtin"; } }
+					leaf-list list { type outer; }
+				}
+			}`,
+		"other.yang": `
+			module other {
+				prefix "o";
+				namespace "urn:o";
+				typedef counter { type uint64; }
+			}`,
+	} {
+		if err := ms.Parse(src, name); err != nil {
+			t.Fatalf("cannot parse %s: %v", name, err)
+		}
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process modules: %v", errs)
+	}
+	c := ToEntry(ms.Modules["test"]).Dir["c"]
+
+	tests := []struct {
+		in   *Entry
+		want string
+	}{
+		{c.Dir["builtin"], "string"},
+		{c.Dir["restricted-builtin"], "uint8"},
+		{c.Dir["typedef"], "inner"},
+		{c.Dir["prefixed-typedef"], "inner"},
+		{c.Dir["typedef-chain"], "outer"},
+		{c.Dir["restricted-typedef"], "inner"},
+		{c.Dir["imported-typedef"], "counter"},
+		{c.Dir["inline-union"], "union"},
+		{c.Dir["typedef-union"], "either"},
+		{c.Dir["ref"], "leafref"},
+		{c.Dir["list"], "outer"},
+		{c, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.in.TypeName(); got != tt.want {
+			t.Errorf("%s: TypeName: got %q, want %q", tt.in.Path(), got, tt.want)
+		}
+	}
+}
+
+func TestEnumTypeKey(t *testing.T) {
+	ms := NewModules()
+	if err := ms.Parse(`
+		module test {
+			prefix "t";
+			namespace "urn:t";
+
+			typedef colour {
+				type enumeration {
+					enum red;
+					enum green;
+				}
+			}
+
+			container c {
+				leaf a {
+					type enumeration {
+						enum red;
+						enum green;
+					}
+				}
+				leaf b {
+					type enumeration {
+						enum green { value 1; }
+						enum red { value 0; }
+					}
+				}
+				leaf-list c {
+					type enumeration {
+						enum red;
+						enum green;
+					}
+				}
+				leaf other-values {
+					type enumeration {
+						enum red { value 1; }
+						enum green { value 2; }
+					}
+				}
+				leaf other-names {
+					type enumeration {
+						enum red;
+						enum blue;
+					}
+				}
+				leaf extra-enum {
+					type enumeration {
+						enum red;
+						enum green;
+						enum blue;
+					}
+				}
+				leaf typedef { type colour; }
+				leaf plain { type string; }
+			}
+		}`, "test.yang"); err != nil {
+		t.Fatalf("cannot parse module: %v", err)
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process module: %v", errs)
+	}
+	c := ToEntry(ms.Modules["test"]).Dir["c"]
+
+	key := func(name string) string {
+		t.Helper()
+		k, ok := c.Dir[name].EnumTypeKey()
+		if !ok {
+			t.Fatalf("EnumTypeKey(%s): got no key", name)
+		}
+		return k
+	}
+
+	for _, name := range []string{"b", "c"} {
+		if got, want := key(name), key("a"); got != want {
+			t.Errorf("EnumTypeKey(%s): got %q, want the key of a, %q", name, got, want)
+		}
+	}
+	for _, name := range []string{"other-values", "other-names", "extra-enum"} {
+		if got := key(name); got == key("a") {
+			t.Errorf("EnumTypeKey(%s): got %q, the key of a", name, got)
+		}
+	}
+	for _, name := range []string{"typedef", "plain"} {
+		if got, ok := c.Dir[name].EnumTypeKey(); ok {
+			t.Errorf("EnumTypeKey(%s): got %q, want no key", name, got)
+		}
+	}
+}
+
+func TestMusts(t *testing.T) {
+	ms := NewModules()
+	for name, src := range map[string]string{
+		"test.yang": `
+			module test {
+				prefix "t";
+				namespace "urn:t";
+				grouping g {
+					leaf mtu {
+						type uint16;
+						must ". >= 64" {
+							error-message "mtu is too small";
+							error-app-tag "mtu-min";
+						}
+						must ". <= 9000";
+					}
+				}
+				container a { uses g; }
+				container b {
+					must "../a/mtu";
+					uses g;
+				}
+				container c {
+					leaf plain { type string; }
+				}
+			}`,
+		"aug.yang": `
+			module aug {
+				prefix "a";
+				namespace "urn:a";
+				import test { prefix t; }
+				augment "/t:c" {
+					uses t:g;
+				}
+			}`,
+	} {
+		if err := ms.Parse(src, name); err != nil {
+			t.Fatalf("cannot parse %s: %v", name, err)
+		}
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process modules: %v", errs)
+	}
+	root := ToEntry(ms.Modules["test"])
+
+	type must struct {
+		Cond, Message, AppTag string
+	}
+	mtuMusts := []must{
+		{". >= 64", "mtu is too small", "mtu-min"},
+		{". <= 9000", "", ""},
+	}
+	tests := []struct {
+		desc string
+		in   *Entry
+		want []must
+	}{
+		{"grouping used once", root.Dir["a"].Dir["mtu"], mtuMusts},
+		{"grouping used again", root.Dir["b"].Dir["mtu"], mtuMusts},
+		{"grouping used by an augment", root.Dir["c"].Dir["mtu"], mtuMusts},
+		{"container", root.Dir["b"], []must{{"../a/mtu", "", ""}}},
+		{"no must statements", root.Dir["c"].Dir["plain"], nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var got []must
+			for _, m := range tt.in.Musts() {
+				g := must{Cond: m.Name}
+				if m.ErrorMessage != nil {
+					g.Message = m.ErrorMessage.Name
+				}
+				if m.ErrorAppTag != nil {
+					g.AppTag = m.ErrorAppTag.Name
+				}
+				got = append(got, g)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Musts (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUnique(t *testing.T) {
+	tests := []struct {
+		desc          string
+		inUnique      string
+		want          [][]string
+		wantErrSubstr string
+	}{{
+		desc:     "single leaf",
+		inUnique: `unique "ip";`,
+		want:     [][]string{{"ip"}},
+	}, {
+		desc:     "multiple leaves",
+		inUnique: `unique "ip port";`,
+		want:     [][]string{{"ip", "port"}},
+	}, {
+		desc:     "multiple statements",
+		inUnique: `unique "ip port"; unique "t:c/name";`,
+		want:     [][]string{{"ip", "port"}, {"t:c/name"}},
+	}, {
+		desc:     "whitespace separated",
+		inUnique: "unique \"  ip\n\t  c/name  \";",
+		want:     [][]string{{"ip", "c/name"}},
+	}, {
+		desc:     "through choice and case",
+		inUnique: `unique "ch/one/a ch/b/b b";`,
+		want:     [][]string{{"ch/one/a", "ch/b/b", "b"}},
+	}, {
+		desc:          "missing leaf",
+		inUnique:      `unique "ip missing";`,
+		wantErrSubstr: `unique "ip missing" of list l: missing does not exist`,
+	}, {
+		desc:          "container",
+		inUnique:      `unique "c";`,
+		wantErrSubstr: `unique "c" of list l: c is not a leaf`,
+	}, {
+		desc:          "leaf-list",
+		inUnique:      `unique "ll";`,
+		wantErrSubstr: `unique "ll" of list l: ll is not a leaf`,
+	}, {
+		desc:          "absolute path",
+		inUnique:      `unique "/t:top/t:l/t:ip";`,
+		wantErrSubstr: "/t:top/t:l/t:ip is not a descendant schema node identifier",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ms := NewModules()
+			if err := ms.Parse(`
+				module test {
+					prefix "t";
+					namespace "urn:t";
+					container top {
+						list l {
+							key "k";
+							`+tt.inUnique+`
+							leaf k { type string; }
+							leaf ip { type string; }
+							leaf port { type uint16; }
+							leaf-list ll { type string; }
+							container c { leaf name { type string; } }
+							choice ch {
+								case one { leaf a { type string; } }
+								leaf b { type string; }
+							}
+						}
+					}
+				}`, "test.yang"); err != nil {
+				t.Fatalf("cannot parse module: %v", err)
+			}
+			var err error
+			if errs := ms.Process(); len(errs) > 0 {
+				err = fmt.Errorf("%v", errs)
+			}
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Fatalf("Process: %s", diff)
+			}
+			l := ToEntry(ms.Modules["test"]).Dir["top"].Dir["l"]
+			if tt.wantErrSubstr != "" {
+				if len(l.Errors) == 0 {
+					t.Errorf("list has no errors, want %q", tt.wantErrSubstr)
+				}
+				return
+			}
+			if diff := cmp.Diff(tt.want, l.Unique()); diff != "" {
+				t.Errorf("Unique (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDepthAndSubtreeSize(t *testing.T) {
+	ms := NewModules()
+	if err := ms.Parse(`
+		module test {
+			prefix "t";
+			namespace "urn:t";
+			container a {
+				container b {
+					leaf c { type string; }
+				}
+				choice ch {
+					case one {
+						leaf d { type string; }
+						leaf e { type string; }
+					}
+					leaf f { type string; }
+				}
+			}
+			rpc r {
+				input { leaf in { type string; } }
+			}
+		}`, "test.yang"); err != nil {
+		t.Fatalf("cannot parse module: %v", err)
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process module: %v", errs)
+	}
+	root := ToEntry(ms.Modules["test"])
+	a := root.Dir["a"]
+	ch := a.Dir["ch"]
+
+	tests := []struct {
+		desc            string
+		in              *Entry
+		wantDepth       int
+		wantSubtreeSize int
+	}{{
+		desc:      "module",
+		in:        root,
+		wantDepth: 0,
+		// test, a, b, c, ch, one, d, e, f (shorthand case), f, r,
+		// input, in, and the implicit output of r.
+		wantSubtreeSize: 14,
+	}, {
+		desc:            "container",
+		in:              a,
+		wantDepth:       1,
+		wantSubtreeSize: 9,
+	}, {
+		desc:            "leaf",
+		in:              a.Dir["b"].Dir["c"],
+		wantDepth:       3,
+		wantSubtreeSize: 1,
+	}, {
+		desc:            "choice",
+		in:              ch,
+		wantDepth:       2,
+		wantSubtreeSize: 6,
+	}, {
+		desc:            "leaf in case",
+		in:              ch.Dir["one"].Dir["d"],
+		wantDepth:       4,
+		wantSubtreeSize: 1,
+	}, {
+		desc:            "leaf in shorthand case",
+		in:              ch.Dir["f"].Dir["f"],
+		wantDepth:       4,
+		wantSubtreeSize: 1,
+	}, {
+		desc:            "rpc",
+		in:              root.Dir["r"],
+		wantDepth:       1,
+		wantSubtreeSize: 4,
+	}, {
+		desc:            "rpc input leaf",
+		in:              root.Dir["r"].RPC.Input.Dir["in"],
+		wantDepth:       3,
+		wantSubtreeSize: 1,
+	}, {
+		desc:      "nil",
+		wantDepth: -1,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if got := tt.in.Depth(); got != tt.wantDepth {
+				t.Errorf("Depth() = %d, want %d", got, tt.wantDepth)
+			}
+			if got := tt.in.SubtreeSize(); got != tt.wantSubtreeSize {
+				t.Errorf("SubtreeSize() = %d, want %d", got, tt.wantSubtreeSize)
+			}
+		})
+	}
+}
+
+func TestPresence(t *testing.T) {
+	ms := NewModules()
+	if err := ms.Parse(`
+		module test {
+			prefix "t";
+			namespace "urn:t";
+			grouping g {
+				container from-grouping { presence "grouped"; }
+			}
+			container c {
+				presence "enabled";
+				uses g;
+				container np { }
+				list l {
+					key "k";
+					leaf k { type string; }
+				}
+				leaf lf { type string; }
+			}
+		}`, "test.yang"); err != nil {
+		t.Fatalf("cannot parse module: %v", err)
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process module: %v", errs)
+	}
+	c := ToEntry(ms.Modules["test"]).Dir["c"]
+
+	tests := []struct {
+		desc         string
+		in           *Entry
+		want         string
+		wantPresence bool
+	}{{
+		desc:         "presence container",
+		in:           c,
+		want:         "enabled",
+		wantPresence: true,
+	}, {
+		desc:         "presence container from grouping",
+		in:           c.Dir["from-grouping"],
+		want:         "grouped",
+		wantPresence: true,
+	}, {
+		desc: "non-presence container",
+		in:   c.Dir["np"],
+	}, {
+		desc: "list",
+		in:   c.Dir["l"],
+	}, {
+		desc: "leaf",
+		in:   c.Dir["lf"],
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, gotPresence := tt.in.Presence()
+			if got != tt.want || gotPresence != tt.wantPresence {
+				t.Errorf("Presence() = %q, %v, want %q, %v", got, gotPresence, tt.want, tt.wantPresence)
+			}
+		})
+	}
+}
+
+func TestChildren(t *testing.T) {
+	ms := NewModules()
+	if err := ms.Parse(`
+		module test {
+			prefix "t";
+			namespace "urn:t";
+			container c {
+				leaf zulu { type string; }
+				leaf alpha { type string; }
+				container mike { }
+				choice ch {
+					leaf bravo { type string; }
+				}
+			}
+			rpc r {
+				input { leaf in { type string; } }
+				output { leaf out { type string; } }
+			}
+		}`, "test.yang"); err != nil {
+		t.Fatalf("cannot parse module: %v", err)
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process module: %v", errs)
+	}
+	root := ToEntry(ms.Modules["test"])
+	names := func(es []*Entry) []string {
+		var ns []string
+		for _, e := range es {
+			ns = append(ns, e.Name)
+		}
+		return ns
+	}
+
+	c := root.Dir["c"]
+	c.Dir["nil"] = nil
+	want := []string{"alpha", "ch", "mike", "zulu"}
+	for i := 0; i < 10; i++ {
+		if diff := cmp.Diff(want, names(c.Children())); diff != "" {
+			t.Fatalf("Children() (-want, +got):\n%s", diff)
+		}
+	}
+
+	// The input and output of an RPC are not children.
+	if got := root.Dir["r"].Children(); len(got) != 0 {
+		t.Errorf("Children() of an RPC = %v, want none", names(got))
+	}
+	if diff := cmp.Diff([]string{"in"}, names(root.Dir["r"].RPC.Input.Children())); diff != "" {
+		t.Errorf("Children() of input (-want, +got):\n%s", diff)
+	}
+	if got := root.Dir["c"].Dir["zulu"].Children(); len(got) != 0 {
+		t.Errorf("Children() of a leaf = %v, want none", names(got))
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+// This file implements the resolution of the use of an extension, such as
+// o:posix-pattern, to the extension statement that defines it.
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ExtensionDef returns the definition of the extension name within the
+// module with the prefix prefix. The prefix is first matched against the
+// prefixes the modules within ms declare for themselves, and otherwise against
+// the prefixes with which the modules within ms import other modules. The
+// extension may be defined within the module, or within one of its
+// submodules. An error is returned if the prefix does not identify exactly
+// one module, or if the module does not define the extension. Process should
+// be called first, so that imports and includes are resolved.
+func (ms *Modules) ExtensionDef(prefix, name string) (*Extension, error) {
+	var mods []*Module
+	add := func(m *Module) {
+		for _, om := range mods {
+			if om == m {
+				return
+			}
+		}
+		mods = append(mods, m)
+	}
+	for _, m := range sortedModules(ms.Modules) {
+		if m.GetPrefix() == prefix {
+			add(m)
+		}
+	}
+	if len(mods) == 0 {
+		for _, m := range sortedModules(ms.Modules) {
+			for _, i := range m.Import {
+				if i.Prefix != nil && i.Prefix.Name == prefix {
+					if im := ms.FindModule(i); im != nil {
+						add(im)
+					}
+				}
+			}
+		}
+	}
+	switch len(mods) {
+	case 0:
+		return nil, fmt.Errorf("no module with prefix %s", prefix)
+	case 1:
+	default:
+		var names []string
+		for _, m := range mods {
+			names = append(names, m.Name)
+		}
+		return nil, fmt.Errorf("prefix %s is ambiguous, it may refer to modules %s", prefix, strings.Join(names, ", "))
+	}
+	if ext := extensionDef(mods[0], name); ext != nil {
+		return ext, nil
+	}
+	return nil, fmt.Errorf("extension %s not found in module %s", name, mods[0].Name)
+}
+
+// extensionDef returns the extension name defined within m, or within the
+// submodules m includes, or nil if there is none.
+func extensionDef(m *Module, name string) *Extension {
+	for _, ext := range m.Extension {
+		if ext.Name == name {
+			return ext
+		}
+	}
+	for _, i := range m.Include {
+		if i.Module == nil {
+			continue
+		}
+		if ext := extensionDef(i.Module, name); ext != nil {
+			return ext
+		}
+	}
+	return nil
+}
+
+// YinElement returns true if the argument of the extension s is encoded as a
+// child element, rather than as an attribute, of the extension's element in
+// YIN, as given by the yin-element statement of its argument.
+func (s *Extension) YinElement() bool {
+	return s.Argument != nil && s.Argument.YinElement != nil && s.Argument.YinElement.Name == "true"
+}
@@ -0,0 +1,153 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+import (
+	"testing"
+
+	"github.com/openconfig/gnmi/errdiff"
+)
+
+func TestExtensionDef(t *testing.T) {
+	ms := NewModules()
+	for name, src := range map[string]string{
+		"ext.yang": `
+			module ext {
+				prefix "oc-ext";
+				namespace "urn:ext";
+				include ext-sub;
+				extension posix-pattern {
+					argument "pattern" { yin-element false; }
+				}
+				extension text {
+					argument "value" { yin-element true; }
+				}
+				extension flag;
+			}`,
+		"ext-sub.yang": `
+			submodule ext-sub {
+				belongs-to ext { prefix "oc-ext"; }
+				extension from-sub { argument "name"; }
+			}`,
+		"user.yang": `
+			module user {
+				prefix "u";
+				namespace "urn:u";
+				import ext { prefix o; }
+				leaf l {
+					type string;
+					o:posix-pattern "[a-z]+";
+				}
+			}`,
+		"other.yang": `
+			module other {
+				prefix "other";
+				namespace "urn:other";
+			}`,
+		"dup-one.yang": `
+			module dup-one {
+				prefix "d";
+				namespace "urn:d1";
+				import other { prefix x; }
+			}`,
+		"dup-two.yang": `
+			module dup-two {
+				prefix "d";
+				namespace "urn:d2";
+				import ext { prefix x; }
+			}`,
+	} {
+		if err := ms.Parse(src, name); err != nil {
+			t.Fatalf("cannot parse %s: %v", name, err)
+		}
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process modules: %v", errs)
+	}
+
+	tests := []struct {
+		desc           string
+		inPrefix       string
+		inName         string
+		wantArgument   string
+		wantYinElement bool
+		wantErrSubstr  string
+	}{{
+		desc:         "prefix of an import",
+		inPrefix:     "o",
+		inName:       "posix-pattern",
+		wantArgument: "pattern",
+	}, {
+		desc:           "prefix of the defining module",
+		inPrefix:       "oc-ext",
+		inName:         "text",
+		wantArgument:   "value",
+		wantYinElement: true,
+	}, {
+		desc:     "extension without an argument",
+		inPrefix: "oc-ext",
+		inName:   "flag",
+	}, {
+		desc:         "extension defined in a submodule",
+		inPrefix:     "o",
+		inName:       "from-sub",
+		wantArgument: "name",
+	}, {
+		desc:          "unknown extension",
+		inPrefix:      "o",
+		inName:        "missing",
+		wantErrSubstr: "extension missing not found in module ext",
+	}, {
+		desc:          "unknown prefix",
+		inPrefix:      "nope",
+		inName:        "posix-pattern",
+		wantErrSubstr: "no module with prefix nope",
+	}, {
+		desc:          "ambiguous module prefix",
+		inPrefix:      "d",
+		inName:        "posix-pattern",
+		wantErrSubstr: "prefix d is ambiguous, it may refer to modules dup-one, dup-two",
+	}, {
+		desc:          "ambiguous import prefix",
+		inPrefix:      "x",
+		inName:        "posix-pattern",
+		wantErrSubstr: "prefix x is ambiguous, it may refer to modules other, ext",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := ms.ExtensionDef(tt.inPrefix, tt.inName)
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Fatalf("ExtensionDef(%q, %q): %s", tt.inPrefix, tt.inName, diff)
+			}
+			if err != nil {
+				return
+			}
+			if got.Name != tt.inName {
+				t.Errorf("ExtensionDef(%q, %q): got extension %s", tt.inPrefix, tt.inName, got.Name)
+			}
+			var gotArgument string
+			if got.Argument != nil {
+				gotArgument = got.Argument.Name
+			}
+			if gotArgument != tt.wantArgument {
+				t.Errorf("ExtensionDef(%q, %q): got argument %q, want %q", tt.inPrefix, tt.inName, gotArgument, tt.wantArgument)
+			}
+			if got.YinElement() != tt.wantYinElement {
+				t.Errorf("ExtensionDef(%q, %q).YinElement() = %v, want %v", tt.inPrefix, tt.inName, got.YinElement(), tt.wantYinElement)
+			}
+		})
+	}
+}
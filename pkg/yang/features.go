@@ -0,0 +1,395 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+// This file implements the checking and evaluation of the features
+// referenced by if-feature statements.
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ifFeatureTokens returns the tokens of the if-feature expression s, which
+// are the parentheses, the operators and, or and not, and feature names.
+func ifFeatureTokens(s string) []string {
+	var tokens []string
+	for _, f := range strings.Fields(s) {
+		for f != "" {
+			i := strings.IndexAny(f, "()")
+			switch {
+			case i < 0:
+				tokens = append(tokens, f)
+				f = ""
+			case i > 0:
+				tokens = append(tokens, f[:i])
+				f = f[i:]
+			default:
+				tokens = append(tokens, f[:1])
+				f = f[1:]
+			}
+		}
+	}
+	return tokens
+}
+
+// ifFeatureNames returns the feature names, possibly prefixed, within the
+// if-feature expression s, in the order in which they appear. The operators
+// and, or and not, and parentheses, are skipped.
+func ifFeatureNames(s string) []string {
+	var names []string
+	for _, t := range ifFeatureTokens(s) {
+		switch t {
+		case "(", ")", "and", "or", "not":
+			continue
+		}
+		names = append(names, t)
+	}
+	return names
+}
+
+// EvaluateIfFeature returns the value of the if-feature expression expr,
+// given that the features named by enabled are enabled if they map to true.
+// Expressions use the YANG 1.1 grammar of feature names combined with the
+// operators not, and and or, in decreasing order of precedence, and
+// parentheses. A YANG 1.0 if-feature statement is a single feature name.
+// Features are looked up by their name both with and without their prefix,
+// and features that are not within enabled are disabled. An error is
+// returned if expr is not a valid expression.
+func EvaluateIfFeature(expr string, enabled map[string]bool) (bool, error) {
+	p := &ifFeatureParser{tokens: ifFeatureTokens(expr), enabled: enabled}
+	v, err := p.expr()
+	switch {
+	case err != nil:
+		return false, fmt.Errorf("invalid if-feature expression %q: %v", expr, err)
+	case p.pos < len(p.tokens):
+		return false, fmt.Errorf("invalid if-feature expression %q: unexpected %q", expr, p.tokens[p.pos])
+	}
+	return v, nil
+}
+
+// An ifFeatureParser evaluates an if-feature expression by recursive
+// descent.
+type ifFeatureParser struct {
+	tokens  []string
+	pos     int
+	enabled map[string]bool
+}
+
+// next returns the next token, or "" at the end of the expression.
+func (p *ifFeatureParser) next() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+// expr evaluates a term, optionally followed by "or" and an expression.
+func (p *ifFeatureParser) expr() (bool, error) {
+	v, err := p.term()
+	if err != nil {
+		return false, err
+	}
+	if p.next() == "or" {
+		p.pos++
+		w, err := p.expr()
+		return v || w, err
+	}
+	return v, nil
+}
+
+// term evaluates a factor, optionally followed by "and" and a term.
+func (p *ifFeatureParser) term() (bool, error) {
+	v, err := p.factor()
+	if err != nil {
+		return false, err
+	}
+	if p.next() == "and" {
+		p.pos++
+		w, err := p.term()
+		return v && w, err
+	}
+	return v, nil
+}
+
+// factor evaluates a negated factor, a parenthesized expression or a feature
+// name.
+func (p *ifFeatureParser) factor() (bool, error) {
+	t := p.next()
+	switch t {
+	case "":
+		return false, errors.New("unexpected end of expression")
+	case ")", "and", "or":
+		return false, fmt.Errorf("unexpected %q", t)
+	}
+	p.pos++
+	switch t {
+	case "not":
+		v, err := p.factor()
+		return !v, err
+	case "(":
+		v, err := p.expr()
+		if err != nil {
+			return false, err
+		}
+		if p.next() != ")" {
+			return false, errors.New("missing )")
+		}
+		p.pos++
+		return v, nil
+	}
+	if on, ok := p.enabled[t]; ok {
+		return on, nil
+	}
+	_, name := getPrefix(t)
+	return p.enabled[name], nil
+}
+
+// IfFeatureExpr returns the if-feature expression of e, which is the
+// conjunction of the expressions of each of its if-feature statements,
+// including those it inherits from uses and augment statements. Where there
+// is more than one, each is parenthesized, e.g., "(a or b) and (c)". An
+// empty string is returned if e has no if-feature statements. An error is
+// returned if any expression is invalid.
+func (e *Entry) IfFeatureExpr() (string, error) {
+	var exprs []string
+	for _, ex := range e.Extra["if-feature"] {
+		v, ok := ex.(*Value)
+		if !ok {
+			return "", fmt.Errorf("%s: if-feature has wrong type %T", e.Path(), ex)
+		}
+		if _, err := EvaluateIfFeature(v.Name, nil); err != nil {
+			return "", fmt.Errorf("%s: %v", Source(v), err)
+		}
+		exprs = append(exprs, v.Name)
+	}
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	for i, x := range exprs {
+		exprs[i] = "(" + x + ")"
+	}
+	return strings.Join(exprs, " and "), nil
+}
+
+// FeatureSet returns the features defined by the modules within ms, keyed by
+// the name of the defining module and the name of the feature, e.g.,
+// "ietf-interfaces:pre-provisioning". The features defined within a
+// submodule are keyed by the name of the module it belongs to. Only the
+// latest revision of each module is included. FeatureSet should be called
+// after Process.
+func (ms *Modules) FeatureSet() map[string]*Feature {
+	features := map[string]*Feature{}
+	for name, m := range ms.Modules {
+		if name != m.Name {
+			continue
+		}
+		seen := map[*Module]bool{}
+		var add func(sm *Module)
+		add = func(sm *Module) {
+			if sm == nil || seen[sm] {
+				return
+			}
+			seen[sm] = true
+			for _, f := range sm.Feature {
+				features[m.Name+":"+f.Name] = f
+			}
+			for _, i := range sm.Include {
+				add(i.Module)
+			}
+		}
+		add(m)
+	}
+	return features
+}
+
+// definedFeatures returns the names of the features defined by the module m,
+// including those defined within its submodules. If m is a submodule, the
+// features of the module it belongs to are returned, if that module is known.
+func definedFeatures(m *Module) map[string]bool {
+	if m.BelongsTo != nil && m.Modules != nil {
+		if bm := m.Modules.Modules[m.BelongsTo.Name]; bm != nil {
+			m = bm
+		}
+	}
+	features := map[string]bool{}
+	seen := map[*Module]bool{}
+	var add func(m *Module)
+	add = func(m *Module) {
+		if m == nil || seen[m] {
+			return
+		}
+		seen[m] = true
+		for _, f := range m.Feature {
+			features[f.Name] = true
+		}
+		for _, i := range m.Include {
+			add(i.Module)
+		}
+	}
+	add(m)
+	return features
+}
+
+// checkIfFeatures calls f with an error for each feature referenced by an
+// if-feature statement within the module or submodule m that is not defined
+// by m, or by the module that its prefix refers to. Every if-feature
+// statement of m is checked, including those within groupings that are not
+// used. Features that are referenced with the prefix of a module that is not
+// imported are also reported.
+func checkIfFeatures(m *Module, f func(error)) {
+	features := map[*Module]map[string]bool{}
+	var check func(s *Statement)
+	check = func(s *Statement) {
+		if s.Keyword == "if-feature" {
+			for _, name := range ifFeatureNames(s.Argument) {
+				pfx, fname := getPrefix(name)
+				fm := FindModuleByPrefix(m, pfx)
+				if fm == nil {
+					// An unknown module is reported when its import
+					// is processed.
+					if !m.hasImportPrefix(pfx) {
+						f(fmt.Errorf("%s: if-feature %q refers to feature %s with unknown prefix %q", s.Location(), s.Argument, name, pfx))
+					}
+					continue
+				}
+				if features[fm] == nil {
+					features[fm] = definedFeatures(fm)
+				}
+				if !features[fm][fname] {
+					f(fmt.Errorf("%s: if-feature %q refers to undefined feature %s", s.Location(), s.Argument, name))
+				}
+			}
+		}
+		for _, ss := range s.SubStatements() {
+			check(ss)
+		}
+	}
+	if m.Source != nil {
+		check(m.Source)
+	}
+}
+
+// hasImportPrefix returns true if m imports a module with the prefix pfx.
+func (m *Module) hasImportPrefix(pfx string) bool {
+	for _, i := range m.Import {
+		if i.Prefix != nil && i.Prefix.Name == pfx {
+			return true
+		}
+	}
+	return false
+}
+
+// PruneByFeatures removes from the entry trees of the modules within ms each
+// entry whose if-feature expression is false, given that the features named
+// by enabled are enabled if they map to true, along with its descendants.
+// Features are named within enabled by the name of the module that defines
+// them and their name, as in the keys of FeatureSet, e.g.,
+// "ietf-interfaces:pre-provisioning", or, if that is not present, by their
+// name alone. Prefixes are resolved within the module that contains the
+// if-feature statement, which need not be the module of the entry, such as
+// for an augment or grouping from another module. Features that are not
+// within enabled are disabled.
+//
+// Entries whose if-feature expressions cannot be evaluated are not removed,
+// and an error describing each of them is returned. PruneByFeatures modifies
+// the entries returned by ToEntry, so should be called after Process.
+func (ms *Modules) PruneByFeatures(enabled map[string]bool) error {
+	var msgs []string
+	seen := map[*Entry]bool{}
+	for _, name := range ms.SortedModuleNames() {
+		ToEntry(ms.Modules[name]).pruneByFeatures(enabled, seen, func(err error) {
+			msgs = append(msgs, err.Error())
+		})
+	}
+	if len(msgs) > 0 {
+		return fmt.Errorf("cannot prune by features: %s", strings.Join(msgs, "; "))
+	}
+	return nil
+}
+
+// pruneByFeatures removes the descendants of e whose if-feature expressions
+// are false given enabled, calling f with an error for each expression that
+// cannot be evaluated. seen records the entries already visited.
+func (e *Entry) pruneByFeatures(enabled map[string]bool, seen map[*Entry]bool, f func(error)) {
+	if e == nil || seen[e] {
+		return
+	}
+	seen[e] = true
+	names := make([]string, 0, len(e.Dir))
+	for name := range e.Dir {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ce := e.Dir[name]
+		on, err := ce.featuresEnabled(enabled)
+		switch {
+		case err != nil:
+			f(err)
+		case !on:
+			delete(e.Dir, name)
+			continue
+		}
+		ce.pruneByFeatures(enabled, seen, f)
+	}
+	if e.RPC != nil {
+		e.RPC.Input.pruneByFeatures(enabled, seen, f)
+		e.RPC.Output.pruneByFeatures(enabled, seen, f)
+	}
+}
+
+// featuresEnabled returns true if each of the if-feature statements of e is
+// true given enabled, as described by PruneByFeatures.
+func (e *Entry) featuresEnabled(enabled map[string]bool) (bool, error) {
+	for _, ex := range e.Extra["if-feature"] {
+		v, ok := ex.(*Value)
+		if !ok {
+			return false, fmt.Errorf("%s: if-feature has wrong type %T", e.Path(), ex)
+		}
+		// The features of the expression, as named within it, mapped to
+		// whether they are enabled.
+		features := map[string]bool{}
+		for _, name := range ifFeatureNames(v.Name) {
+			pfx, fname := getPrefix(name)
+			var m *Module
+			if RootNode(v) != nil {
+				m = FindModuleByPrefix(v, pfx)
+			}
+			if m == nil {
+				return false, fmt.Errorf("%s: if-feature %q: cannot find module for prefix %q", Source(v), v.Name, pfx)
+			}
+			mname := m.Name
+			if m.BelongsTo != nil {
+				mname = m.BelongsTo.Name
+			}
+			on, ok := enabled[mname+":"+fname]
+			if !ok {
+				on = enabled[fname]
+			}
+			features[name] = on
+		}
+		on, err := EvaluateIfFeature(v.Name, features)
+		if err != nil {
+			return false, fmt.Errorf("%s: %v", Source(v), err)
+		}
+		if !on {
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,336 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+)
+
+func TestEvaluateIfFeature(t *testing.T) {
+	enabled := map[string]bool{
+		"on":       true,
+		"off":      false,
+		"o:remote": true,
+		"local":    true,
+	}
+
+	tests := []struct {
+		desc          string
+		in            string
+		want          bool
+		wantErrSubstr string
+	}{
+		{desc: "enabled", in: "on", want: true},
+		{desc: "disabled", in: "off", want: false},
+		{desc: "not within enabled", in: "unknown", want: false},
+		{desc: "prefixed name", in: "o:remote", want: true},
+		{desc: "prefix is ignored if not within enabled", in: "t:local", want: true},
+		{desc: "negation", in: "not off", want: true},
+		{desc: "double negation", in: "not not off", want: false},
+		{desc: "and", in: "on and off", want: false},
+		{desc: "or", in: "off or on", want: true},
+		{desc: "and binds tighter than or", in: "on or on and off", want: true},
+		{desc: "parentheses", in: "(on or on) and off", want: false},
+		{desc: "parentheses without spaces", in: "not(off)and(o:remote or off)", want: true},
+		{desc: "not binds tighter than and", in: "not off and on", want: true},
+		{desc: "nested parentheses", in: "((off or (on and o:remote)))", want: true},
+		{desc: "empty", in: "", wantErrSubstr: "unexpected end of expression"},
+		{desc: "dangling operator", in: "on and", wantErrSubstr: "unexpected end of expression"},
+		{desc: "missing operator", in: "on off", wantErrSubstr: `unexpected "off"`},
+		{desc: "unbalanced open", in: "(on or off", wantErrSubstr: "missing )"},
+		{desc: "unbalanced close", in: "on)", wantErrSubstr: `unexpected ")"`},
+		{desc: "leading operator", in: "or on", wantErrSubstr: `unexpected "or"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := EvaluateIfFeature(tt.in, enabled)
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Fatalf("EvaluateIfFeature(%q): %s", tt.in, diff)
+			}
+			if got != tt.want {
+				t.Errorf("EvaluateIfFeature(%q): got %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFeatureSetAndIfFeatureExpr(t *testing.T) {
+	ms := NewModules()
+	for name, src := range map[string]string{
+		"test.yang": `
+			module test {
+				yang-version 1.1;
+				prefix "t";
+				namespace "urn:t";
+				import other { prefix o; }
+				include test-sub;
+				feature a;
+				feature b;
+				grouping g {
+					leaf gl { type string; }
+				}
+				container c {
+					if-feature "a or o:remote";
+					leaf plain { type string; }
+					leaf single { type string; if-feature t:b; }
+					uses g { if-feature "not sub"; }
+				}
+			}`,
+		"test-sub.yang": `
+			submodule test-sub {
+				yang-version 1.1;
+				belongs-to test { prefix t; }
+				feature sub;
+			}`,
+		"other.yang": `
+			module other {
+				prefix "o";
+				namespace "urn:o";
+				feature remote;
+			}`,
+	} {
+		if err := ms.Parse(src, name); err != nil {
+			t.Fatalf("cannot parse %s: %v", name, err)
+		}
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process modules: %v", errs)
+	}
+
+	var gotFeatures []string
+	for name, f := range ms.FeatureSet() {
+		if _, fname := getPrefix(name); fname != f.Name {
+			t.Errorf("FeatureSet: %s maps to feature %s", name, f.Name)
+		}
+		gotFeatures = append(gotFeatures, name)
+	}
+	sort.Strings(gotFeatures)
+	if diff := cmp.Diff([]string{"other:remote", "test:a", "test:b", "test:sub"}, gotFeatures); diff != "" {
+		t.Errorf("FeatureSet (-want, +got):\n%s", diff)
+	}
+
+	c := ToEntry(ms.Modules["test"]).Dir["c"]
+	tests := []struct {
+		desc    string
+		in      *Entry
+		want    string
+		enabled map[string]bool
+		wantOn  bool
+	}{{
+		desc:    "no if-feature",
+		in:      c.Dir["plain"],
+		want:    "",
+		enabled: nil,
+	}, {
+		desc:    "compound expression",
+		in:      c,
+		want:    "a or o:remote",
+		enabled: map[string]bool{"o:remote": true},
+		wantOn:  true,
+	}, {
+		desc:    "prefixed name",
+		in:      c.Dir["single"],
+		want:    "t:b",
+		enabled: map[string]bool{"b": true},
+		wantOn:  true,
+	}, {
+		desc:    "inherited from uses",
+		in:      c.Dir["gl"],
+		want:    "not sub",
+		enabled: map[string]bool{"sub": true},
+		wantOn:  false,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := tt.in.IfFeatureExpr()
+			if err != nil {
+				t.Fatalf("IfFeatureExpr: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("IfFeatureExpr: got %q, want %q", got, tt.want)
+			}
+			if got == "" {
+				return
+			}
+			on, err := EvaluateIfFeature(got, tt.enabled)
+			if err != nil {
+				t.Fatalf("EvaluateIfFeature(%q): %v", got, err)
+			}
+			if on != tt.wantOn {
+				t.Errorf("EvaluateIfFeature(%q): got %v, want %v", got, on, tt.wantOn)
+			}
+		})
+	}
+
+	// Multiple if-feature statements are combined with and.
+	e := &Entry{Extra: map[string][]interface{}{
+		"if-feature": {&Value{Name: "a or b"}, &Value{Name: "c"}},
+	}}
+	if got, err := e.IfFeatureExpr(); err != nil || got != "(a or b) and (c)" {
+		t.Errorf("IfFeatureExpr: got %q, %v, want %q, nil", got, err, "(a or b) and (c)")
+	}
+	e.Extra["if-feature"] = append(e.Extra["if-feature"], &Value{Name: "a or"})
+	if _, err := e.IfFeatureExpr(); err == nil {
+		t.Errorf("IfFeatureExpr: got no error for an invalid expression")
+	}
+}
+
+func TestPruneByFeatures(t *testing.T) {
+	srcs := map[string]string{
+		"test.yang": `
+			module test {
+				yang-version 1.1;
+				prefix "t";
+				namespace "urn:t";
+				import other { prefix o; }
+				feature a;
+				feature b;
+				container c {
+					if-feature a;
+					leaf l { type string; }
+					container inner {
+						leaf il { type string; }
+					}
+				}
+				container d {
+					leaf needs-b { type string; if-feature t:b; }
+					leaf needs-remote { type string; if-feature "o:remote"; }
+					leaf not-remote { type string; if-feature "not o:remote"; }
+					leaf plain { type string; }
+				}
+				rpc r {
+					input {
+						leaf in { type string; if-feature b; }
+					}
+				}
+			}`,
+		"other.yang": `
+			module other {
+				prefix "o";
+				namespace "urn:o";
+				import test { prefix t; }
+				feature remote;
+				augment "/t:d" {
+					if-feature remote;
+					leaf aug { type string; }
+				}
+			}`,
+	}
+
+	tests := []struct {
+		desc      string
+		inEnabled map[string]bool
+		want      []string
+	}{{
+		desc:      "all enabled",
+		inEnabled: map[string]bool{"test:a": true, "test:b": true, "other:remote": true},
+		want: []string{
+			"/test/c", "/test/c/inner", "/test/c/inner/il", "/test/c/l",
+			"/test/d", "/test/d/aug", "/test/d/needs-b", "/test/d/needs-remote", "/test/d/plain",
+			"/test/r", "/test/r/input", "/test/r/input/in",
+		},
+	}, {
+		desc:      "none enabled",
+		inEnabled: nil,
+		want: []string{
+			"/test/d", "/test/d/not-remote", "/test/d/plain",
+			"/test/r", "/test/r/input",
+		},
+	}, {
+		desc:      "disabled container is removed with its children",
+		inEnabled: map[string]bool{"test:a": false, "b": true, "other:remote": true},
+		want: []string{
+			"/test/d", "/test/d/aug", "/test/d/needs-b", "/test/d/needs-remote", "/test/d/plain",
+			"/test/r", "/test/r/input", "/test/r/input/in",
+		},
+	}, {
+		desc:      "prefix resolves to the imported module",
+		inEnabled: map[string]bool{"test:a": true, "test:remote": true},
+		want: []string{
+			"/test/c", "/test/c/inner", "/test/c/inner/il", "/test/c/l",
+			"/test/d", "/test/d/not-remote", "/test/d/plain",
+			"/test/r", "/test/r/input",
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ms := NewModules()
+			for name, src := range srcs {
+				if err := ms.Parse(src, name); err != nil {
+					t.Fatalf("cannot parse %s: %v", name, err)
+				}
+			}
+			if errs := ms.Process(); len(errs) > 0 {
+				t.Fatalf("cannot process modules: %v", errs)
+			}
+			if err := ms.PruneByFeatures(tt.inEnabled); err != nil {
+				t.Fatalf("PruneByFeatures: %v", err)
+			}
+			var got []string
+			var walk func(e *Entry)
+			walk = func(e *Entry) {
+				for _, ce := range e.Dir {
+					got = append(got, ce.Path())
+					walk(ce)
+				}
+				if e.RPC != nil && e.RPC.Input != nil {
+					got = append(got, e.RPC.Input.Path())
+					walk(e.RPC.Input)
+				}
+			}
+			walk(ToEntry(ms.Modules["test"]))
+			sort.Strings(got)
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("entries after PruneByFeatures (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPruneByFeaturesError(t *testing.T) {
+	ms := NewModules()
+	if err := ms.Parse(`
+		module test {
+			yang-version 1.1;
+			prefix "t";
+			namespace "urn:t";
+			feature a;
+			leaf bad { type string; if-feature "a or"; }
+			leaf good { type string; if-feature "not a"; }
+		}`, "test.yang"); err != nil {
+		t.Fatalf("cannot parse module: %v", err)
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process module: %v", errs)
+	}
+	err := ms.PruneByFeatures(map[string]bool{"test:a": true})
+	if diff := errdiff.Substring(err, `test.yang:7:28: invalid if-feature expression "a or"`); diff != "" {
+		t.Errorf("PruneByFeatures: %s", diff)
+	}
+	root := ToEntry(ms.Modules["test"])
+	if root.Dir["bad"] == nil {
+		t.Errorf("PruneByFeatures: entry with an invalid expression was removed")
+	}
+	if root.Dir["good"] != nil {
+		t.Errorf("PruneByFeatures: disabled entry was not removed")
+	}
+}
@@ -16,8 +16,10 @@ package yang
 
 import (
 	"fmt"
+	"io/fs"
 	"io/ioutil"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"sort"
@@ -69,6 +71,16 @@ func (ms *Modules) AddPath(paths ...string) {
 	}
 }
 
+// SetPath sets Path to the directories specified in paths, each a colon
+// separated list of directory names, replacing the directories previously in
+// Path. Each Modules has its own Path, so Modules with different paths may be
+// used concurrently.
+func (ms *Modules) SetPath(paths ...string) {
+	ms.Path = nil
+	ms.pathMap = map[string]bool{}
+	ms.AddPath(paths...)
+}
+
 // readFile makes testing of findFile easier.
 var readFile = ioutil.ReadFile
 
@@ -87,9 +99,23 @@ var scanDir = findInDir
 // subdirectories of dir are searched.
 //
 // The current directory (.) is always checked first, no matter the value of
-// Path.
+// Path, unless ms.Resolver is set and resolves name, which may be of the form
+// module or module@revision-date, to a file.
 func (ms *Modules) findFile(name string) (string, string, error) {
 	slash := strings.Index(name, "/")
+	if slash < 0 && !strings.HasSuffix(name, ".yang") && ms.Resolver != nil {
+		mname, rev := name, ""
+		if i := strings.Index(name, "@"); i >= 0 {
+			mname, rev = name[:i], name[i+1:]
+		}
+		if p, ok := ms.Resolver(mname, rev); ok {
+			data, err := readFile(p)
+			if err != nil {
+				return "", "", fmt.Errorf("cannot read %s resolved for %s: %v", p, name, err)
+			}
+			return p, string(data), nil
+		}
+	}
 	if slash < 0 && !strings.HasSuffix(name, ".yang") {
 		name += ".yang"
 		if best := scanDir(".", name, false); best != "" {
@@ -121,9 +147,44 @@ func (ms *Modules) findFile(name string) (string, string, error) {
 			return n, string(data), nil
 		}
 	}
+	for _, fsys := range ms.fsys {
+		if n, data, err := findInFS(fsys, name); err == nil {
+			return n, data, nil
+		}
+	}
 	return "", "", fmt.Errorf("no such file: %s", name)
 }
 
+// findInFS returns the name and contents of the .yang file associated with
+// name within fsys, or an error. As with findFile, .yang is appended to name
+// if it is a module name. A name containing a / must be the path of a file
+// within fsys. Otherwise, fsys is searched from its root, through all its
+// directories, for the file, or failing that, for the name@revision-date.yang
+// file with the latest revision-date.
+func findInFS(fsys fs.FS, name string) (string, string, error) {
+	if strings.Contains(name, "/") {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return "", "", fmt.Errorf("no such file: %s", name)
+		}
+		return name, string(data), nil
+	}
+	if !strings.HasSuffix(name, ".yang") {
+		name += ".yang"
+	}
+	n := scanEntries(func(dir string) ([]fs.DirEntry, error) {
+		return fs.ReadDir(fsys, dir)
+	}, path.Join, ".", name, true)
+	if n == "" {
+		return "", "", fmt.Errorf("no such file: %s", name)
+	}
+	data, err := fs.ReadFile(fsys, n)
+	if err != nil {
+		return "", "", fmt.Errorf("cannot read %s: %v", n, err)
+	}
+	return n, string(data), nil
+}
+
 // findInDir looks for a file named name in dir or any of its subdirectories if
 // recurse is true. if recurse is false, scan only the directory dir.
 // If no matching file is found, an empty string is returned.
@@ -138,23 +199,29 @@ func (ms *Modules) findFile(name string) (string, string, error) {
 // revision-date pattern exactly matching the above are found, then path of the
 // one with the latest date is returned.
 func findInDir(dir, name string, recurse bool) string {
-	fis, err := ioutil.ReadDir(dir)
+	return scanEntries(os.ReadDir, filepath.Join, dir, name, recurse)
+}
+
+// scanEntries implements findInDir over the directories read by readDir,
+// whose paths are joined by join.
+func scanEntries(readDir func(string) ([]fs.DirEntry, error), join func(...string) string, dir, name string, recurse bool) string {
+	des, err := readDir(dir)
 	if err != nil {
 		return ""
 	}
 
 	var revisions []string
 	mname := strings.TrimSuffix(name, ".yang")
-	for _, fi := range fis {
+	for _, de := range des {
 		switch {
-		case !fi.IsDir():
-			if fn := fi.Name(); fn == name {
-				return filepath.Join(dir, name)
+		case !de.IsDir():
+			if fn := de.Name(); fn == name {
+				return join(dir, name)
 			} else if strings.HasPrefix(fn, mname) && revisionDateSuffixRegex.MatchString(strings.TrimPrefix(fn, mname)) {
 				revisions = append(revisions, fn)
 			}
 		case recurse:
-			if n := findInDir(filepath.Join(dir, fi.Name()), name, recurse); n != "" {
+			if n := scanEntries(readDir, join, join(dir, de.Name()), name, recurse); n != "" {
 				return n
 			}
 		}
@@ -163,5 +230,5 @@ func findInDir(dir, name string, recurse bool) string {
 		return ""
 	}
 	sort.Strings(revisions)
-	return filepath.Join(dir, revisions[len(revisions)-1])
+	return join(dir, revisions[len(revisions)-1])
 }
@@ -16,11 +16,16 @@ package yang
 
 import (
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"reflect"
+	"sync"
 	"testing"
+	"testing/fstest"
+
+	"github.com/openconfig/gnmi/errdiff"
 )
 
 func TestFindFile(t *testing.T) {
@@ -164,3 +169,285 @@ func TestFindInDir(t *testing.T) {
 		})
 	}
 }
+
+func TestResolver(t *testing.T) {
+	// disable any readFile mock setup by other tests
+	readFile = ioutil.ReadFile
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"main-module.txt": `
+			module res {
+				prefix "r";
+				namespace "urn:r";
+				import dep { prefix "d"; revision-date 2020-01-01; }
+				leaf l { type d:t; }
+			}`,
+		"dependency": `
+			module dep {
+				prefix "d";
+				namespace "urn:d";
+				revision 2020-01-01;
+				typedef t { type string; }
+			}`,
+	}
+	for name, data := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	type request struct{ name, revision string }
+	var requests []request
+	ms := NewModules()
+	ms.Resolver = func(name, revision string) (string, bool) {
+		requests = append(requests, request{name, revision})
+		switch name {
+		case "res":
+			return filepath.Join(dir, "main-module.txt"), true
+		case "dep":
+			return filepath.Join(dir, "dependency"), true
+		}
+		return "", false
+	}
+
+	e, errs := ms.GetModule("res")
+	if errs != nil {
+		t.Fatalf("GetModule(res): %v", errs)
+	}
+	if got := e.Dir["l"].Type.Kind; got != Ystring {
+		t.Errorf("leaf l: got type %v, want %v", got, Ystring)
+	}
+	wantRequests := []request{{"res", ""}, {"dep", "2020-01-01"}}
+	if !reflect.DeepEqual(requests, wantRequests) {
+		t.Errorf("resolver requests: got %v, want %v", requests, wantRequests)
+	}
+
+	// Unresolved modules fall back to the normal search.
+	if _, errs := ms.GetModule("missing"); errs == nil {
+		t.Errorf("GetModule(missing): got no error, want error")
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	// disable any readFile mock setup by other tests
+	readFile = ioutil.ReadFile
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"pf@2020-01-01.yang": `
+			module pf {
+				prefix "p";
+				namespace "urn:p";
+				revision 2020-01-01;
+				leaf l { type string; }
+			}`,
+		"bad.yang": `
+			module bad {
+				prefix "b";
+				namespace "urn:b"
+			}`,
+	}
+	for name, data := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ms := NewModules()
+	if err := ms.ParseFile(filepath.Join(dir, "pf@2020-01-01.yang")); err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	for _, name := range []string{"pf", "pf@2020-01-01"} {
+		if ms.Modules[name] == nil {
+			t.Errorf("ParseFile: module %s not found", name)
+		}
+	}
+
+	badPath := filepath.Join(dir, "bad.yang")
+	if diff := errdiff.Substring(ms.ParseFile(badPath), badPath+":5:4"); diff != "" {
+		t.Errorf("ParseFile of invalid source: %s", diff)
+	}
+
+	missing := filepath.Join(dir, "missing.yang")
+	err := ms.ParseFile(missing)
+	if diff := errdiff.Substring(err, "cannot read "+missing); diff != "" {
+		t.Errorf("ParseFile of missing file: %s", diff)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("ParseFile of missing file: got error %v, want one wrapping %v", err, os.ErrNotExist)
+	}
+}
+
+func TestReadFromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"yang/base.yang": {Data: []byte(`
+			module base {
+				prefix "b";
+				namespace "urn:b";
+				import types { prefix t; }
+				include base-sub;
+				leaf l { type t:name; }
+			}`)},
+		"yang/base-sub.yang": {Data: []byte(`
+			submodule base-sub {
+				belongs-to base { prefix "b"; }
+				leaf s { type string; }
+			}`)},
+		"deps/types@2020-01-01.yang": {Data: []byte(`
+			module types {
+				prefix "t";
+				namespace "urn:t";
+				revision 2020-01-01;
+				typedef name { type int8; }
+			}`)},
+		"deps/types@2021-01-01.yang": {Data: []byte(`
+			module types {
+				prefix "t";
+				namespace "urn:t";
+				revision 2021-01-01;
+				typedef name { type string; }
+			}`)},
+		"bad.yang": {Data: []byte(`module bad {`)},
+	}
+
+	tests := []struct {
+		desc          string
+		inName        string
+		wantErrSubstr string
+	}{{
+		desc:   "module name",
+		inName: "base",
+	}, {
+		desc:   "file name",
+		inName: "base.yang",
+	}, {
+		desc:   "path",
+		inName: "yang/base.yang",
+	}, {
+		desc:          "module not found",
+		inName:        "missing",
+		wantErrSubstr: "no such file: missing.yang",
+	}, {
+		desc:          "path not found",
+		inName:        "base/base.yang",
+		wantErrSubstr: "no such file: base/base.yang",
+	}, {
+		desc:          "parse error",
+		inName:        "bad",
+		wantErrSubstr: "bad.yang:",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ms := NewModules()
+			err := ms.ReadFromFS(fsys, tt.inName)
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Fatalf("ReadFromFS: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			// The imports and includes are also read from fsys, taking
+			// the latest revision of types.
+			if errs := ms.Process(); len(errs) > 0 {
+				t.Fatalf("cannot process modules: %v", errs)
+			}
+			e, errs := ms.GetModule("base")
+			if len(errs) > 0 {
+				t.Fatalf("GetModule: %v", errs)
+			}
+			if got, want := e.Dir["l"].Type.Kind, Ystring; got != want {
+				t.Errorf("type of l is %v, want %v", got, want)
+			}
+			if e.Dir["s"] == nil {
+				t.Errorf("leaf s of the included submodule is missing")
+			}
+			if got, want := Source(ms.Modules["types"]), "deps/types@2021-01-01.yang:2:4"; got != want {
+				t.Errorf("types was read from %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestReadFromFSTwice(t *testing.T) {
+	// A fstest.MapFS is not comparable, so reading from it twice must not
+	// compare it with the filesystems already searched.
+	fsys := fstest.MapFS{
+		"a.yang": {Data: []byte(`module a { prefix "a"; namespace "urn:a"; leaf l { type string; } }`)},
+		"b.yang": {Data: []byte(`module b { prefix "b"; namespace "urn:b"; import a { prefix a; } leaf l { type string; } }`)},
+	}
+	ms := NewModules()
+	for _, name := range []string{"a", "b"} {
+		if err := ms.ReadFromFS(fsys, name); err != nil {
+			t.Fatalf("ReadFromFS(%q): %v", name, err)
+		}
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process modules: %v", errs)
+	}
+	for _, name := range []string{"a", "b"} {
+		if ms.Modules[name] == nil {
+			t.Errorf("module %s was not read", name)
+		}
+	}
+
+	// A filesystem of a comparable type is only searched once.
+	dir := os.DirFS(".")
+	ms = NewModules()
+	ms.addFS(dir)
+	ms.addFS(dir)
+	if got := len(ms.fsys); got != 1 {
+		t.Errorf("after adding os.DirFS twice, %d filesystems are searched, want 1", got)
+	}
+}
+
+func TestSetPath(t *testing.T) {
+	ms := NewModules()
+	ms.AddPath("a:b", "c")
+	ms.SetPath("d:a", "d")
+	if got, want := ms.Path, []string{"d", "a"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SetPath: got Path %v, want %v", got, want)
+	}
+	ms.AddPath("b")
+	if got, want := ms.Path, []string{"d", "a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("AddPath after SetPath: got Path %v, want %v", got, want)
+	}
+}
+
+func TestPathIsolation(t *testing.T) {
+	// Two directories contain different modules with the same name. Each
+	// Modules finds the module within its own path only.
+	var dirs []string
+	for _, ns := range []string{"urn:one", "urn:two"} {
+		dir := t.TempDir()
+		src := fmt.Sprintf(`module isolated { prefix i; namespace %q; }`, ns)
+		if err := ioutil.WriteFile(filepath.Join(dir, "isolated.yang"), []byte(src), 0644); err != nil {
+			t.Fatalf("cannot write module: %v", err)
+		}
+		dirs = append(dirs, dir)
+	}
+
+	var wg sync.WaitGroup
+	got := make([]string, 2*len(dirs))
+	for i := range got {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ms := NewModules()
+			ms.SetPath(dirs[i%len(dirs)])
+			e, errs := ms.GetModule("isolated")
+			if len(errs) > 0 {
+				t.Errorf("GetModule: %v", errs)
+				return
+			}
+			got[i] = e.Namespace().Name
+		}(i)
+	}
+	wg.Wait()
+
+	want := []string{"urn:one", "urn:two", "urn:one", "urn:two"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got namespaces %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+// This file implements the resolution of gNMI structured paths against an
+// Entry tree.
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A PathElem is a single element of a structured path, as used by gNMI.
+// Name is the name of a data node, optionally qualified with the name of the
+// module that defines it (e.g., "openconfig-interfaces:interfaces"). Keys
+// maps the names of the key leaves of a list to their values.
+type PathElem struct {
+	Name string
+	Keys map[string]string
+}
+
+// FindGNMI returns the entry referenced by the structured path elems,
+// relative to e. Choice and case entries are not data nodes and so do not
+// appear within elems. An error is returned if an element cannot be found,
+// if a module qualifier does not match the module of the entry, if the keys
+// of an element do not match the key leaves of a list, or if an element
+// other than the last refers to a list without specifying its keys.
+//
+// If e is a module entry then the first element may be qualified with the
+// name of another module, in which case it is resolved within that module.
+func (e *Entry) FindGNMI(elems []PathElem) (*Entry, error) {
+	for i, pe := range elems {
+		mod, name := getPrefix(pe.Name)
+		if i == 0 && e.Parent == nil && mod != "" && mod != e.Name {
+			m := e.Modules().Modules[mod]
+			if m == nil {
+				return nil, fmt.Errorf("%s: unknown module %q", pe.Name, mod)
+			}
+			e = ToEntry(m)
+		}
+
+		ce := e.findDataChild(name)
+		if ce == nil {
+			return nil, fmt.Errorf("%s: no such element within %s", pe.Name, e.Path())
+		}
+		if mod != "" {
+			if im, err := ce.InstantiatingModule(); err != nil || im != mod {
+				return nil, fmt.Errorf("%s: element %s is not defined in module %q", pe.Name, ce.Path(), mod)
+			}
+		}
+
+		switch {
+		case len(pe.Keys) > 0:
+			if !ce.IsList() {
+				return nil, fmt.Errorf("%s: keys specified for %s, which is not a list", pe.Name, ce.Path())
+			}
+			keys := ce.keyNames()
+			var got []string
+			for k := range pe.Keys {
+				got = append(got, k)
+			}
+			sort.Strings(keys)
+			sort.Strings(got)
+			if strings.Join(keys, " ") != strings.Join(got, " ") {
+				return nil, fmt.Errorf("%s: got keys %v for list %s, want %v", pe.Name, got, ce.Path(), keys)
+			}
+		case ce.IsList() && i != len(elems)-1:
+			return nil, fmt.Errorf("%s: no keys specified for list %s", pe.Name, ce.Path())
+		}
+		e = ce
+	}
+	return e, nil
+}
+
+// findDataChild returns the child data node of e named name, looking through
+// any intervening choice and case entries. It returns nil if no such child
+// exists.
+func (e *Entry) findDataChild(name string) *Entry {
+	if e.RPC != nil {
+		switch name {
+		case "input":
+			return e.RPC.Input
+		case "output":
+			return e.RPC.Output
+		}
+		return nil
+	}
+	if ce := e.Dir[name]; ce != nil && !ce.IsChoice() && !ce.IsCase() {
+		return ce
+	}
+	for _, ce := range e.Dir {
+		if ce.IsChoice() || ce.IsCase() {
+			if de := ce.findDataChild(name); de != nil {
+				return de
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+import (
+	"testing"
+
+	"github.com/openconfig/gnmi/errdiff"
+)
+
+func TestFindGNMI(t *testing.T) {
+	ms := NewModules()
+	for n, m := range map[string]string{
+		"ifs.yang": `
+			module ifs {
+				prefix "i";
+				namespace "urn:i";
+
+				container interfaces {
+					list interface {
+						key "name";
+						leaf name { type string; }
+
+						list subinterface {
+							key "index vlan";
+							leaf index { type uint32; }
+							leaf vlan { type uint16; }
+							leaf description { type string; }
+						}
+
+						choice mode {
+							case routed { leaf address { type string; } }
+						}
+					}
+				}
+			}`,
+		"ifs-aug.yang": `
+			module ifs-aug {
+				prefix "a";
+				namespace "urn:a";
+				import ifs { prefix "i"; }
+
+				augment "/i:interfaces/i:interface" {
+					leaf speed { type uint32; }
+				}
+			}`,
+	} {
+		if err := ms.Parse(m, n); err != nil {
+			t.Fatalf("cannot parse module %s: %v", n, err)
+		}
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process modules: %v", errs)
+	}
+	aug, errs := ms.GetModule("ifs-aug")
+	if errs != nil {
+		t.Fatalf("cannot get module ifs-aug: %v", errs)
+	}
+	ifs, errs := ms.GetModule("ifs")
+	if errs != nil {
+		t.Fatalf("cannot get module ifs: %v", errs)
+	}
+
+	tests := []struct {
+		desc          string
+		inEntry       *Entry
+		inElems       []PathElem
+		wantPath      string
+		wantErrSubstr string
+	}{{
+		desc:    "keyed list leaf",
+		inEntry: ifs,
+		inElems: []PathElem{
+			{Name: "interfaces"},
+			{Name: "interface", Keys: map[string]string{"name": "eth0"}},
+			{Name: "subinterface", Keys: map[string]string{"vlan": "10", "index": "0"}},
+			{Name: "description"},
+		},
+		wantPath: "/ifs/interfaces/interface/subinterface/description",
+	}, {
+		desc:    "module-qualified path from another module",
+		inEntry: aug,
+		inElems: []PathElem{
+			{Name: "ifs:interfaces"},
+			{Name: "interface", Keys: map[string]string{"name": "eth0"}},
+			{Name: "ifs-aug:speed"},
+		},
+		wantPath: "/ifs/interfaces/interface/speed",
+	}, {
+		desc:    "leaf within a choice",
+		inEntry: ifs,
+		inElems: []PathElem{
+			{Name: "interfaces"},
+			{Name: "interface", Keys: map[string]string{"name": "eth0"}},
+			{Name: "address"},
+		},
+		wantPath: "/ifs/interfaces/interface/mode/routed/address",
+	}, {
+		desc:    "whole list",
+		inEntry: ifs,
+		inElems: []PathElem{
+			{Name: "interfaces"},
+			{Name: "interface"},
+		},
+		wantPath: "/ifs/interfaces/interface",
+	}, {
+		desc:    "wrong key names",
+		inEntry: ifs,
+		inElems: []PathElem{
+			{Name: "interfaces"},
+			{Name: "interface", Keys: map[string]string{"id": "eth0"}},
+		},
+		wantErrSubstr: "got keys [id] for list /ifs/interfaces/interface, want [name]",
+	}, {
+		desc:    "missing key",
+		inEntry: ifs,
+		inElems: []PathElem{
+			{Name: "interfaces"},
+			{Name: "interface", Keys: map[string]string{"name": "eth0"}},
+			{Name: "subinterface", Keys: map[string]string{"index": "0"}},
+		},
+		wantErrSubstr: "got keys [index]",
+	}, {
+		desc:    "keys on a container",
+		inEntry: ifs,
+		inElems: []PathElem{
+			{Name: "interfaces", Keys: map[string]string{"name": "eth0"}},
+		},
+		wantErrSubstr: "which is not a list",
+	}, {
+		desc:    "list without keys before the last element",
+		inEntry: ifs,
+		inElems: []PathElem{
+			{Name: "interfaces"},
+			{Name: "interface"},
+			{Name: "name"},
+		},
+		wantErrSubstr: "no keys specified for list",
+	}, {
+		desc:    "wrong module qualifier",
+		inEntry: ifs,
+		inElems: []PathElem{
+			{Name: "interfaces"},
+			{Name: "interface", Keys: map[string]string{"name": "eth0"}},
+			{Name: "ifs:speed"},
+		},
+		wantErrSubstr: `is not defined in module "ifs"`,
+	}, {
+		desc:    "unknown element",
+		inEntry: ifs,
+		inElems: []PathElem{
+			{Name: "routing"},
+		},
+		wantErrSubstr: "no such element",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := tt.inEntry.FindGNMI(tt.inElems)
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Fatal(diff)
+			}
+			if err != nil {
+				return
+			}
+			if got.Path() != tt.wantPath {
+				t.Errorf("got path %s, want %s", got.Path(), tt.wantPath)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+// This file implements the scanning of the header of a module or submodule
+// without parsing its body.
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A ModuleHeader holds the statements from the header of a module or
+// submodule, as returned by ScanModuleHeader.
+type ModuleHeader struct {
+	Kind        string // "module" or "submodule".
+	Name        string
+	YangVersion string
+	Namespace   string // Only set for modules.
+	Prefix      string // For submodules, the prefix within belongs-to.
+	BelongsTo   string // Only set for submodules.
+	Revisions   []string
+	Imports     []string
+	Includes    []string
+}
+
+// ScanModuleHeader reads the module or submodule from r and returns its
+// header: the header, linkage, meta and revision statements that precede
+// the body of the module. The body is not parsed, and the module is not
+// otherwise validated, so ScanModuleHeader is considerably cheaper than
+// parsing and processing the module. An error is returned if the input does
+// not start with a module or submodule statement, or if a syntax error is
+// found within the header.
+func ScanModuleHeader(r io.Reader) (ModuleHeader, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return ModuleHeader{}, err
+	}
+	p := &parser{
+		lex:      newLexer(string(data), ""),
+		errout:   &bytes.Buffer{},
+		hitBrace: &Statement{},
+	}
+	p.lex.errout = p.errout
+
+	var h ModuleHeader
+	t := p.next()
+	if t.Code() != tUnquoted || (t.Text != "module" && t.Text != "submodule") {
+		return ModuleHeader{}, fmt.Errorf("%v: expected module or submodule", t)
+	}
+	h.Kind = t.Text
+	if t = p.next(); t.Code() != tString && t.Code() != tUnquoted {
+		return ModuleHeader{}, fmt.Errorf("%v: expected %s name", t, h.Kind)
+	}
+	h.Name = t.Text
+	if t = p.next(); t.Code() != '{' {
+		return ModuleHeader{}, fmt.Errorf("%v: expected '{'", t)
+	}
+
+Loop:
+	for {
+		s := p.nextStatement()
+		if p.errout.Len() > 0 {
+			return ModuleHeader{}, errors.New(strings.TrimSpace(p.errout.String()))
+		}
+		if s == nil || s == p.hitBrace {
+			break
+		}
+		switch s.Keyword {
+		case "yang-version":
+			h.YangVersion = s.Argument
+		case "namespace":
+			h.Namespace = s.Argument
+		case "prefix":
+			h.Prefix = s.Argument
+		case "belongs-to":
+			h.BelongsTo = s.Argument
+			for _, ss := range s.statements {
+				if ss.Keyword == "prefix" {
+					h.Prefix = ss.Argument
+				}
+			}
+		case "import":
+			h.Imports = append(h.Imports, s.Argument)
+		case "include":
+			h.Includes = append(h.Includes, s.Argument)
+		case "revision":
+			h.Revisions = append(h.Revisions, s.Argument)
+		case "organization", "contact", "description", "reference":
+		default:
+			// Extension statements may appear anywhere, any other
+			// statement starts the body.
+			if !strings.Contains(s.Keyword, ":") {
+				break Loop
+			}
+		}
+	}
+	return h, nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+)
+
+func TestScanModuleHeader(t *testing.T) {
+	tests := []struct {
+		desc          string
+		in            string
+		want          ModuleHeader
+		wantErrSubstr string
+	}{{
+		desc: "module",
+		in: `
+			module foo {
+				yang-version 1.1;
+				namespace "urn:foo";
+				prefix "f";
+				import bar { prefix b; }
+				import baz { prefix z; revision-date 2020-01-01; }
+				include foo-sub;
+				organization "Example";
+				oc-ext:openconfig-version "1.0.0";
+				description "The foo module.";
+				revision 2021-02-03 { description "Second."; }
+				revision "2020-01-01";
+
+				container c {
+					leaf l { type b:unresolved; }
+				}
+				; this is not valid YANG, but is never reached
+			}`,
+		want: ModuleHeader{
+			Kind:        "module",
+			Name:        "foo",
+			YangVersion: "1.1",
+			Namespace:   "urn:foo",
+			Prefix:      "f",
+			Revisions:   []string{"2021-02-03", "2020-01-01"},
+			Imports:     []string{"bar", "baz"},
+			Includes:    []string{"foo-sub"},
+		},
+	}, {
+		desc: "submodule",
+		in: `
+			submodule foo-sub {
+				belongs-to foo { prefix "f"; }
+				import bar { prefix b; }
+			}`,
+		want: ModuleHeader{
+			Kind:      "submodule",
+			Name:      "foo-sub",
+			Prefix:    "f",
+			BelongsTo: "foo",
+			Imports:   []string{"bar"},
+		},
+	}, {
+		desc:          "not a module",
+		in:            `container c { }`,
+		wantErrSubstr: "expected module or submodule",
+	}, {
+		desc: "syntax error in header",
+		in: `
+			module foo {
+				namespace "urn:foo"
+				prefix "f";
+			}`,
+		wantErrSubstr: "syntax error",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := ScanModuleHeader(strings.NewReader(tt.in))
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Fatalf("ScanModuleHeader: %s", diff)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ScanModuleHeader (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
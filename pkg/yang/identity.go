@@ -29,8 +29,9 @@ type identityDictionary struct {
 	mu sync.Mutex
 	// dict is a global cache of identities keyed by
 	// modulename:identityname, where modulename is the full name of the
-	// module to which the identity belongs. If the identity were defined
-	// in a submodule, then the parent module name is used instead.
+	// module to which the identity belongs, including its revision. If the
+	// identity were defined in a submodule, then the parent module name is
+	// used instead.
 	dict map[string]resolvedIdentity
 }
 
@@ -53,7 +54,16 @@ func newResolvedIdentity(m *Module, i *Identity) (string, *resolvedIdentity) {
 		Module:   m,
 		Identity: i,
 	}
-	return i.modulePrefixedName(), r
+	return identityKey(m, i.Name), r
+}
+
+// identityKey returns the key of the identity named name, defined within the
+// module or submodule m, within the identity dictionary. The key includes the
+// revision of the module, such that the identities of each revision of a
+// module are distinct, and an identity is resolved within the revision that
+// is imported.
+func identityKey(m *Module, name string) string {
+	return fmt.Sprintf("%s:%s", module(m).FullName(), name)
 }
 
 func appendIfNotIn(ids []*Identity, chk *Identity) []*Identity {
@@ -93,7 +103,7 @@ func (mod *Module) findIdentityBase(baseStr string) (*resolvedIdentity, []error)
 	case "", rootPrefix:
 		// This is a local identity which is defined within the current
 		// module
-		keyName := fmt.Sprintf("%s:%s", module(mod).Name, baseName)
+		keyName := identityKey(mod, baseName)
 		base, ok = typeDict.identities.dict[keyName]
 		if !ok {
 			errs = append(errs, fmt.Errorf("%s: can't resolve the local base %s as %s", source, baseStr, keyName))
@@ -106,8 +116,8 @@ func (mod *Module) findIdentityBase(baseStr string) (*resolvedIdentity, []error)
 				fmt.Errorf("%s: can't find external module with prefix %s", source, basePrefix))
 			break
 		}
-		// The identity we are looking for is modulename:basename.
-		if id, ok := typeDict.identities.dict[fmt.Sprintf("%s:%s", module(extmod).Name, baseName)]; ok {
+		// The identity we are looking for is modulename@revision:basename.
+		if id, ok := typeDict.identities.dict[identityKey(extmod, baseName)]; ok {
 			base = id
 			break
 		}
@@ -131,7 +141,7 @@ func (ms *Modules) resolveIdentities() []error {
 	// from them, and compile them into a "fully resolved" map that means that
 	// we can look them up based on the 'real' prefix of the module and the
 	// name of the identity.
-	for _, mod := range ms.Modules {
+	for _, mod := range sortedModules(ms.Modules) {
 		for _, i := range mod.Identities() {
 			keyName, r := newResolvedIdentity(mod, i)
 			ms.typeDict.identities.dict[keyName] = *r
@@ -155,8 +165,16 @@ func (ms *Modules) resolveIdentities() []error {
 	// A child identity here means an inherited identity.
 	//
 	// We start by finding the direct children of all identities using the
-	// 'base' statement.
-	for _, i := range ms.typeDict.identities.dict {
+	// 'base' statement. The identities are visited in the order of their
+	// keys so that the errors, and the order of the children, are the same
+	// every time.
+	keys := make([]string, 0, len(ms.typeDict.identities.dict))
+	for k := range ms.typeDict.identities.dict {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		i := ms.typeDict.identities.dict[k]
 		if i.Identity.Base != nil {
 			// This identity inherits from one or more other identities.
 
@@ -177,7 +195,8 @@ func (ms *Modules) resolveIdentities() []error {
 
 	// Now, we can find all transitive identities by recursively populating
 	// the children of each identity.
-	for _, i := range ms.typeDict.identities.dict {
+	for _, k := range keys {
+		i := ms.typeDict.identities.dict[k]
 		newValues := []*Identity{}
 		for _, j := range i.Identity.Values {
 			newValues = addChildren(j, newValues)
@@ -190,3 +209,19 @@ func (ms *Modules) resolveIdentities() []error {
 
 	return errs
 }
+
+// IdentityrefBase returns the base identity of the identityref leaf or
+// leaf-list e. The Values of the identity returned are all of the identities
+// that are derived from it, directly or transitively, within any of the
+// modules and submodules that were processed with the module of e. An error
+// is returned if the type of e is not an identityref, including if it is a
+// union.
+func (e *Entry) IdentityrefBase() (*Identity, error) {
+	switch {
+	case e.Type == nil || e.Type.Kind != Yidentityref:
+		return nil, fmt.Errorf("%s: not an identityref", e.Path())
+	case e.Type.IdentityBase == nil:
+		return nil, fmt.Errorf("%s: identityref has no resolved base", e.Path())
+	}
+	return e.Type.IdentityBase, nil
+}
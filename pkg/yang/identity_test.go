@@ -800,3 +800,173 @@ func TestIdentityTree(t *testing.T) {
 		})
 	}
 }
+
+func TestIdentityrefBase(t *testing.T) {
+	ms := NewModules()
+	for name, src := range map[string]string{
+		"base.yang": `
+			module base {
+				prefix "b";
+				namespace "urn:b";
+				identity b;
+				identity local { base b; }
+				typedef b-ref { type identityref { base b; } }
+				leaf direct { type identityref { base b; } }
+				leaf-list list { type identityref { base b; } }
+				leaf not-idref { type string; }
+				leaf in-union { type union { type string; type identityref { base b; } } }
+			}`,
+		"derived.yang": `
+			module derived {
+				prefix "d";
+				namespace "urn:d";
+				import base { prefix b; }
+				include derived-sub;
+				identity remote { base b:b; }
+				leaf via-typedef { type b:b-ref; }
+			}`,
+		"derived-sub.yang": `
+			submodule derived-sub {
+				belongs-to derived { prefix d; }
+				import base { prefix b; }
+				identity from-sub { base b:b; }
+			}`,
+		"transitive.yang": `
+			module transitive {
+				prefix "t";
+				namespace "urn:t";
+				import derived { prefix d; }
+				identity grandchild { base d:remote; }
+			}`,
+	} {
+		if err := ms.Parse(src, name); err != nil {
+			t.Fatalf("cannot parse %s: %v", name, err)
+		}
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process modules: %v", errs)
+	}
+	base := ToEntry(ms.Modules["base"])
+	derived := ToEntry(ms.Modules["derived"])
+
+	tests := []struct {
+		desc          string
+		in            *Entry
+		wantValues    []string
+		wantErrSubstr string
+	}{{
+		desc:       "leaf",
+		in:         base.Dir["direct"],
+		wantValues: []string{"from-sub", "grandchild", "local", "remote"},
+	}, {
+		desc:       "leaf-list",
+		in:         base.Dir["list"],
+		wantValues: []string{"from-sub", "grandchild", "local", "remote"},
+	}, {
+		desc:       "typedef from another module",
+		in:         derived.Dir["via-typedef"],
+		wantValues: []string{"from-sub", "grandchild", "local", "remote"},
+	}, {
+		desc:          "not an identityref",
+		in:            base.Dir["not-idref"],
+		wantErrSubstr: "not an identityref",
+	}, {
+		desc:          "union",
+		in:            base.Dir["in-union"],
+		wantErrSubstr: "not an identityref",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := tt.in.IdentityrefBase()
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Fatalf("IdentityrefBase: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if got.Name != "b" {
+				t.Errorf("IdentityrefBase: got identity %s, want b", got.Name)
+			}
+			var names []string
+			for _, v := range got.Values {
+				names = append(names, v.Name)
+			}
+			if diff := cmp.Diff(tt.wantValues, names); diff != "" {
+				t.Errorf("IdentityrefBase values (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCrossModuleIdentityDerivation(t *testing.T) {
+	srcs := []struct{ name, src string }{{
+		"a.yang", `
+			module a {
+				prefix "a";
+				namespace "urn:a";
+				identity a-base;
+			}`,
+	}, {
+		"b.yang", `
+			module b {
+				prefix "b";
+				namespace "urn:b";
+				import a { prefix a; }
+				identity b-derived { base a:a-base; }
+			}`,
+	}, {
+		"c.yang", `
+			module c {
+				prefix "c";
+				namespace "urn:c";
+				import b { prefix b; }
+				identity c-derived { base b:b-derived; }
+			}`,
+	}}
+
+	// The identities are linked regardless of the order in which the
+	// modules are parsed.
+	for _, order := range [][]int{{0, 1, 2}, {2, 1, 0}} {
+		ms := NewModules()
+		for _, i := range order {
+			if err := ms.Parse(srcs[i].src, srcs[i].name); err != nil {
+				t.Fatalf("cannot parse %s: %v", srcs[i].name, err)
+			}
+		}
+		if errs := ms.Process(); len(errs) > 0 {
+			t.Fatalf("cannot process modules: %v", errs)
+		}
+		identity := func(mod, name string) *Identity {
+			for _, i := range ms.Modules[mod].Identities() {
+				if i.Name == name {
+					return i
+				}
+			}
+			t.Fatalf("identity %s not found in module %s", name, mod)
+			return nil
+		}
+		aBase, bDerived, cDerived := identity("a", "a-base"), identity("b", "b-derived"), identity("c", "c-derived")
+
+		tests := []struct {
+			base    *Identity
+			name    string
+			want    *Identity
+			defined bool
+		}{
+			{aBase, "b-derived", bDerived, true},
+			{aBase, "c-derived", cDerived, true},
+			{bDerived, "c-derived", cDerived, true},
+			{bDerived, "a-base", nil, false},
+			{cDerived, "b-derived", nil, false},
+		}
+		for _, tt := range tests {
+			if got := tt.base.GetValue(tt.name); got != tt.want {
+				t.Errorf("order %v: %s.GetValue(%q): got %v, want %v", order, tt.base.Name, tt.name, got, tt.want)
+			}
+			if got := tt.base.IsDefined(tt.name); got != tt.defined {
+				t.Errorf("order %v: %s.IsDefined(%q): got %v, want %v", order, tt.base.Name, tt.name, got, tt.defined)
+			}
+		}
+	}
+}
@@ -0,0 +1,595 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+// This file implements the validation of RFC7951 JSON instance data against
+// an Entry tree.
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidateInstance validates data, a decoded RFC7951 JSON object, against
+// the configuration data schema rooted at e, and returns all of the errors
+// found. If e is a module then data contains its top-level nodes, if e is a
+// list then data is a single entry of the list, and otherwise data contains
+// the children of e. The errors reported are:
+//
+//   - members that are not configuration data nodes of the schema, or whose
+//     module qualifier is not the module that instantiates the node
+//   - leaf and leaf-list values that are not valid for their type, including
+//     its ranges, lengths, patterns, enums, bits and identity bases
+//   - missing mandatory leaves, anydata, anyxml and choices, and lists and
+//     leaf-lists with fewer than min-elements or more than max-elements
+//     entries
+//   - list entries with missing or duplicate keys
+//   - members from more than one case of the same choice
+//
+// Numbers may be decoded as float64 or, with json.Decoder.UseNumber, as
+// json.Number. Only the mandatory nodes of the case of a choice that is
+// present are required, and nodes with a when statement are never required,
+// as the expressions are not evaluated. Must statements are not evaluated.
+// Patterns, which are XSD regular expressions, are checked by translating
+// them to Go regular expressions, except for those that use character class
+// subtraction or Unicode block escapes (e.g., \p{IsBasicLatin}), which Go does
+// not support and are not checked. Each error is prefixed with the path of
+// the member within data.
+func (e *Entry) ValidateInstance(data map[string]interface{}) []error {
+	if e == nil {
+		return []error{fmt.Errorf("nil entry")}
+	}
+	errs := validateObject(e, data, "")
+	if e.IsList() {
+		errs = append(errs, validateKeys(e, data, "")...)
+	}
+	return errs
+}
+
+// validateObject validates obj as the children of e. path is the path of obj
+// within the instance data.
+func validateObject(e *Entry, obj map[string]interface{}, path string) []error {
+	var errs []error
+
+	// cases records the case of each choice that is present, and the first
+	// member that selected it.
+	type selection struct {
+		c      *Entry
+		member string
+	}
+	cases := map[*Entry]selection{}
+	present := map[*Entry]bool{}
+
+	var names []string
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p := path + "/" + name
+		mod, local := "", name
+		if i := strings.Index(name, ":"); i >= 0 {
+			mod, local = name[:i], name[i+1:]
+		}
+		ce := e.findDataChild(local)
+		if ce == nil || ce.RPC != nil || ce.Kind == NotificationEntry {
+			errs = append(errs, fmt.Errorf("%s: unknown member", p))
+			continue
+		}
+		if mod != "" {
+			if m, err := ce.InstantiatingModule(); err == nil && m != mod {
+				errs = append(errs, fmt.Errorf("%s: %s is defined by module %s, not %s", p, local, m, mod))
+				continue
+			}
+		}
+		if ce.ReadOnly() {
+			errs = append(errs, fmt.Errorf("%s: not configuration data", p))
+			continue
+		}
+		if present[ce] {
+			errs = append(errs, fmt.Errorf("%s: duplicate member", p))
+			continue
+		}
+		present[ce] = true
+
+		for c := ce; c.Parent != nil && c != e; c = c.Parent {
+			if !c.Parent.IsChoice() {
+				continue
+			}
+			s, ok := cases[c.Parent]
+			switch {
+			case !ok:
+				cases[c.Parent] = selection{c, name}
+			case s.c != c:
+				errs = append(errs, fmt.Errorf("%s: case %s of choice %s conflicts with case %s selected by %s", p, c.Name, c.Parent.Name, s.c.Name, s.member))
+			}
+		}
+
+		errs = append(errs, validateMember(ce, obj[name], p)...)
+	}
+
+	selected := map[*Entry]*Entry{}
+	for ch, s := range cases {
+		selected[ch] = s.c
+	}
+	return append(errs, validateMissing(e, present, selected, path)...)
+}
+
+// validateMissing returns an error for each mandatory child of e that is
+// not in present, looking through choices into the case that is selected,
+// and through containers that are not presence containers. path is the
+// path of the instance of e.
+func validateMissing(e *Entry, present map[*Entry]bool, selected map[*Entry]*Entry, path string) []error {
+	var errs []error
+	var names []string
+	for name := range e.Dir {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ce := e.Dir[name]
+		p := path + "/" + ce.JSONName()
+		if _, ok := ce.GetWhenXPath(); ok {
+			continue
+		}
+		switch {
+		case ce.RPC != nil, ce.Kind == NotificationEntry, ce.ReadOnly(), present[ce]:
+		case ce.IsChoice():
+			if c := selected[ce]; c != nil {
+				errs = append(errs, validateMissing(c, present, selected, path)...)
+			} else if ce.Mandatory == TSTrue {
+				errs = append(errs, fmt.Errorf("%s: no case of mandatory choice %s is present", rootPath(path), ce.Name))
+			}
+		case ce.IsCase():
+			// Cases are only reached through their choice.
+		case ce.ListAttr != nil:
+			if ce.ListAttr.MinElements > 0 {
+				errs = append(errs, fmt.Errorf("%s: missing, at least %d entries are required", p, ce.ListAttr.MinElements))
+			}
+		case ce.Kind == DirectoryEntry:
+			// A non-presence container exists whenever its parent does.
+			if len(ce.Extra["presence"]) == 0 {
+				errs = append(errs, validateObject(ce, map[string]interface{}{}, p)...)
+			}
+		case ce.Mandatory == TSTrue:
+			errs = append(errs, fmt.Errorf("%s: missing mandatory %s", p, ce.Name))
+		}
+	}
+	return errs
+}
+
+// validateMember validates the value v of the member for e at path.
+func validateMember(e *Entry, v interface{}, path string) []error {
+	switch {
+	case e.Kind == AnyDataEntry, e.Kind == AnyXMLEntry:
+		return nil
+	case e.IsList():
+		vs, ok := v.([]interface{})
+		if !ok {
+			return []error{fmt.Errorf("%s: list value is not an array", path)}
+		}
+		errs := validateCount(e, len(vs), path)
+		seen := map[string]int{}
+		for i, ev := range vs {
+			p := fmt.Sprintf("%s[%d]", path, i)
+			obj, ok := ev.(map[string]interface{})
+			if !ok {
+				errs = append(errs, fmt.Errorf("%s: list entry is not an object", p))
+				continue
+			}
+			errs = append(errs, validateObject(e, obj, p)...)
+			errs = append(errs, validateKeys(e, obj, p)...)
+			if e.Key == "" {
+				continue
+			}
+			var key []interface{}
+			for _, k := range e.keyNames() {
+				key = append(key, memberValue(obj, k))
+			}
+			ks := fmt.Sprintf("%#v", key)
+			if j, ok := seen[ks]; ok {
+				errs = append(errs, fmt.Errorf("%s: duplicate key of entry %d", p, j))
+				continue
+			}
+			seen[ks] = i
+		}
+		return errs
+	case e.IsDir():
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return []error{fmt.Errorf("%s: container value is not an object", path)}
+		}
+		return validateObject(e, obj, path)
+	case e.IsLeafList():
+		vs, ok := v.([]interface{})
+		if !ok {
+			return []error{fmt.Errorf("%s: leaf-list value is not an array", path)}
+		}
+		errs := validateCount(e, len(vs), path)
+		for i, lv := range vs {
+			if err := validateLeafValue(e, e.Type, lv); err != nil {
+				errs = append(errs, fmt.Errorf("%s[%d]: %v", path, i, err))
+			}
+		}
+		return errs
+	default:
+		if err := validateLeafValue(e, e.Type, v); err != nil {
+			return []error{fmt.Errorf("%s: %v", path, err)}
+		}
+		return nil
+	}
+}
+
+// validateCount returns an error if n entries of the list or leaf-list e
+// violate its min-elements or max-elements.
+func validateCount(e *Entry, n int, path string) []error {
+	switch la := e.ListAttr; {
+	case la == nil:
+	case uint64(n) < la.MinElements:
+		return []error{fmt.Errorf("%s: %d entries, at least %d are required", path, n, la.MinElements)}
+	case uint64(n) > la.MaxElements:
+		return []error{fmt.Errorf("%s: %d entries, at most %d are allowed", path, n, la.MaxElements)}
+	}
+	return nil
+}
+
+// validateKeys returns an error for each key of the list e that is missing
+// from the list entry obj.
+func validateKeys(e *Entry, obj map[string]interface{}, path string) []error {
+	var errs []error
+	for _, k := range e.keyNames() {
+		if memberValue(obj, k) == nil {
+			errs = append(errs, fmt.Errorf("%s: missing key %s", rootPath(path), k))
+		}
+	}
+	return errs
+}
+
+// memberValue returns the value of the member of obj named name, with or
+// without a module qualifier, or nil if there is none.
+func memberValue(obj map[string]interface{}, name string) interface{} {
+	if v, ok := obj[name]; ok {
+		return v
+	}
+	for n, v := range obj {
+		if i := strings.Index(n, ":"); i >= 0 && n[i+1:] == name {
+			return v
+		}
+	}
+	return nil
+}
+
+// rootPath returns path, or / if path is the root of the instance data.
+func rootPath(path string) string {
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+// validateLeafValue returns an error if v is not the RFC7951 representation
+// of a valid value of type t of the leaf or leaf-list e.
+func validateLeafValue(e *Entry, t *YangType, v interface{}) error {
+	if t == nil {
+		return nil
+	}
+	switch t.Kind {
+	case Yint8, Yint16, Yint32, Yuint8, Yuint16, Yuint32:
+		n, err := jsonInteger(v)
+		if err != nil {
+			return err
+		}
+		return validateRange(t, n)
+	case Yint64, Yuint64, Ydecimal64:
+		// RFC7951 section 6.1: 64-bit numbers are encoded as strings.
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%v is not a string encoded %s", v, t.Kind)
+		}
+		var n Number
+		var err error
+		if t.Kind == Ydecimal64 {
+			n, err = ParseDecimal(s, uint8(t.FractionDigits))
+		} else {
+			n, err = ParseInt(s)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid %s %q: %v", t.Kind, s, err)
+		}
+		return validateRange(t, n)
+	case Ystring:
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%v is not a string", v)
+		}
+		if err := validateLength(t, uint64(utf8.RuneCountInString(s))); err != nil {
+			return err
+		}
+		ms := entryModules(e)
+		for _, p := range t.Pattern {
+			re, err := compilePattern(ms, p, false)
+			switch {
+			case err == errUnsupportedPattern:
+			case err != nil:
+				return fmt.Errorf("invalid pattern %q: %v", p, err)
+			case t.PatternInverted(p) && re.MatchString(s):
+				return fmt.Errorf("%q matches inverted pattern %q", s, p)
+			case !t.PatternInverted(p) && !re.MatchString(s):
+				return fmt.Errorf("%q does not match pattern %q", s, p)
+			}
+		}
+		for _, p := range t.POSIXPattern {
+			re, err := compilePattern(ms, p, true)
+			if err != nil {
+				return fmt.Errorf("invalid pattern %q: %v", p, err)
+			}
+			if !re.MatchString(s) {
+				return fmt.Errorf("%q does not match pattern %q", s, p)
+			}
+		}
+	case Ybinary:
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%v is not a string", v)
+		}
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return fmt.Errorf("invalid binary %q: %v", s, err)
+		}
+		return validateLength(t, uint64(len(b)))
+	case Ybool:
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%v is not a boolean", v)
+		}
+	case Yempty:
+		if vs, ok := v.([]interface{}); !ok || len(vs) != 1 || vs[0] != nil {
+			return fmt.Errorf("%v is not an empty value, [null]", v)
+		}
+	case Yenum:
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%v is not a string", v)
+		}
+		if t.Enum != nil && !t.Enum.IsDefined(s) {
+			return fmt.Errorf("%q is not an enum of %s", s, t.Name)
+		}
+	case Ybits:
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%v is not a string", v)
+		}
+		for _, b := range strings.Fields(s) {
+			if t.Bit != nil && !t.Bit.IsDefined(b) {
+				return fmt.Errorf("%q is not a bit of %s", b, t.Name)
+			}
+		}
+	case Yidentityref:
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%v is not a string", v)
+		}
+		if _, name := getPrefix(s); t.IdentityBase != nil && !t.IdentityBase.IsDefined(name) {
+			return fmt.Errorf("%q is not derived from identity %s", s, t.IdentityBase.Name)
+		}
+	case Yunion:
+		for _, ut := range t.Type {
+			if validateLeafValue(e, ut, v) == nil {
+				return nil
+			}
+		}
+		return fmt.Errorf("%v is not valid for any member of union %s", v, t.Name)
+	case Yleafref:
+		// The target is only resolved for the type of e itself, not for
+		// leafrefs within a union.
+		if t != e.Type {
+			return nil
+		}
+		target, err := e.LeafrefTarget()
+		if err != nil {
+			return nil
+		}
+		return validateLeafValue(target, target.Type, v)
+	case YinstanceIdentifier:
+		if _, ok := v.(string); !ok {
+			return fmt.Errorf("%v is not a string", v)
+		}
+	}
+	return nil
+}
+
+// jsonInteger returns the integer v, decoded from a JSON number.
+func jsonInteger(v interface{}) (Number, error) {
+	switch v := v.(type) {
+	case float64:
+		if v != math.Trunc(v) || math.Abs(v) > math.MaxInt64 {
+			return Number{}, fmt.Errorf("%v is not an integer", v)
+		}
+		return FromInt(int64(v)), nil
+	case json.Number:
+		n, err := ParseInt(string(v))
+		if err != nil {
+			return Number{}, fmt.Errorf("%v is not an integer", v)
+		}
+		return n, nil
+	case int:
+		return FromInt(int64(v)), nil
+	case int64:
+		return FromInt(v), nil
+	}
+	return Number{}, fmt.Errorf("%v is not a number", v)
+}
+
+// validateRange returns an error if n is not within the range of t.
+func validateRange(t *YangType, n Number) error {
+	if !t.Range.Contains(YangRange{{n, n}}) {
+		return fmt.Errorf("%s is outside the range %s", n, t.Range)
+	}
+	return nil
+}
+
+// validateLength returns an error if the length n is not within the length
+// of t.
+func validateLength(t *YangType, n uint64) error {
+	l := FromUint(n)
+	if !t.Length.Contains(YangRange{{l, l}}) {
+		return fmt.Errorf("length %d is outside the length %s", n, t.Length)
+	}
+	return nil
+}
+
+// errUnsupportedPattern is returned by xsdRegexp for a pattern that uses a
+// feature of XSD regular expressions that Go does not support.
+var errUnsupportedPattern = errors.New("unsupported XSD regular expression")
+
+// A patternKey identifies a compiled pattern within the patterns of a
+// Modules.
+type patternKey struct {
+	pattern string
+	posix   bool
+}
+
+// A compiledPattern is the result of compiling a pattern.
+type compiledPattern struct {
+	re  *regexp.Regexp
+	err error
+}
+
+// entryModules returns the Modules that the tree of e was built from, or nil
+// if e is not within the tree of a module.
+func entryModules(e *Entry) *Modules {
+	if e == nil {
+		return nil
+	}
+	for e.Parent != nil {
+		e = e.Parent
+	}
+	if m, ok := e.Node.(*Module); ok {
+		return m.Modules
+	}
+	return nil
+}
+
+// compilePattern returns the compiled pattern p, which is a POSIX regular
+// expression if posix is true, and otherwise an XSD regular expression. The
+// expression must match the whole of a value. The compiled pattern is kept by
+// ms, if it is not nil, so that p is only compiled once for ms.
+func compilePattern(ms *Modules, p string, posix bool) (*regexp.Regexp, error) {
+	k := patternKey{pattern: p, posix: posix}
+	if ms != nil {
+		if c, ok := ms.patterns.Load(k); ok {
+			c := c.(compiledPattern)
+			return c.re, c.err
+		}
+	}
+	var c compiledPattern
+	if posix {
+		c.re, c.err = regexp.CompilePOSIX("^(" + p + ")$")
+	} else {
+		var s string
+		if s, c.err = xsdRegexp(p); c.err == nil {
+			c.re, c.err = regexp.Compile("^(?:" + s + ")$")
+		}
+	}
+	if ms != nil {
+		ms.patterns.Store(k, c)
+	}
+	return c.re, c.err
+}
+
+// xsdRegexp returns the XSD regular expression p, as defined by XML Schema
+// Part 2 appendix F and used by the pattern statement, translated to Go's
+// syntax. The multi-character escapes of XSD that Go lacks, or that differ
+// in Go, are replaced by equivalent classes, and "^" and "$", which are not
+// anchors in XSD, are escaped. errUnsupportedPattern is returned if p uses
+// character class subtraction or a Unicode block escape.
+func xsdRegexp(p string) (string, error) {
+	var b strings.Builder
+	rs := []rune(p)
+	inClass := false
+	for i := 0; i < len(rs); i++ {
+		switch c := rs[i]; {
+		case c == '\\' && i+1 < len(rs):
+			i++
+			// out is the translation of the escape outside a character
+			// class, and in its translation within one, or "" if it
+			// cannot be used within one.
+			var out, in string
+			switch e := rs[i]; e {
+			case 'i':
+				out, in = `[\p{L}_:]`, `\p{L}_:`
+			case 'I':
+				out = `[^\p{L}_:]`
+			case 'c':
+				out, in = `[\p{L}\p{Nd}._:\-]`, `\p{L}\p{Nd}._:\-`
+			case 'C':
+				out = `[^\p{L}\p{Nd}._:\-]`
+			case 'd':
+				out, in = `\p{Nd}`, `\p{Nd}`
+			case 'D':
+				out, in = `\P{Nd}`, `\P{Nd}`
+			case 'w':
+				out = `[^\p{P}\p{Z}\p{C}]`
+			case 'W':
+				out, in = `[\p{P}\p{Z}\p{C}]`, `\p{P}\p{Z}\p{C}`
+			case 'p', 'P':
+				if strings.HasPrefix(string(rs[i+1:]), "{Is") {
+					return "", errUnsupportedPattern
+				}
+				fallthrough
+			default:
+				out = `\` + string(e)
+				in = out
+			}
+			switch {
+			case !inClass:
+				b.WriteString(out)
+			case in == "":
+				return "", errUnsupportedPattern
+			default:
+				b.WriteString(in)
+			}
+		case inClass:
+			switch {
+			case c == '-' && i+1 < len(rs) && rs[i+1] == '[':
+				return "", errUnsupportedPattern
+			case c == '[':
+				b.WriteString(`\[`)
+			case c == ']':
+				inClass = false
+				b.WriteRune(c)
+			default:
+				b.WriteRune(c)
+			}
+		case c == '[':
+			inClass = true
+			b.WriteRune(c)
+		case c == '^' || c == '$':
+			b.WriteString(`\` + string(c))
+		case c == '.':
+			// The wildcard of XSD does not match carriage returns.
+			b.WriteString(`[^\n\r]`)
+		default:
+			b.WriteRune(c)
+		}
+	}
+	return b.String(), nil
+}
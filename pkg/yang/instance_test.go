@@ -0,0 +1,334 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValidateInstance(t *testing.T) {
+	ms := NewModules()
+	for name, src := range map[string]string{
+		"test.yang": `
+			module test {
+				yang-version 1.1;
+				prefix "t";
+				namespace "urn:t";
+				identity base-id;
+				identity derived { base base-id; }
+				container c {
+					leaf name { type string { length "1..8"; } mandatory true; }
+					leaf count { type uint8 { range "1..10"; } }
+					leaf big { type int64; }
+					leaf ratio { type decimal64 { fraction-digits 2; } }
+					leaf on { type boolean; }
+					leaf flag { type empty; }
+					leaf color { type enumeration { enum red; enum blue; } }
+					leaf perms { type bits { bit read; bit write; } }
+					leaf id { type identityref { base base-id; } }
+					leaf data { type binary { length "0..2"; } }
+					leaf either { type union { type uint8; type enumeration { enum none; } } }
+					leaf ref { type leafref { path "../count"; } }
+					leaf state { type string; config false; }
+					leaf-list tags { type string; max-elements 2; }
+					leaf code {
+						type string {
+							pattern '[A-Z]{2}\d+';
+							pattern 'XX.*' { modifier invert-match; }
+						}
+					}
+					leaf-list words { type string { pattern '\i\c*'; } }
+					leaf latin { type string { pattern '\p{IsBasicLatin}*'; } }
+					anydata any;
+					list l {
+						key "k";
+						min-elements 1;
+						leaf k { type string; }
+						leaf v { type int8; }
+					}
+					choice ch {
+						mandatory true;
+						case a { leaf a1 { type string; } leaf a2 { type string; } }
+						case b { leaf b1 { type string; } }
+					}
+					container inner {
+						leaf required { type string; mandatory true; }
+					}
+					container p {
+						presence "optional";
+						leaf required { type string; mandatory true; }
+					}
+				}
+			}`,
+		"aug.yang": `
+			module aug {
+				prefix "a";
+				namespace "urn:a";
+				import test { prefix t; }
+				augment "/t:c" {
+					leaf extra { type string; }
+				}
+			}`,
+	} {
+		if err := ms.Parse(src, name); err != nil {
+			t.Fatalf("cannot parse %s: %v", name, err)
+		}
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process modules: %v", errs)
+	}
+	root := ToEntry(ms.Modules["test"])
+
+	const valid = `{
+		"test:c": {
+			"name": "fish",
+			"count": 3,
+			"big": "-9000000000",
+			"ratio": "1.25",
+			"on": true,
+			"flag": [null],
+			"color": "blue",
+			"perms": "read write",
+			"id": "test:derived",
+			"data": "AAE=",
+			"either": "none",
+			"ref": 5,
+			"tags": ["x", "y"],
+			"code": "AB12",
+			"words": ["a-1", "_x", "b.c"],
+			"latin": "not checked: é",
+			"any": {"anything": [1, 2]},
+			"l": [{"k": "one", "v": -1}, {"k": "two"}],
+			"a1": "first",
+			"a2": "second",
+			"inner": {"required": "yes"},
+			"aug:extra": "augmented"
+		}
+	}`
+
+	tests := []struct {
+		desc string
+		in   *Entry
+		// patch is merged into the members of the container c of the
+		// valid document, or, if in is not the module, is the document.
+		patch string
+		want  []string
+	}{{
+		desc: "valid document",
+		in:   root,
+	}, {
+		desc:  "valid list entry",
+		in:    root.Dir["c"].Dir["l"],
+		patch: `{"k": "one", "v": 127}`,
+	}, {
+		desc:  "unknown members",
+		in:    root,
+		patch: `{"nope": 1, "test:extra": "x"}`,
+		want: []string{
+			"/test:c/nope: unknown member",
+			"/test:c/test:extra: extra is defined by module aug, not test",
+		},
+	}, {
+		desc:  "state data",
+		in:    root,
+		patch: `{"state": "x"}`,
+		want:  []string{"/test:c/state: not configuration data"},
+	}, {
+		desc: "invalid values",
+		in:   root,
+		patch: `{
+			"name": "much too long",
+			"count": 11,
+			"big": 1,
+			"ratio": "1.234",
+			"on": "true",
+			"flag": null,
+			"color": "green",
+			"perms": "read execute",
+			"id": "base-id",
+			"data": "AAEC",
+			"either": "all",
+			"ref": 0,
+			"tags": ["x", 1],
+			"code": "A1",
+			"words": ["ok", "1x"]
+		}`,
+		want: []string{
+			`/test:c/big: 1 is not a string encoded int64`,
+			`/test:c/code: "A1" does not match pattern "[A-Z]{2}\\d+"`,
+			`/test:c/color: "green" is not an enum of enumeration`,
+			`/test:c/count: 11 is outside the range 1..10`,
+			`/test:c/data: length 3 is outside the length 0..2`,
+			`/test:c/either: all is not valid for any member of union union`,
+			`/test:c/flag: <nil> is not an empty value, [null]`,
+			`/test:c/id: "base-id" is not derived from identity base-id`,
+			`/test:c/name: length 13 is outside the length 1..8`,
+			`/test:c/on: true is not a boolean`,
+			`/test:c/perms: "execute" is not a bit of bits`,
+			`/test:c/ratio: invalid decimal64 "1.234": 1.234 has too much precision, expect <= 2 fractional digits`,
+			`/test:c/ref: 0 is outside the range 1..10`,
+			`/test:c/tags[1]: 1 is not a string`,
+			`/test:c/words[1]: "1x" does not match pattern "\\i\\c*"`,
+		},
+	}, {
+		desc:  "inverted pattern",
+		in:    root,
+		patch: `{"code": "XX12"}`,
+		want:  []string{`/test:c/code: "XX12" matches inverted pattern "XX.*"`},
+	}, {
+		desc:  "missing mandatory nodes",
+		in:    root,
+		patch: `{"name": null, "l": null, "a1": null, "a2": null, "inner": {}, "p": {}}`,
+		want: []string{
+			"/test:c/inner/required: missing mandatory required",
+			"/test:c/p/required: missing mandatory required",
+			"/test:c: no case of mandatory choice ch is present",
+			"/test:c/l: missing, at least 1 entries are required",
+			"/test:c/name: missing mandatory name",
+		},
+	}, {
+		desc:  "absent non-presence container is validated",
+		in:    root,
+		patch: `{"inner": null}`,
+		want:  []string{"/test:c/inner/required: missing mandatory required"},
+	}, {
+		desc:  "cardinality",
+		in:    root,
+		patch: `{"tags": ["x", "y", "z"], "l": []}`,
+		want: []string{
+			"/test:c/l: 0 entries, at least 1 are required",
+			"/test:c/tags: 3 entries, at most 2 are allowed",
+		},
+	}, {
+		desc:  "list keys",
+		in:    root,
+		patch: `{"l": [{"k": "one"}, {"v": 1}, {"k": "one"}, "x"]}`,
+		want: []string{
+			"/test:c/l[1]: missing key k",
+			"/test:c/l[2]: duplicate key of entry 0",
+			"/test:c/l[3]: list entry is not an object",
+		},
+	}, {
+		desc:  "choice mutual exclusion",
+		in:    root,
+		patch: `{"b1": "second"}`,
+		want:  []string{"/test:c/b1: case b of choice ch conflicts with case a selected by a1"},
+	}, {
+		desc:  "wrong shapes",
+		in:    root,
+		patch: `{"inner": [], "l": {}, "tags": "x"}`,
+		want: []string{
+			"/test:c/inner: container value is not an object",
+			"/test:c/l: list value is not an array",
+			"/test:c/tags: leaf-list value is not an array",
+		},
+	}, {
+		desc:  "invalid list entry",
+		in:    root.Dir["c"].Dir["l"],
+		patch: `{"v": 128}`,
+		want: []string{
+			"/v: 128 is outside the range -128..127",
+			"/: missing key k",
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			var doc map[string]interface{}
+			if tt.in == root {
+				doc = decodeJSON(t, valid)
+				if tt.patch != "" {
+					c := doc["test:c"].(map[string]interface{})
+					for k, v := range decodeJSON(t, tt.patch) {
+						if v == nil && k != "flag" {
+							delete(c, k)
+							continue
+						}
+						c[k] = v
+					}
+				}
+			} else {
+				doc = decodeJSON(t, tt.patch)
+			}
+			var got []string
+			for _, err := range tt.in.ValidateInstance(doc) {
+				got = append(got, err.Error())
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ValidateInstance (-want, +got):\n%s", diff)
+			}
+		})
+	}
+
+	// Numbers may also be decoded as json.Number.
+	d := json.NewDecoder(bytes.NewBufferString(`{"k": "one", "v": 1.5}`))
+	d.UseNumber()
+	var doc map[string]interface{}
+	if err := d.Decode(&doc); err != nil {
+		t.Fatalf("cannot decode document: %v", err)
+	}
+	errs := root.Dir["c"].Dir["l"].ValidateInstance(doc)
+	if len(errs) != 1 || errs[0].Error() != "/v: 1.5 is not an integer" {
+		t.Errorf("ValidateInstance with json.Number: got %v, want [/v: 1.5 is not an integer]", errs)
+	}
+
+	// The patterns that were compiled are kept by ms.
+	if _, ok := ms.patterns.Load(patternKey{pattern: `[A-Z]{2}\d+`}); !ok {
+		t.Errorf("pattern %s is not kept by ms", `[A-Z]{2}\d+`)
+	}
+}
+
+func decodeJSON(t *testing.T, s string) map[string]interface{} {
+	t.Helper()
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(s), &m); err != nil {
+		t.Fatalf("cannot decode %s: %v", s, err)
+	}
+	return m
+}
+
+func TestXSDRegexp(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: `[a-z]+\.[0-9]{1,3}`, want: `[a-z]+\.[0-9]{1,3}`},
+		{in: `a^b$`, want: `a\^b\$`},
+		{in: `[^$^]`, want: `[^$^]`},
+		{in: `a.b`, want: `a[^\n\r]b`},
+		{in: `\i\c*`, want: `[\p{L}_:][\p{L}\p{Nd}._:\-]*`},
+		{in: `[\i\d-]`, want: `[\p{L}_:\p{Nd}-]`},
+		{in: `\d\D\w\W`, want: `\p{Nd}\P{Nd}[^\p{P}\p{Z}\p{C}][\p{P}\p{Z}\p{C}]`},
+		{in: `\p{Lu}\P{N}`, want: `\p{Lu}\P{N}`},
+		{in: `[a-z-[aeiou]]`, wantErr: true},
+		{in: `\p{IsGreek}`, wantErr: true},
+		{in: `[\w]`, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := xsdRegexp(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("xsdRegexp(%q): got error %v, want error %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("xsdRegexp(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
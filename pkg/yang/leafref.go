@@ -0,0 +1,412 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+// This file implements the resolution of the path statements of leafrefs
+// against the Entry tree.
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// A leafrefStep is a single step of a leafref path.
+type leafrefStep struct {
+	name string // name of the node, including any prefix, or "..".
+	pred string // predicate of the step, without the enclosing brackets.
+}
+
+// parseLeafrefPath parses the leafref path p into its steps, reporting
+// whether p is an absolute path.
+func parseLeafrefPath(p string) ([]leafrefStep, bool, error) {
+	p = strings.TrimSpace(p)
+	if p == "" {
+		return nil, false, fmt.Errorf("empty leafref path")
+	}
+	if strings.HasPrefix(p, "deref(") {
+		return nil, false, fmt.Errorf("leafref path %q: deref is not supported", p)
+	}
+	absolute := strings.HasPrefix(p, "/")
+	if absolute {
+		p = p[1:]
+	}
+
+	toks, err := xpathTokens(p)
+	if err != nil {
+		return nil, false, fmt.Errorf("leafref path %q: %v", p, err)
+	}
+	var steps []leafrefStep
+	// start is the offset of the first token of the current step, or -1
+	// if the step has no tokens yet, and end the offset after its last.
+	start, end := -1, 0
+	endStep := func() {
+		if start >= 0 {
+			steps = append(steps, leafrefStep{name: p[start:end]})
+			start = -1
+		}
+	}
+	for i := 0; i < len(toks); i++ {
+		switch t := toks[i]; {
+		case t.is("["):
+			depth := 1
+			j := i + 1
+			for ; j < len(toks) && depth > 0; j++ {
+				switch {
+				case toks[j].is("["):
+					depth++
+				case toks[j].is("]"):
+					depth--
+				}
+			}
+			if depth != 0 {
+				return nil, false, fmt.Errorf("leafref path %q: unterminated predicate", p)
+			}
+			endStep()
+			if len(steps) == 0 {
+				return nil, false, fmt.Errorf("leafref path %q: predicate without a node", p)
+			}
+			pred := strings.TrimSpace(p[t.end():toks[j-1].pos])
+			last := &steps[len(steps)-1]
+			if last.pred != "" {
+				last.pred += " and "
+			}
+			last.pred += pred
+			i = j - 1
+		case t.is("/"):
+			endStep()
+		case t.is("//"):
+			return nil, false, fmt.Errorf("leafref path %q: empty step", p)
+		default:
+			if start < 0 {
+				start = t.pos
+			}
+			end = t.end()
+		}
+	}
+	endStep()
+	if len(steps) == 0 {
+		return nil, false, fmt.Errorf("leafref path %q: no steps", p)
+	}
+	return steps, absolute, nil
+}
+
+// leafrefTypes returns the type statements that specify the paths of the
+// leafrefs within the type of e, including the members of unions. The path of
+// each is resolved in the context of its type statement, since for a leafref
+// defined via a typedef in another module, prefixes within the path refer to
+// the imports of that module rather than those of e.
+func (e *Entry) leafrefTypes() []*Type {
+	if e.Type == nil || (e.Type.Kind != Yleafref && e.Type.Kind != Yunion) {
+		return nil
+	}
+	var t *Type
+	switch n := e.Node.(type) {
+	case *Leaf:
+		t = n.Type
+	case *LeafList:
+		t = n.Type
+	}
+	var ts []*Type
+	seen := map[*Type]bool{}
+	var walk func(t *Type)
+	walk = func(t *Type) {
+		if t == nil || seen[t] {
+			return
+		}
+		seen[t] = true
+		switch {
+		case t.Path != nil:
+			ts = append(ts, t)
+		case len(t.Type) > 0:
+			for _, ut := range t.Type {
+				walk(ut)
+			}
+		case t.YangType != nil:
+			walk(t.YangType.Base)
+		}
+	}
+	walk(t)
+	return ts
+}
+
+// dataParent returns the nearest ancestor of e that is not a choice or case.
+func (e *Entry) dataParent() *Entry {
+	p := e.Parent
+	for p != nil && (p.IsChoice() || p.IsCase()) {
+		p = p.Parent
+	}
+	return p
+}
+
+// followLeafrefPath returns the entry referenced by the path of the leafref
+// type t of e. Predicates are ignored, unless enter is not nil, in which case
+// it is called with the entry entered by each step of the path that names a
+// node (rather than "..") along with the step, and an error that it returns
+// is returned.
+func (e *Entry) followLeafrefPath(t *Type, enter func(*Entry, leafrefStep) error) (*Entry, error) {
+	steps, absolute, err := parseLeafrefPath(t.Path.Name)
+	if err != nil {
+		return nil, err
+	}
+	ctx := Node(t)
+	cur := e
+	if absolute {
+		pfx, _ := getPrefix(steps[0].name)
+		m := FindModuleByPrefix(ctx, pfx)
+		if m == nil {
+			return nil, fmt.Errorf("unknown prefix %q", pfx)
+		}
+		if m = module(m); m == nil {
+			return nil, fmt.Errorf("cannot find module for prefix %q", pfx)
+		}
+		cur = ToEntry(m)
+	}
+
+	for _, s := range steps {
+		switch {
+		case s.name == "..":
+			if cur = cur.dataParent(); cur == nil {
+				return nil, fmt.Errorf(".. above the root")
+			}
+		case s.name == "current()" || s.name == ".":
+		default:
+			pfx, name := getPrefix(s.name)
+			if pfx != "" && FindModuleByPrefix(ctx, pfx) == nil {
+				return nil, fmt.Errorf("unknown prefix %q", pfx)
+			}
+			next := cur.findDataChild(name)
+			if next == nil {
+				return nil, fmt.Errorf("%s: no such element within %s", s.name, cur.Path())
+			}
+			cur = next
+			if enter != nil {
+				if err := enter(cur, s); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	return cur, nil
+}
+
+// LeafrefTarget returns the entry referenced by the path of the leafref leaf
+// or leaf-list e. Prefixes within the path are resolved against the imports
+// of the module in which the path is defined. If the target is itself a
+// leafref then its path is followed in turn, so that the entry returned is
+// not a leafref. An error is returned if e is not a leafref, if a path cannot
+// be resolved, or if the chain of leafrefs forms a cycle.
+func (e *Entry) LeafrefTarget() (*Entry, error) {
+	seen := map[*Entry]bool{}
+	for {
+		if e.Type == nil || e.Type.Kind != Yleafref {
+			return nil, fmt.Errorf("%s: not a leafref", e.Path())
+		}
+		if seen[e] {
+			return nil, fmt.Errorf("%s: leafref cycle", e.Path())
+		}
+		seen[e] = true
+		ts := e.leafrefTypes()
+		if len(ts) == 0 {
+			return nil, fmt.Errorf("%s: leafref has no path", e.Path())
+		}
+		target, err := e.followLeafrefPath(ts[0], nil)
+		if err != nil {
+			return nil, fmt.Errorf("%s: cannot resolve leafref path %q: %v", e.Path(), ts[0].Path.Name, err)
+		}
+		if target.Type == nil || target.Type.Kind != Yleafref {
+			return target, nil
+		}
+		e = target
+	}
+}
+
+// within returns true if e is a, or is a descendant of a.
+func (e *Entry) within(a *Entry) bool {
+	for ; e != nil; e = e.Parent {
+		if e == a {
+			return true
+		}
+	}
+	return false
+}
+
+// checkLeafref returns an error if the path of a leafref within the type of
+// e resolves to an entry that is not a valid leafref target, i.e., is not a
+// leaf or leaf-list. Paths that cannot be resolved are not reported.
+func (e *Entry) checkLeafref() error {
+	for _, t := range e.leafrefTypes() {
+		target, err := e.followLeafrefPath(t, nil)
+		if err != nil {
+			continue
+		}
+		if !target.IsLeaf() && !target.IsLeafList() {
+			return fmt.Errorf("%s: leafref target %s is not a leaf", Source(e.Node), target.Path())
+		}
+	}
+	return nil
+}
+
+// checkLeafrefKeys returns an error if the path of a leafref within the type
+// of e has an invalid predicate, as described by checkLeafrefPredicate, or
+// enters a keyed list without a predicate, unless the path leaves the list
+// again or targets one of its keys. RFC7950 does not require such
+// predicates, so this is only checked by Validate. Paths that cannot be
+// resolved are not reported.
+func (e *Entry) checkLeafrefKeys() error {
+	for _, t := range e.leafrefTypes() {
+		var predErr error
+		var lists []*Entry
+		unkeyed := map[*Entry]bool{}
+		target, err := e.followLeafrefPath(t, func(l *Entry, s leafrefStep) error {
+			if s.pred != "" {
+				predErr = e.checkLeafrefPredicate(l, s.pred)
+				unkeyed[l] = false
+				return predErr
+			}
+			if l.IsList() && l.Key != "" {
+				if _, ok := unkeyed[l]; !ok {
+					lists = append(lists, l)
+				}
+				unkeyed[l] = true
+			}
+			return nil
+		})
+		if predErr != nil {
+			return fmt.Errorf("%s: leafref path %q: %v", Source(e.Node), t.Path.Name, predErr)
+		}
+		if err != nil {
+			continue
+		}
+		for _, l := range lists {
+			if unkeyed[l] && target.within(l) && (target.Parent != l || !target.IsKey()) {
+				return fmt.Errorf("%s: leafref path %q enters list %s without a key predicate and does not target one of its keys", Source(e.Node), t.Path.Name, l.Path())
+			}
+		}
+	}
+	return nil
+}
+
+// checkLeafrefPredicate returns an error if pred, the predicate of the step
+// of the path of the leafref e that entered l, is not valid as defined by
+// RFC7950 section 9.9.2: l must be a list, and each of the equalities of pred,
+// joined by "and", must compare a key of l with a path starting with
+// current(), followed by one or more ".." steps and then the names of the
+// nodes that lead to a leaf, which is resolved relative to e.
+func (e *Entry) checkLeafrefPredicate(l *Entry, pred string) error {
+	if !l.IsList() {
+		return fmt.Errorf("predicate [%s] on %s, which is not a list", pred, l.Path())
+	}
+	for _, eq := range strings.Split(pred, " and ") {
+		parts := strings.SplitN(eq, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("predicate [%s] is not an equality", eq)
+		}
+		_, key := getPrefix(strings.TrimSpace(parts[0]))
+		if ke := l.Dir[key]; ke == nil || !ke.IsKey() {
+			return fmt.Errorf("predicate [%s]: %s is not a key of list %s", eq, key, l.Path())
+		}
+		rel := strings.TrimSpace(parts[1])
+		if !strings.HasPrefix(rel, "current()") {
+			return fmt.Errorf("predicate [%s]: %q does not start with current()", eq, rel)
+		}
+		steps := strings.Split(strings.TrimSpace(strings.TrimPrefix(rel, "current()")), "/")
+		if len(steps) < 3 || strings.TrimSpace(steps[0]) != "" || strings.TrimSpace(steps[1]) != ".." {
+			return fmt.Errorf("predicate [%s]: %q is not of the form current()/../node", eq, rel)
+		}
+		cur := e
+		for i, s := range steps[1:] {
+			s = strings.TrimSpace(s)
+			switch {
+			case s == "..":
+				if names := steps[1 : i+1]; len(names) > 0 && strings.TrimSpace(names[len(names)-1]) != ".." {
+					return fmt.Errorf("predicate [%s]: %q has .. after a node name", eq, rel)
+				}
+				if cur = cur.dataParent(); cur == nil {
+					return fmt.Errorf("predicate [%s]: %q goes above the root", eq, rel)
+				}
+			default:
+				_, name := getPrefix(s)
+				if cur = cur.findDataChild(name); cur == nil {
+					return fmt.Errorf("predicate [%s]: %s: no such element", eq, s)
+				}
+			}
+		}
+		if !cur.IsLeaf() && !cur.IsLeafList() {
+			return fmt.Errorf("predicate [%s]: %s is not a leaf", eq, cur.Path())
+		}
+	}
+	return nil
+}
+
+// checkLeafrefs calls f with the error returned by check, either
+// checkLeafref or checkLeafrefKeys, for every invalid leafref in the tree e,
+// skipping entries that are in seen.
+func (e *Entry) checkLeafrefs(seen map[*Entry]bool, check func(*Entry) error, f func(error)) {
+	if e == nil || seen[e] {
+		return
+	}
+	seen[e] = true
+	if err := check(e); err != nil {
+		f(err)
+	}
+	for _, ce := range e.Dir {
+		ce.checkLeafrefs(seen, check, f)
+	}
+	if e.RPC != nil {
+		e.RPC.Input.checkLeafrefs(seen, check, f)
+		e.RPC.Output.checkLeafrefs(seen, check, f)
+	}
+}
+
+// LeafrefBackReferences returns a map, keyed by the path of an entry, of the
+// paths of the leafref leaves and leaf-lists that reference that entry,
+// including via leafrefs that are members of a union. Each list of paths is
+// sorted. Leafrefs whose path cannot be resolved are omitted. The modules
+// must have been processed.
+func (ms *Modules) LeafrefBackReferences() map[string][]string {
+	refs := map[string][]string{}
+	seen := map[*Entry]bool{}
+	var walk func(e *Entry)
+	walk = func(e *Entry) {
+		if e == nil || seen[e] {
+			return
+		}
+		seen[e] = true
+		tseen := map[*Entry]bool{}
+		for _, t := range e.leafrefTypes() {
+			target, err := e.followLeafrefPath(t, nil)
+			if err != nil || tseen[target] {
+				continue
+			}
+			tseen[target] = true
+			refs[target.Path()] = append(refs[target.Path()], e.Path())
+		}
+		for _, ce := range e.Dir {
+			walk(ce)
+		}
+		if e.RPC != nil {
+			walk(e.RPC.Input)
+			walk(e.RPC.Output)
+		}
+	}
+	for _, m := range ms.Modules {
+		walk(ToEntry(m))
+	}
+	for _, r := range refs {
+		sort.Strings(r)
+	}
+	return refs
+}
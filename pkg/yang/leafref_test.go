@@ -0,0 +1,432 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+)
+
+func TestParseLeafrefPath(t *testing.T) {
+	tests := []struct {
+		in            string
+		wantSteps     []leafrefStep
+		wantAbsolute  bool
+		wantErrSubstr string
+	}{{
+		in:           "/a:x/a:y",
+		wantSteps:    []leafrefStep{{name: "a:x"}, {name: "a:y"}},
+		wantAbsolute: true,
+	}, {
+		in:        "../../config/name",
+		wantSteps: []leafrefStep{{name: ".."}, {name: ".."}, {name: "config"}, {name: "name"}},
+	}, {
+		in: "/if:interfaces/if:interface[if:name = current()/../ifname]/if:mtu",
+		wantSteps: []leafrefStep{
+			{name: "if:interfaces"},
+			{name: "if:interface", pred: "if:name = current()/../ifname"},
+			{name: "if:mtu"},
+		},
+		wantAbsolute: true,
+	}, {
+		in: "/l[a = current()/../a][b = current()/../b]/v",
+		wantSteps: []leafrefStep{
+			{name: "l", pred: "a = current()/../a and b = current()/../b"},
+			{name: "v"},
+		},
+		wantAbsolute: true,
+	}, {
+		in: "/l[name = '/a[b]']/v",
+		wantSteps: []leafrefStep{
+			{name: "l", pred: "name = '/a[b]'"},
+			{name: "v"},
+		},
+		wantAbsolute: true,
+	}, {
+		in:            "/l[name = 'x]/v",
+		wantErrSubstr: "unterminated literal",
+	}, {
+		in:            "../a//b",
+		wantErrSubstr: "empty step"}, {
+		in:            "/a[b = c",
+		wantErrSubstr: "unterminated predicate",
+	}, {
+		in:            "deref(../x)/../y",
+		wantErrSubstr: "deref is not supported",
+	}, {
+		in:            "",
+		wantErrSubstr: "empty leafref path",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			steps, absolute, err := parseLeafrefPath(tt.in)
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Fatal(diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.wantSteps, steps, cmp.AllowUnexported(leafrefStep{})); diff != "" {
+				t.Errorf("steps (-want, +got):\n%s", diff)
+			}
+			if absolute != tt.wantAbsolute {
+				t.Errorf("absolute: got %v, want %v", absolute, tt.wantAbsolute)
+			}
+		})
+	}
+}
+
+func TestLeafrefTargetValidation(t *testing.T) {
+	const target = `
+		module target {
+			prefix "t";
+			namespace "urn:t";
+
+			typedef ifref {
+				type leafref { path "/t:interfaces/t:interface/t:name"; }
+			}
+
+			container interfaces {
+				list interface {
+					key "name";
+					leaf name {
+						type leafref { path "../config/name"; }
+					}
+					container config {
+						leaf name { type string; }
+						leaf mtu { type uint16; }
+					}
+					list subinterface {
+						key "index vlan";
+						leaf index { type uint32; }
+						leaf vlan { type uint16; }
+						leaf mtu { type uint16; }
+					}
+				}
+			}
+
+			list prefixed {
+				key "t:id";
+				leaf id { type string; }
+				leaf value { type string; }
+			}
+		}`
+
+	tests := []struct {
+		desc   string
+		inLeaf string
+		// wantErrSubstr is the error returned by Process, and
+		// wantValidateErrSubstr that returned by Validate.
+		wantErrSubstr         string
+		wantValidateErrSubstr string
+	}{{
+		desc:   "leafref to a key leaf",
+		inLeaf: `leaf r { type leafref { path "/t:interfaces/t:interface/t:name"; } }`,
+	}, {
+		desc:   "leafref via a typedef in another module",
+		inLeaf: `leaf r { type t:ifref; }`,
+	}, {
+		desc:   "leafref with a key predicate",
+		inLeaf: `leaf ifname { type string; } leaf r { type leafref { path "/t:interfaces/t:interface[t:name = current()/../ifname]/t:config/t:mtu"; } }`,
+	}, {
+		desc: "leafref with multiple predicates",
+		inLeaf: `container c {
+				leaf ifname { type string; }
+				leaf index { type uint32; }
+				leaf vlan { type uint16; }
+				leaf r { type leafref { path "/t:interfaces/t:interface[t:name = current()/../ifname]/t:subinterface[t:index=current()/../index][t:vlan = current() / .. / vlan]/t:mtu"; } }
+			}`,
+	}, {
+		desc:   "leafref with a predicate on a prefixed key",
+		inLeaf: `leaf id { type string; } leaf r { type leafref { path "/t:prefixed[t:id = current()/../id]/t:value"; } }`,
+	}, {
+		desc:   "leafref to a prefixed key leaf",
+		inLeaf: `leaf r { type leafref { path "/t:prefixed/t:id"; } }`,
+	}, {
+		desc:                  "leafref into a list with a prefixed key without a predicate",
+		inLeaf:                `leaf r { type leafref { path "/t:prefixed/t:value"; } }`,
+		wantValidateErrSubstr: "enters list /target/prefixed without a key predicate",
+	}, {
+		desc:                  "predicate on a key that does not exist",
+		inLeaf:                `leaf ifname { type string; } leaf r { type leafref { path "/t:interfaces/t:interface[t:mtu = current()/../ifname]/t:config/t:mtu"; } }`,
+		wantValidateErrSubstr: "predicate [t:mtu = current()/../ifname]: mtu is not a key of list /target/interfaces/interface",
+	}, {
+		desc:                  "predicate on a container",
+		inLeaf:                `leaf ifname { type string; } leaf r { type leafref { path "/t:interfaces[t:name = current()/../ifname]/t:interface/t:name"; } }`,
+		wantValidateErrSubstr: "predicate [t:name = current()/../ifname] on /target/interfaces, which is not a list",
+	}, {
+		desc:                  "predicate that does not use current",
+		inLeaf:                `leaf r { type leafref { path "/t:interfaces/t:interface[t:name = 'eth0']/t:config/t:mtu"; } }`,
+		wantValidateErrSubstr: `"'eth0'" does not start with current()`,
+	}, {
+		desc:                  "predicate with an unresolvable path",
+		inLeaf:                `leaf r { type leafref { path "/t:interfaces/t:interface[t:name = current()/../missing]/t:config/t:mtu"; } }`,
+		wantValidateErrSubstr: "missing: no such element",
+	}, {
+		desc:                  "predicate without ..",
+		inLeaf:                `leaf r { type leafref { path "/t:interfaces/t:interface[t:name = current()/r]/t:config/t:mtu"; } }`,
+		wantValidateErrSubstr: `"current()/r" is not of the form current()/../node`,
+	}, {
+		desc:          "leafref to a list",
+		inLeaf:        `leaf r { type leafref { path "/t:interfaces/t:interface"; } }`,
+		wantErrSubstr: "leafref target /target/interfaces/interface is not a leaf",
+	}, {
+		desc:          "leafref to a container",
+		inLeaf:        `leaf r { type leafref { path "/t:interfaces"; } }`,
+		wantErrSubstr: "leafref target /target/interfaces is not a leaf",
+	}, {
+		desc:                  "leafref into a list within a list with a predicate",
+		inLeaf:                `leaf index { type uint32; } leaf vlan { type uint16; } leaf r { type leafref { path "/t:interfaces/t:interface/t:subinterface[t:index = current()/../index][t:vlan = current()/../vlan]/t:mtu"; } }`,
+		wantValidateErrSubstr: "enters list /target/interfaces/interface without a key predicate",
+	}, {
+		desc:   "leafref that leaves a list entered without a predicate",
+		inLeaf: `leaf r { type leafref { path "/t:interfaces/t:interface/../../t:prefixed/t:id"; } }`,
+	}, {
+		desc:                  "leafref into a list without a predicate",
+		inLeaf:                `leaf-list r { type leafref { path "/t:interfaces/t:interface/t:config/t:mtu"; } }`,
+		wantValidateErrSubstr: "enters list /target/interfaces/interface without a key predicate",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ms := NewModules()
+			if err := ms.Parse(target, "target.yang"); err != nil {
+				t.Fatalf("cannot parse target: %v", err)
+			}
+			src := `
+				module source {
+					prefix "s";
+					namespace "urn:s";
+					import target { prefix "t"; }
+					` + tt.inLeaf + `
+				}`
+			if err := ms.Parse(src, "source.yang"); err != nil {
+				t.Fatalf("cannot parse source: %v", err)
+			}
+			var err error
+			if errs := ms.Process(); len(errs) > 0 {
+				err = errs[0]
+			}
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Fatalf("Process: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if errs := ms.Validate(); len(errs) > 0 {
+				err = errs[0]
+			}
+			if diff := errdiff.Substring(err, tt.wantValidateErrSubstr); diff != "" {
+				t.Errorf("Validate: %s", diff)
+			}
+		})
+	}
+}
+
+func TestLeafrefBackReferences(t *testing.T) {
+	ms := NewModules()
+	for n, m := range map[string]string{
+		"target.yang": `
+			module target {
+				prefix "t";
+				namespace "urn:t";
+
+				container system {
+					leaf hostname { type string; }
+					leaf domain { type string; }
+				}
+			}`,
+		"source.yang": `
+			module source {
+				prefix "s";
+				namespace "urn:s";
+				import target { prefix "t"; }
+
+				typedef hostref {
+					type leafref { path "/t:system/t:hostname"; }
+				}
+
+				container refs {
+					leaf a { type hostref; }
+					leaf b { type leafref { path "/t:system/t:hostname"; } }
+					leaf-list c {
+						type union {
+							type leafref { path "/t:system/t:domain"; }
+							type string;
+						}
+					}
+					leaf d { type leafref { path "/t:system/t:missing"; } }
+				}
+			}`,
+	} {
+		if err := ms.Parse(m, n); err != nil {
+			t.Fatalf("cannot parse module %s: %v", n, err)
+		}
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process modules: %v", errs)
+	}
+
+	want := map[string][]string{
+		"/target/system/hostname": {"/source/refs/a", "/source/refs/b"},
+		"/target/system/domain":   {"/source/refs/c"},
+	}
+	got := ms.LeafrefBackReferences()
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("LeafrefBackReferences (-want, +got):\n%s", diff)
+	}
+}
+
+func TestLeafrefTarget(t *testing.T) {
+	ms := NewModules()
+	for n, m := range map[string]string{
+		"target.yang": `
+			module target {
+				prefix "t";
+				namespace "urn:t";
+
+				container system {
+					leaf hostname { type string; }
+				}
+				list server {
+					key "name port";
+					leaf name { type string; }
+					leaf port { type uint16; }
+					container config {
+						leaf address { type string; }
+					}
+				}
+			}`,
+		"source.yang": `
+			module source {
+				prefix "s";
+				namespace "urn:s";
+				import target { prefix "t"; }
+
+				container refs {
+					leaf abs { type leafref { path "/t:system/t:hostname"; } }
+					leaf rel { type leafref { path "../abs"; } }
+					leaf chain { type leafref { path "../rel"; } }
+					leaf missing { type leafref { path "/t:system/t:missing"; } }
+					leaf cycle-a { type leafref { path "../cycle-b"; } }
+					leaf cycle-b { type leafref { path "../cycle-a"; } }
+					leaf plain { type string; }
+					leaf server { type string; }
+					leaf port { type uint16; }
+					leaf single-pred { type leafref { path "/t:server[t:name = current()/../server]/t:config/t:address"; } }
+					leaf multi-pred { type leafref { path "/t:server[t:name = current()/../server][t:port = current()/../port]/t:port"; } }
+				}
+			}`,
+	} {
+		if err := ms.Parse(m, n); err != nil {
+			t.Fatalf("cannot parse module %s: %v", n, err)
+		}
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process modules: %v", errs)
+	}
+	refs := ToEntry(ms.Modules["source"]).Dir["refs"]
+
+	tests := []struct {
+		inLeaf        string
+		wantPath      string
+		wantErrSubstr string
+	}{{
+		inLeaf:   "abs",
+		wantPath: "/target/system/hostname",
+	}, {
+		inLeaf:   "rel",
+		wantPath: "/target/system/hostname",
+	}, {
+		inLeaf:   "chain",
+		wantPath: "/target/system/hostname",
+	}, {
+		inLeaf:   "single-pred",
+		wantPath: "/target/server/config/address",
+	}, {
+		inLeaf:   "multi-pred",
+		wantPath: "/target/server/port",
+	}, {
+		inLeaf:        "missing",
+		wantErrSubstr: `cannot resolve leafref path "/t:system/t:missing"`,
+	}, {
+		inLeaf:        "cycle-a",
+		wantErrSubstr: "/source/refs/cycle-a: leafref cycle",
+	}, {
+		inLeaf:        "plain",
+		wantErrSubstr: "/source/refs/plain: not a leafref",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.inLeaf, func(t *testing.T) {
+			got, err := refs.Dir[tt.inLeaf].LeafrefTarget()
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Fatalf("LeafrefTarget: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if got.Path() != tt.wantPath {
+				t.Errorf("LeafrefTarget: got %s, want %s", got.Path(), tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestLeafrefRequireInstance(t *testing.T) {
+	ms := NewModules()
+	if err := ms.Parse(`
+		module test {
+			prefix "t";
+			namespace "urn:t";
+
+			typedef optref {
+				type leafref {
+					path "/t:c/t:foo";
+					require-instance false;
+				}
+			}
+
+			container c {
+				leaf foo { type string; }
+				leaf required { type leafref { path "../foo"; } }
+				leaf optional {
+					type leafref {
+						path "../foo";
+						require-instance false;
+					}
+				}
+				leaf via-typedef { type optref; }
+			}
+		}`, "test.yang"); err != nil {
+		t.Fatalf("cannot parse module: %v", err)
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process module: %v", errs)
+	}
+	c := ToEntry(ms.Modules["test"]).Dir["c"]
+	for name, want := range map[string]bool{
+		"required":    false,
+		"optional":    true,
+		"via-typedef": true,
+	} {
+		if got := c.Dir[name].Type.OptionalInstance; got != want {
+			t.Errorf("%s: got OptionalInstance %v, want %v", name, got, want)
+		}
+	}
+}
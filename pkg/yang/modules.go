@@ -20,11 +20,20 @@ package yang
 
 import (
 	"fmt"
+	"io/fs"
+	"reflect"
+	"sort"
 	"sync"
 )
 
 // Modules contains information about all the top level modules and
 // submodules that are read into it via its Read method.
+//
+// Once Process has been called, the methods of Modules that do not read or
+// delete modules, and of the Entry trees of its modules, only read the trees,
+// so are safe for concurrent use, including GetModule for modules that have
+// already been read. Reading modules, calling Process again, or changing the
+// trees is not safe for concurrent use.
 type Modules struct {
 	Modules      map[string]*Module // All "module" nodes
 	SubModules   map[string]*Module // All "submodule" nodes
@@ -48,8 +57,35 @@ type Modules struct {
 	ParseOptions Options
 	// Path is the list of directories to look for .yang files in.
 	Path []string
+	// Resolver, if set, is consulted to find the file that contains the
+	// module or submodule named name, at the revision revision (which is ""
+	// when no particular revision is required), before searching Path. It
+	// returns the path of the file, and false if it cannot resolve the
+	// module, in which case the normal search is performed.
+	Resolver func(name, revision string) (path string, ok bool)
+	// OnEntry, if set, is called by Process once with each Entry of the
+	// trees of the modules of ms, as returned by AllEntries, once the
+	// trees are complete, i.e., once groupings are used, augments and
+	// deviations are applied and the type, config and list attributes of
+	// each entry are set. It allows callers to annotate or validate the
+	// entries without walking the trees themselves. The entries of
+	// groupings, which are not within the trees, are not passed to
+	// OnEntry, while each use of a grouping is.
+	OnEntry func(*Entry)
 	// pathMap is used to prevent adding dups in Path.
 	pathMap map[string]bool
+	// fsys holds the filesystems that modules have been read from by
+	// ReadFromFS, which are searched when a file is not found on Path.
+	fsys []fs.FS
+	// processed is set when Process has been called since modules were
+	// last added or deleted, in which case processErrs holds the errors it
+	// returned.
+	processed   bool
+	processErrs []error
+	// patterns maps the patternKeys of the patterns of the types of ms to
+	// their compiledPatterns, so that ValidateInstance compiles each
+	// pattern once. It is freed with ms.
+	patterns sync.Map
 }
 
 // NewModules returns a newly created and initialized Modules.
@@ -79,6 +115,40 @@ func (ms *Modules) Read(name string) error {
 	return ms.Parse(data, name)
 }
 
+// ReadFromFS reads the named yang module from the filesystem fsys into ms,
+// as Read does from the operating system's filesystem, so that modules may be
+// read from an embed.FS or an in-memory filesystem. The name can be the path
+// of a .yang file within fsys, or a module/submodule name, in which case
+// fsys is searched, from its root and through all its directories, for the
+// file name.yang or, failing that, the name@revision-date.yang file with the
+// latest revision-date. fsys is also searched, after Path, for the modules
+// and submodules that are imported and included by the modules of ms. An
+// error is returned if the file is not found or there was an error parsing
+// the file.
+func (ms *Modules) ReadFromFS(fsys fs.FS, name string) error {
+	ms.addFS(fsys)
+	name, data, err := findInFS(fsys, name)
+	if err != nil {
+		return err
+	}
+	return ms.Parse(data, name)
+}
+
+// addFS adds fsys to the filesystems searched by ms, if it is not already
+// searched. Only filesystems of comparable types, such as those returned by
+// os.DirFS, can be found to be already searched; others, such as a
+// fstest.MapFS, are added each time, as comparing them would panic.
+func (ms *Modules) addFS(fsys fs.FS) {
+	if reflect.TypeOf(fsys).Comparable() {
+		for _, f := range ms.fsys {
+			if f == fsys {
+				return
+			}
+		}
+	}
+	ms.fsys = append(ms.fsys, fsys)
+}
+
 // Parse parses data as YANG source and adds it to ms.  The name should reflect
 // the source of data.
 // Note: If an error is returned, valid modules might still have been added to
@@ -88,6 +158,7 @@ func (ms *Modules) Parse(data, name string) error {
 	if err != nil {
 		return err
 	}
+	ms.typeDict.unknownStatement = ms.ParseOptions.UnknownStatementHandler
 	for _, s := range ss {
 		n, err := buildASTWithTypeDict(s, ms.typeDict)
 		if err != nil {
@@ -100,6 +171,19 @@ func (ms *Modules) Parse(data, name string) error {
 	return nil
 }
 
+// ParseFile reads the file at path and parses it as YANG source, adding the
+// modules and submodules it defines to ms. The name of each module is taken
+// from its module or submodule statement, and path is used as the source
+// name within errors. The returned error wraps the error from reading path,
+// if any.
+func (ms *Modules) ParseFile(path string) error {
+	data, err := readFile(path)
+	if err != nil {
+		return fmt.Errorf("cannot read %s: %w", path, err)
+	}
+	return ms.Parse(string(data), path)
+}
+
 // GetModule returns the Entry of the module named by name.  GetModule will
 // search for and read the file named name + ".yang" if it cannot satisfy the
 // request from what it has currently read.
@@ -117,8 +201,13 @@ func (ms *Modules) GetModule(name string) (*Entry, []error) {
 		}
 	}
 	// Make sure that the modules have all been processed and have no
-	// errors.
-	if errs := ms.Process(); len(errs) != 0 {
+	// errors. They are not processed again once processed, so that
+	// GetModule only reads the trees.
+	errs := ms.processErrs
+	if !ms.processed {
+		errs = ms.Process()
+	}
+	if len(errs) != 0 {
 		return nil, errs
 	}
 	return ToEntry(ms.Modules[name]), nil
@@ -164,8 +253,13 @@ func (ms *Modules) add(n Node) error {
 	}
 
 	mod := n.(*Module)
+	// RFC6020 defines version "1" and RFC7950 version "1.1".
+	if v := mod.YangVersion; v != nil && v.Name != "1" && v.Name != "1.1" {
+		return fmt.Errorf("%s: unknown yang-version %q in %s %s", Source(v), v.Name, kind, name)
+	}
 	fullName := mod.FullName()
 	mod.Modules = ms
+	ms.processed = false
 
 	if o := m[fullName]; o != nil {
 		return fmt.Errorf("duplicate %s %s at %s and %s", kind, fullName, Source(o), Source(n))
@@ -258,6 +352,60 @@ func (ms *Modules) FindModuleByNamespace(ns string) (*Module, error) {
 	return found, nil
 }
 
+// GetModuleForEntry returns the module within ms that defines e, using the
+// same rules as InstantiatingModule: the module is the one whose namespace is
+// that of e, as returned by Namespace. For most entries, this is the module
+// at the root of the tree of e, or the module that a submodule at the root
+// belongs to. An entry added to the tree by an augment is defined by the
+// module containing the augment, rather than the module at the root of the
+// tree. An error is returned if e has no namespace, or its namespace is not
+// that of exactly one module within ms.
+func (ms *Modules) GetModuleForEntry(e *Entry) (*Module, error) {
+	if e == nil {
+		return nil, fmt.Errorf("nil entry")
+	}
+	ns := e.Namespace()
+	if ns == nil || ns.Name == "" {
+		return nil, fmt.Errorf("%s: entry has no namespace", e.Path())
+	}
+	m, err := ms.FindModuleByNamespace(ns.Name)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", e.Path(), err)
+	}
+	return m, nil
+}
+
+// SortedModuleNames returns the sorted names of the latest revision of each
+// module within ms. Unlike ranging over ms.Modules, the order is the same
+// every time.
+func (ms *Modules) SortedModuleNames() []string {
+	var names []string
+	for name, m := range ms.Modules {
+		if name == m.Name {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sortedModules returns the modules within mods in the order of their keys,
+// so that they are processed in the same order every time. A module is
+// returned once for each key that it is stored under, e.g., both "foo" and
+// "foo@2020-01-01".
+func sortedModules(mods map[string]*Module) []*Module {
+	names := make([]string, 0, len(mods))
+	for name := range mods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	ms := make([]*Module, len(names))
+	for i, name := range names {
+		ms[i] = mods[name]
+	}
+	return ms
+}
+
 // process satisfies all include and import statements and verifies that all
 // link ref paths reference a known node.  If an import or include references
 // a [sub]module that is not already known, Process will search for a .yang
@@ -273,9 +421,7 @@ func (ms *Modules) process() []error {
 	// Collect the list of modules we know about now so when we range
 	// below we don't pick up new modules.  We assume the user tells
 	// us explicitly which modules they are interested in.
-	for _, m := range ms.Modules {
-		mods = append(mods, m)
-	}
+	mods = append(mods, sortedModules(ms.Modules)...)
 	for _, m := range mods {
 		if err := ms.include(m); err != nil {
 			errs = append(errs, err)
@@ -292,6 +438,28 @@ func (ms *Modules) process() []error {
 	return errs
 }
 
+// checkGroupings returns an error for each uses statement within the modules
+// and submodules of ms that refers to a grouping that cannot be found,
+// including those within groupings that are never used.
+func (ms *Modules) checkGroupings() []error {
+	var errs []error
+	checked := map[*Module]bool{}
+	for _, mods := range []map[string]*Module{ms.Modules, ms.SubModules} {
+		for _, m := range sortedModules(mods) {
+			if checked[m] {
+				continue
+			}
+			checked[m] = true
+			walkUses(m, func(u *Uses) {
+				if FindGrouping(u, u.Name, map[string]bool{}) == nil {
+					errs = append(errs, fmt.Errorf("%s: unknown group: %s", Source(u), u.Name))
+				}
+			})
+		}
+	}
+	return errs
+}
+
 // Process processes all the modules and submodules that have been read into
 // ms.  While processing, if an include or import is found for which there
 // is no matching module, Process attempts to locate the source file (using
@@ -322,20 +490,32 @@ func (ms *Modules) process() []error {
 // not mean these are all the errors.  Process will terminate processing early
 // based on the type and location of the error.
 func (ms *Modules) Process() []error {
+	errs := ms.processAll()
+	ms.processed, ms.processErrs = true, errs
+	return errs
+}
+
+// processAll implements Process.
+func (ms *Modules) processAll() []error {
 	// Reset globals that may remain stale if multiple Process() calls are
 	// made by the same caller.
 	ms.mergedSubmodule = map[string]bool{}
 	ms.ClearEntryCache()
 
+	// Every uses of an unknown grouping is reported before the groupings
+	// are expanded, even if processing cannot continue. Expansion reports
+	// the same errors, which errorSort removes.
 	errs := ms.process()
+	groupingErrs := ms.checkGroupings()
 	if len(errs) > 0 {
-		return errorSort(errs)
+		return errorSort(append(errs, groupingErrs...))
 	}
 
-	for _, m := range ms.Modules {
+	errs = groupingErrs
+	for _, m := range sortedModules(ms.Modules) {
 		errs = append(errs, ToEntry(m).GetErrors()...)
 	}
-	for _, m := range ms.SubModules {
+	for _, m := range sortedModules(ms.SubModules) {
 		errs = append(errs, ToEntry(m).GetErrors()...)
 	}
 
@@ -347,10 +527,10 @@ func (ms *Modules) Process() []error {
 	// what order to process them in, so repeat until no progress is made
 
 	mods := make([]*Module, 0, len(ms.Modules)+len(ms.SubModules))
-	for _, m := range ms.Modules {
+	for _, m := range sortedModules(ms.Modules) {
 		mods = append(mods, m)
 	}
-	for _, m := range ms.SubModules {
+	for _, m := range sortedModules(ms.SubModules) {
 		mods = append(mods, m)
 	}
 	for len(mods) > 0 {
@@ -373,18 +553,51 @@ func (ms *Modules) Process() []error {
 
 	// Now fix up all the choice statements to add in the missing case
 	// statements.
-	for _, m := range ms.Modules {
+	for _, m := range sortedModules(ms.Modules) {
 		ToEntry(m).FixChoice()
 	}
-	for _, m := range ms.SubModules {
+	for _, m := range sortedModules(ms.SubModules) {
 		ToEntry(m).FixChoice()
 	}
+	seen := map[*Entry]bool{}
+	for _, m := range sortedModules(ms.Modules) {
+		ToEntry(m).checkChoiceDefaults(seen, func(err error) {
+			errs = append(errs, err)
+		})
+	}
+	seen = map[*Entry]bool{}
+	for _, m := range sortedModules(ms.Modules) {
+		ToEntry(m).checkUnique(seen)
+	}
+	seen = map[*Entry]bool{}
+	for _, m := range sortedModules(ms.Modules) {
+		ToEntry(m).checkActionPlacement(nil, seen, func(err error) {
+			errs = append(errs, err)
+		})
+	}
+	checked := map[*Module]bool{}
+	for _, fmods := range []map[string]*Module{ms.Modules, ms.SubModules} {
+		for _, m := range sortedModules(fmods) {
+			if !checked[m] {
+				checked[m] = true
+				checkIfFeatures(m, func(err error) {
+					errs = append(errs, err)
+				})
+			}
+		}
+	}
 
-	// Go through any modules that have remaining augments and collect
-	// the errors.
+	// Go through any modules that have remaining augments and add errors
+	// for them. Applying an augment may also add errors to the entry it
+	// targets, such as when the augment adds a node that is already
+	// defined, so the errors are collected from every module.
 	for _, m := range mods {
 		ToEntry(m).Augment(true)
-		errs = append(errs, ToEntry(m).GetErrors()...)
+	}
+	for _, amods := range []map[string]*Module{ms.Modules, ms.SubModules} {
+		for _, m := range sortedModules(amods) {
+			errs = append(errs, ToEntry(m).GetErrors()...)
+		}
 	}
 
 	// The deviation statement is only valid under a module or submodule,
@@ -394,7 +607,7 @@ func (ms *Modules) Process() []error {
 	// an entry does not exist.
 	dvP := map[string]bool{} // cache the modules we've handled since we have both modname and modname@revision-date
 	for _, devmods := range []map[string]*Module{ms.Modules, ms.SubModules} {
-		for _, m := range devmods {
+		for _, m := range sortedModules(devmods) {
 			e := ToEntry(m)
 			if !dvP[e.Name] {
 				errs = append(errs, e.ApplyDeviate(ms.ParseOptions.DeviateOptions)...)
@@ -403,6 +616,80 @@ func (ms *Modules) Process() []error {
 		}
 	}
 
+	// The prefixes within a when statement are resolved in the context of
+	// the module that the statement is defined in, which need not be the
+	// module of the node it is attached to (e.g., for augments).
+	seen = map[*Entry]bool{}
+	for _, wmods := range []map[string]*Module{ms.Modules, ms.SubModules} {
+		for _, m := range sortedModules(wmods) {
+			ToEntry(m).checkWhenPrefixes(seen, func(err error) {
+				errs = append(errs, err)
+			})
+		}
+	}
+
+	// Leafrefs can only be checked once the whole schema tree, including
+	// augments and deviations, is known.
+	seen = map[*Entry]bool{}
+	for _, m := range sortedModules(ms.Modules) {
+		ToEntry(m).checkLeafrefs(seen, (*Entry).checkLeafref, func(err error) {
+			errs = append(errs, err)
+		})
+	}
+
+	if ms.OnEntry != nil {
+		ms.walkEntries(ms.OnEntry)
+	}
+
+	return errorSort(errs)
+}
+
+// walkEntries calls f with each of the entries returned by AllEntries, in the
+// order of the names of the modules, with each entry before its children.
+func (ms *Modules) walkEntries(f func(*Entry)) {
+	var walk func(e *Entry)
+	walk = func(e *Entry) {
+		if e == nil {
+			return
+		}
+		f(e)
+		for _, ce := range e.Dir {
+			walk(ce)
+		}
+		if e.RPC != nil {
+			walk(e.RPC.Input)
+			walk(e.RPC.Output)
+		}
+	}
+	for _, name := range ms.SortedModuleNames() {
+		walk(ToEntry(ms.Modules[name]))
+	}
+}
+
+// Validate returns the errors found by checks of the modules of ms that are
+// stricter than those made by Process, which accepts some modules that do
+// not conform to RFC7950 so that existing models continue to be processed.
+// Process must be called first. Currently, the keys of every list are
+// checked: each must name a leaf that is a child of the list, no key may be
+// named more than once, the keys of a list that is configuration data must
+// not be config false, and a list that is configuration data must have a
+// key. The predicates of leafref paths are also checked, and a leafref path
+// that enters a keyed list must either have a key predicate on the list or
+// target one of its keys.
+func (ms *Modules) Validate() []error {
+	var errs []error
+	seen := map[*Entry]bool{}
+	for _, m := range sortedModules(ms.Modules) {
+		ToEntry(m).checkKeys(seen, func(err error) {
+			errs = append(errs, err)
+		})
+	}
+	seen = map[*Entry]bool{}
+	for _, m := range sortedModules(ms.Modules) {
+		ToEntry(m).checkLeafrefs(seen, (*Entry).checkLeafrefKeys, func(err error) {
+			errs = append(errs, err)
+		})
+	}
 	return errorSort(errs)
 }
 
@@ -458,9 +745,318 @@ func (ms *Modules) setEntryCache(n Node, e *Entry) {
 }
 
 // ClearEntryCache clears the entryCache containing previously converted nodes
-// used by the ToEntry function.
+// used by the ToEntry function. Each Modules has its own cache, so it need not
+// be cleared between independent sets of modules created by NewModules.
 func (ms *Modules) ClearEntryCache() {
 	ms.entryCacheMu.Lock()
 	defer ms.entryCacheMu.Unlock()
 	ms.entryCache = map[Node]*Entry{}
+	ms.processed = false
+}
+
+// DeleteModule removes the module named name, which may include a revision
+// (e.g., "foo@2020-01-01"), from ms. Unless another revision of the module
+// remains in ms, the submodules that belong to the module are also removed.
+// The cached entries, typedefs and identities of the removed modules and
+// submodules are discarded. If another revision of the module remains, the
+// most recent is then found by the name of the module. An error is returned
+// if the module is not within ms.
+//
+// A module that is imported by other modules may be deleted, in which case
+// the imports of those modules continue to refer to the deleted module, as do
+// the entries of any module that it augmented, until the importing modules
+// are themselves deleted and parsed again, or ClearEntryCache is called.
+func (ms *Modules) DeleteModule(name string) error {
+	m := ms.Modules[name]
+	if m == nil {
+		return fmt.Errorf("module not found: %s", name)
+	}
+
+	ms.processed = false
+	deleted := map[*Module]bool{m: true}
+	for k, o := range ms.Modules {
+		if o == m {
+			delete(ms.Modules, k)
+		}
+	}
+
+	// Find the most recent remaining revision of the module, if any.
+	var latest *Module
+	for _, o := range ms.Modules {
+		if o.Name == m.Name && (latest == nil || o.FullName() > latest.FullName()) {
+			latest = o
+		}
+	}
+	if latest != nil {
+		ms.Modules[m.Name] = latest
+	} else {
+		for k, s := range ms.SubModules {
+			if s.BelongsTo != nil && s.BelongsTo.Name == m.Name {
+				deleted[s] = true
+				delete(ms.SubModules, k)
+			}
+		}
+	}
+
+	for mod := range deleted {
+		delete(ms.includes, mod)
+	}
+
+	ms.nsMu.Lock()
+	for ns, o := range ms.byNS {
+		if deleted[o] {
+			delete(ms.byNS, ns)
+		}
+	}
+	ms.nsMu.Unlock()
+
+	ms.entryCacheMu.Lock()
+	for n := range ms.entryCache {
+		if deleted[RootNode(n)] {
+			delete(ms.entryCache, n)
+		}
+	}
+	ms.entryCacheMu.Unlock()
+
+	d := ms.typeDict
+	d.mu.Lock()
+	for n := range d.dict {
+		if deleted[RootNode(n)] {
+			delete(d.dict, n)
+		}
+	}
+	d.mu.Unlock()
+	d.identities.mu.Lock()
+	for k, ri := range d.identities.dict {
+		if deleted[ri.Module] || deleted[RootNode(ri.Identity)] {
+			delete(d.identities.dict, k)
+		}
+	}
+	d.identities.mu.Unlock()
+	return nil
+}
+
+// GenerationOrder returns the names of the modules within ms in dependency
+// order, such that each module appears after the modules that it imports.
+// The names of the submodules of each module follow the name of the module,
+// sorted by name, and the imports of a submodule are treated as imports of
+// the module that it belongs to. Modules are considered in order of name. As
+// mutually importing modules have no valid order, an import that would
+// complete a cycle is ignored, e.g., where a imports b and b imports a, b
+// precedes a. An error is returned if a module imports a module that is not
+// within ms.
+func (ms *Modules) GenerationOrder() ([]string, error) {
+	imports := map[string][]string{}
+	subs := map[string][]string{}
+	for _, m := range ms.Modules {
+		if _, ok := imports[m.Name]; !ok {
+			imports[m.Name] = nil
+		}
+		for _, i := range m.Import {
+			imports[m.Name] = append(imports[m.Name], i.Name)
+		}
+	}
+	seenSub := map[string]bool{}
+	for _, sm := range ms.SubModules {
+		if sm.BelongsTo == nil || seenSub[sm.Name] {
+			continue
+		}
+		seenSub[sm.Name] = true
+		owner := sm.BelongsTo.Name
+		if _, ok := imports[owner]; !ok {
+			continue
+		}
+		subs[owner] = append(subs[owner], sm.Name)
+		for _, i := range sm.Import {
+			imports[owner] = append(imports[owner], i.Name)
+		}
+	}
+
+	var names []string
+	for name, deps := range imports {
+		names = append(names, name)
+		sort.Strings(deps)
+		for _, d := range deps {
+			if _, ok := imports[d]; !ok {
+				return nil, fmt.Errorf("module %s imports unknown module %s", name, d)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	// A module is marked as visited before its imports are visited, so an
+	// import of a module whose imports are still being visited, which
+	// completes a cycle, is ignored.
+	var order []string
+	visited := map[string]bool{}
+	var visit func(name string)
+	visit = func(name string) {
+		if visited[name] {
+			return
+		}
+		visited[name] = true
+		for _, d := range imports[name] {
+			visit(d)
+		}
+		order = append(order, name)
+		sort.Strings(subs[name])
+		order = append(order, subs[name]...)
+	}
+	for _, name := range names {
+		visit(name)
+	}
+	return order, nil
+}
+
+// AllEntries returns the entries of every node within the modules of ms,
+// keyed by their path, as returned by Path. Process should be called first.
+// The entries of the modules themselves, and of the nodes added to them by
+// augments, are included, as are the input and output of RPCs and actions,
+// notifications, and their descendants. As the paths of entries include the
+// names of choice and case entries, these are included too. Submodules are
+// not included separately, as their nodes are within the entries of the
+// modules they belong to. Where several revisions of a module are within ms,
+// only the most recent is included.
+func (ms *Modules) AllEntries() map[string]*Entry {
+	entries := map[string]*Entry{}
+	var add func(e *Entry)
+	add = func(e *Entry) {
+		if e == nil {
+			return
+		}
+		entries[e.Path()] = e
+		for _, ce := range e.Dir {
+			add(ce)
+		}
+		if e.RPC != nil {
+			add(e.RPC.Input)
+			add(e.RPC.Output)
+		}
+	}
+	for name, m := range ms.Modules {
+		if name == m.Name {
+			add(ToEntry(m))
+		}
+	}
+	return entries
+}
+
+// DatastoreView returns the entries of the data nodes within the modules of
+// ms that are visible within the named NMDA datastore (RFC8342), keyed by
+// their path, as returned by AllEntries. The conventional configuration
+// datastores, "running", "candidate", "startup" and "intended", contain only
+// configuration data, so exclude the entries that are config false, either
+// directly or through an ancestor. The "operational" datastore contains both
+// configuration and state data. The name may be qualified with the prefix
+// ietf-datastores, e.g., "ietf-datastores:running". RPCs, actions and
+// notifications, and their descendants, are not data and are never included.
+// The entries are not copied, so the Dir of an entry within the view may
+// contain children that are not. An error is returned if the datastore is
+// unknown.
+func (ms *Modules) DatastoreView(datastore string) (map[string]*Entry, error) {
+	var configOnly bool
+	switch prefix, name := getPrefix(datastore); {
+	case prefix != "" && prefix != "ietf-datastores":
+		return nil, fmt.Errorf("unknown datastore: %s", datastore)
+	case name == "running", name == "candidate", name == "startup", name == "intended":
+		configOnly = true
+	case name == "operational":
+	default:
+		return nil, fmt.Errorf("unknown datastore: %s", datastore)
+	}
+
+	entries := map[string]*Entry{}
+	var add func(e *Entry)
+	add = func(e *Entry) {
+		switch {
+		case e.RPC != nil, e.Kind == NotificationEntry:
+			return
+		case configOnly && e.ReadOnly():
+			return
+		}
+		entries[e.Path()] = e
+		for _, ce := range e.Dir {
+			add(ce)
+		}
+	}
+	for name, m := range ms.Modules {
+		if name == m.Name {
+			add(ToEntry(m))
+		}
+	}
+	return entries, nil
+}
+
+// ModuleMeta is the metadata within the header of a module or submodule.
+type ModuleMeta struct {
+	Name         string
+	Organization string
+	Contact      string
+	Description  string
+	Reference    string
+	Namespace    string
+	Prefix       string
+	Revision     string // The most recent revision, if any.
+}
+
+// ModuleMetadata returns the metadata of the module or submodule named name,
+// which may include a revision (e.g., "foo@2020-01-01"). A submodule has no
+// namespace of its own, so the namespace of a submodule is that of the module
+// it belongs to, and its prefix is that given by its belongs-to statement.
+// An error is returned if there is no such module or submodule, or if the
+// module that a submodule belongs to cannot be found.
+func (ms *Modules) ModuleMetadata(name string) (ModuleMeta, error) {
+	m := ms.Modules[name]
+	if m == nil {
+		m = ms.SubModules[name]
+	}
+	if m == nil {
+		return ModuleMeta{}, fmt.Errorf("module not found: %s", name)
+	}
+	meta := ModuleMeta{
+		Name:         m.Name,
+		Organization: m.Organization.asString(),
+		Contact:      m.Contact.asString(),
+		Description:  m.Description.asString(),
+		Reference:    m.Reference.asString(),
+		Namespace:    m.Namespace.asString(),
+		Prefix:       m.GetPrefix(),
+		Revision:     m.Current(),
+	}
+	if m.Kind() == "submodule" {
+		bm := ms.Modules[m.BelongsTo.Name]
+		if bm == nil {
+			return ModuleMeta{}, fmt.Errorf("%s: module not found: %s", m.Name, m.BelongsTo.Name)
+		}
+		meta.Namespace = bm.Namespace.asString()
+	}
+	return meta, nil
+}
+
+// PrefixMap returns the prefixes that are in scope within the module or
+// submodule named name, mapped to the names of the modules that they refer
+// to: the prefix of the module itself, or for a submodule the prefix within
+// its belongs-to statement, is mapped to the name of the module, and the
+// prefix of each import is mapped to the name of the imported module. These
+// are the prefixes used to resolve prefixed names, such as those within the
+// when, must and path statements of the module. An error is returned if the
+// module is not within ms.
+func (ms *Modules) PrefixMap(name string) (map[string]string, error) {
+	m := ms.Modules[name]
+	if m == nil {
+		m = ms.SubModules[name]
+	}
+	if m == nil {
+		return nil, fmt.Errorf("module not found: %s", name)
+	}
+	pfxs := map[string]string{}
+	if m.BelongsTo != nil {
+		pfxs[m.GetPrefix()] = m.BelongsTo.Name
+	} else {
+		pfxs[m.GetPrefix()] = m.Name
+	}
+	for _, i := range m.Import {
+		pfxs[i.Prefix.Name] = i.Name
+	}
+	return pfxs, nil
 }
@@ -15,9 +15,13 @@
 package yang
 
 import (
+	"fmt"
+	"sort"
 	"strings"
+	"sync"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/openconfig/gnmi/errdiff"
 )
 
@@ -412,3 +416,1561 @@ func TestModulesTotalProcess(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerationOrder(t *testing.T) {
+	tests := []struct {
+		desc          string
+		inModules     []string
+		want          []string
+		wantErrSubstr string
+	}{{
+		desc: "chain of imports",
+		inModules: []string{
+			`module a { prefix a; namespace urn:a; import b { prefix b; } }`,
+			`module b { prefix b; namespace urn:b; import c { prefix c; } }`,
+			`module c { prefix c; namespace urn:c; }`,
+		},
+		want: []string{"c", "b", "a"},
+	}, {
+		desc: "independent modules",
+		inModules: []string{
+			`module z { prefix z; namespace urn:z; }`,
+			`module y { prefix y; namespace urn:y; }`,
+		},
+		want: []string{"y", "z"},
+	}, {
+		desc: "mutual imports",
+		inModules: []string{
+			`module a { prefix a; namespace urn:a; import b { prefix b; } }`,
+			`module b { prefix b; namespace urn:b; import a { prefix a; } }`,
+		},
+		want: []string{"b", "a"},
+	}, {
+		desc: "submodule imports",
+		inModules: []string{
+			`module a { prefix a; namespace urn:a; include a-sub2; include a-sub1; }`,
+			`submodule a-sub1 { belongs-to a { prefix a; } import b { prefix b; } }`,
+			`submodule a-sub2 { belongs-to a { prefix a; } }`,
+			`module b { prefix b; namespace urn:b; }`,
+		},
+		want: []string{"b", "a", "a-sub1", "a-sub2"},
+	}, {
+		desc: "unknown import",
+		inModules: []string{
+			`module a { prefix a; namespace urn:a; import missing { prefix m; } }`,
+		},
+		wantErrSubstr: "module a imports unknown module missing",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ms := NewModules()
+			for i, m := range tt.inModules {
+				if err := ms.Parse(m, fmt.Sprintf("%d.yang", i)); err != nil {
+					t.Fatalf("cannot parse module %d: %v", i, err)
+				}
+			}
+			got, err := ms.GenerationOrder()
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Fatalf("GenerationOrder: %s", diff)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("GenerationOrder (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestModuleMetadata(t *testing.T) {
+	ms := NewModules()
+	for name, src := range map[string]string{
+		"meta.yang": `
+			module meta {
+				prefix "m";
+				namespace "urn:meta";
+				include meta-sub;
+				organization "Example Org";
+				contact "noc@example.com";
+				description "A module with a full header.";
+				reference "RFC 0000";
+				revision 2019-06-01 { description "Initial."; }
+				revision 2020-01-01 { description "Second."; }
+			}`,
+		"meta-sub.yang": `
+			submodule meta-sub {
+				belongs-to meta { prefix "ms"; }
+				organization "Sub Org";
+				description "A submodule.";
+				revision 2021-03-04;
+			}`,
+		"orphan.yang": `
+			submodule orphan {
+				belongs-to missing { prefix "o"; }
+			}`,
+	} {
+		if err := ms.Parse(src, name); err != nil {
+			t.Fatalf("cannot parse %s: %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		desc          string
+		inName        string
+		want          ModuleMeta
+		wantErrSubstr string
+	}{{
+		desc:   "module",
+		inName: "meta",
+		want: ModuleMeta{
+			Name:         "meta",
+			Organization: "Example Org",
+			Contact:      "noc@example.com",
+			Description:  "A module with a full header.",
+			Reference:    "RFC 0000",
+			Namespace:    "urn:meta",
+			Prefix:       "m",
+			Revision:     "2020-01-01",
+		},
+	}, {
+		desc:   "module with revision",
+		inName: "meta@2020-01-01",
+		want: ModuleMeta{
+			Name:         "meta",
+			Organization: "Example Org",
+			Contact:      "noc@example.com",
+			Description:  "A module with a full header.",
+			Reference:    "RFC 0000",
+			Namespace:    "urn:meta",
+			Prefix:       "m",
+			Revision:     "2020-01-01",
+		},
+	}, {
+		desc:   "submodule",
+		inName: "meta-sub",
+		want: ModuleMeta{
+			Name:         "meta-sub",
+			Organization: "Sub Org",
+			Description:  "A submodule.",
+			Namespace:    "urn:meta",
+			Prefix:       "ms",
+			Revision:     "2021-03-04",
+		},
+	}, {
+		desc:          "unknown module",
+		inName:        "nope",
+		wantErrSubstr: "module not found: nope",
+	}, {
+		desc:          "submodule of unknown module",
+		inName:        "orphan",
+		wantErrSubstr: "orphan: module not found: missing",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := ms.ModuleMetadata(tt.inName)
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Fatalf("ModuleMetadata: %s", diff)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ModuleMetadata (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDeleteModule(t *testing.T) {
+	// sources is keyed by the full name of each module and submodule.
+	sources := map[string]string{
+		"base@2020-01-01": `
+			module base {
+				prefix "b";
+				namespace "urn:base";
+				revision 2020-01-01;
+				identity id;
+				typedef t { type string; }
+				container c { leaf l { type t; } }
+			}`,
+		"base@2019-01-01": `
+			module base {
+				prefix "b";
+				namespace "urn:base";
+				revision 2019-01-01;
+			}`,
+		"user": `
+			module user {
+				prefix "u";
+				namespace "urn:user";
+				import base { prefix b; }
+				include user-sub;
+				leaf l { type b:t; }
+			}`,
+		"user-sub": `
+			submodule user-sub {
+				belongs-to user { prefix "u"; }
+				leaf s { type string; }
+			}`,
+	}
+
+	tests := []struct {
+		desc           string
+		inDelete       []string
+		wantModules    []string
+		wantSubModules []string
+		wantErrSubstr  string
+	}{{
+		desc:           "module with submodule",
+		inDelete:       []string{"user"},
+		wantModules:    []string{"base", "base@2019-01-01", "base@2020-01-01"},
+		wantSubModules: nil,
+	}, {
+		desc:           "latest revision of imported module",
+		inDelete:       []string{"base"},
+		wantModules:    []string{"base", "base@2019-01-01", "user"},
+		wantSubModules: []string{"user-sub"},
+	}, {
+		desc:           "specific revision",
+		inDelete:       []string{"base@2019-01-01"},
+		wantModules:    []string{"base", "base@2020-01-01", "user"},
+		wantSubModules: []string{"user-sub"},
+	}, {
+		desc:           "all revisions",
+		inDelete:       []string{"base", "base"},
+		wantModules:    []string{"user"},
+		wantSubModules: []string{"user-sub"},
+	}, {
+		desc:          "unknown module",
+		inDelete:      []string{"nope"},
+		wantErrSubstr: "module not found: nope",
+	}, {
+		desc:          "submodule",
+		inDelete:      []string{"user-sub"},
+		wantErrSubstr: "module not found: user-sub",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ms := NewModules()
+			for name, src := range sources {
+				if err := ms.Parse(src, name); err != nil {
+					t.Fatalf("cannot parse %s: %v", name, err)
+				}
+			}
+			if errs := ms.Process(); len(errs) > 0 {
+				t.Fatalf("cannot process modules: %v", errs)
+			}
+
+			var err error
+			for _, name := range tt.inDelete {
+				if err = ms.DeleteModule(name); err != nil {
+					break
+				}
+			}
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Fatalf("DeleteModule: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+
+			var gotModules, gotSubModules []string
+			for name := range ms.Modules {
+				gotModules = append(gotModules, name)
+			}
+			for name := range ms.SubModules {
+				gotSubModules = append(gotSubModules, name)
+			}
+			sort.Strings(gotModules)
+			sort.Strings(gotSubModules)
+			if diff := cmp.Diff(tt.wantModules, gotModules); diff != "" {
+				t.Errorf("Modules (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantSubModules, gotSubModules); diff != "" {
+				t.Errorf("SubModules (-want, +got):\n%s", diff)
+			}
+			for n := range ms.entryCache {
+				if m := RootNode(n); m != nil && ms.Modules[m.FullName()] != m && ms.SubModules[m.FullName()] != m {
+					t.Errorf("entry cache holds %s from deleted module %s", NodePath(n), m.FullName())
+				}
+			}
+
+			// The deleted modules can be parsed and processed again.
+			for name, src := range sources {
+				if ms.Modules[name] != nil || ms.SubModules[name] != nil {
+					continue
+				}
+				if err := ms.Parse(src, name+".yang"); err != nil {
+					t.Fatalf("cannot parse %s again: %v", name, err)
+				}
+			}
+			if errs := ms.Process(); len(errs) > 0 {
+				t.Errorf("cannot process modules after DeleteModule: %v", errs)
+			}
+		})
+	}
+}
+
+func TestIndependentModules(t *testing.T) {
+	for _, tt := range []struct {
+		in       string
+		wantLeaf string
+		wantType TypeKind
+	}{{
+		in:       `module foo { prefix "f"; namespace "urn:foo"; container c { leaf a { type string; } } }`,
+		wantLeaf: "a",
+		wantType: Ystring,
+	}, {
+		in:       `module foo { prefix "f"; namespace "urn:foo"; container c { leaf b { type uint8; } } }`,
+		wantLeaf: "b",
+		wantType: Yuint8,
+	}} {
+		ms := NewModules()
+		if err := ms.Parse(tt.in, "foo.yang"); err != nil {
+			t.Fatalf("cannot parse module: %v", err)
+		}
+		if errs := ms.Process(); len(errs) > 0 {
+			t.Fatalf("cannot process module: %v", errs)
+		}
+		e, errs := ms.GetModule("foo")
+		if len(errs) > 0 {
+			t.Fatalf("cannot get module: %v", errs)
+		}
+		c := e.Dir["c"]
+		if len(c.Dir) != 1 {
+			t.Errorf("got children %v, want only %s", c.Dir, tt.wantLeaf)
+		}
+		l := c.Dir[tt.wantLeaf]
+		if l == nil {
+			t.Fatalf("leaf %s not found", tt.wantLeaf)
+		}
+		if l.Type.Kind != tt.wantType {
+			t.Errorf("leaf %s: got type %v, want %v", tt.wantLeaf, l.Type.Kind, tt.wantType)
+		}
+	}
+}
+
+func TestSubmodulePrefixes(t *testing.T) {
+	tests := []struct {
+		desc          string
+		inAugment     string
+		wantErrSubstr string
+	}{{
+		desc:      "belongs-to prefix",
+		inAugment: "/x:c",
+	}, {
+		desc:      "no prefix",
+		inAugment: "/c",
+	}, {
+		desc:          "prefix of the module",
+		inAugment:     "/m:c",
+		wantErrSubstr: `sub.yang:5:7: cannot find module giving prefix "m" within submodule sub, which refers to module m by its belongs-to prefix "x"`,
+	}, {
+		desc:          "undefined prefix",
+		inAugment:     "/y:c",
+		wantErrSubstr: `sub.yang:5:7: cannot find module giving prefix "y" within submodule sub`,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ms := NewModules()
+			for name, src := range map[string]string{
+				"m.yang": `
+					module m {
+						prefix "m";
+						namespace "urn:m";
+						include sub;
+						container c;
+					}`,
+				"sub.yang": `
+					submodule sub {
+						belongs-to m { prefix "x"; }
+
+						augment "` + tt.inAugment + `" {
+							leaf l { type string; }
+						}
+					}`,
+			} {
+				if err := ms.Parse(src, name); err != nil {
+					t.Fatalf("cannot parse %s: %v", name, err)
+				}
+			}
+			var err error
+			if errs := ms.Process(); len(errs) > 0 {
+				err = fmt.Errorf("%v", errs)
+			}
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Fatalf("Process: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if ToEntry(ms.Modules["m"]).Dir["c"].Dir["l"] == nil {
+				t.Errorf("augment %s did not add l to /m/c", tt.inAugment)
+			}
+		})
+	}
+}
+
+func TestAllEntries(t *testing.T) {
+	ms := NewModules()
+	for name, src := range map[string]string{
+		"a.yang": `
+			module a {
+				prefix "a";
+				namespace "urn:a";
+				include a-sub;
+				container c {
+					choice ch {
+						leaf x { type string; }
+					}
+				}
+				rpc r {
+					input { leaf in { type string; } }
+					output { leaf out { type string; } }
+				}
+				notification n {
+					leaf note { type string; }
+				}
+			}`,
+		"a-sub.yang": `
+			submodule a-sub {
+				belongs-to a { prefix "a"; }
+				leaf from-sub { type string; }
+			}`,
+		"b.yang": `
+			module b {
+				prefix "b";
+				namespace "urn:b";
+				import a { prefix a; }
+				augment "/a:c" {
+					leaf added { type string; }
+				}
+				leaf own { type string; }
+			}`,
+	} {
+		if err := ms.Parse(src, name); err != nil {
+			t.Fatalf("cannot parse %s: %v", name, err)
+		}
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process modules: %v", errs)
+	}
+
+	got := ms.AllEntries()
+	var gotPaths []string
+	for p, e := range got {
+		if e.Path() != p {
+			t.Errorf("entry %s has path %s", p, e.Path())
+		}
+		gotPaths = append(gotPaths, p)
+	}
+	sort.Strings(gotPaths)
+	wantPaths := []string{
+		"/a",
+		"/a/c",
+		"/a/c/added",
+		"/a/c/ch",
+		"/a/c/ch/x",
+		"/a/c/ch/x/x",
+		"/a/from-sub",
+		"/a/n",
+		"/a/n/note",
+		"/a/r",
+		"/a/r/input",
+		"/a/r/input/in",
+		"/a/r/output",
+		"/a/r/output/out",
+		"/b",
+		"/b/own",
+	}
+	if diff := cmp.Diff(wantPaths, gotPaths); diff != "" {
+		t.Errorf("AllEntries paths (-want, +got):\n%s", diff)
+	}
+}
+
+func TestDatastoreView(t *testing.T) {
+	ms := NewModules()
+	if err := ms.Parse(`
+		module test {
+			yang-version 1.1;
+			prefix "t";
+			namespace "urn:t";
+			container c {
+				leaf cfg { type string; }
+				leaf counter { type uint32; config false; }
+				container state {
+					config false;
+					leaf status { type string; }
+				}
+				action reset {
+					input { leaf why { type string; } }
+				}
+			}
+			rpc r {
+				input { leaf in { type string; } }
+			}
+			notification n {
+				leaf note { type string; }
+			}
+		}`, "test.yang"); err != nil {
+		t.Fatalf("cannot parse module: %v", err)
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process module: %v", errs)
+	}
+
+	config := []string{"/test", "/test/c", "/test/c/cfg"}
+	all := []string{"/test", "/test/c", "/test/c/cfg", "/test/c/counter", "/test/c/state", "/test/c/state/status"}
+	tests := []struct {
+		desc          string
+		in            string
+		want          []string
+		wantErrSubstr string
+	}{
+		{desc: "running", in: "running", want: config},
+		{desc: "candidate", in: "candidate", want: config},
+		{desc: "startup", in: "startup", want: config},
+		{desc: "intended", in: "intended", want: config},
+		{desc: "qualified", in: "ietf-datastores:running", want: config},
+		{desc: "operational", in: "operational", want: all},
+		{desc: "unknown", in: "scratch", wantErrSubstr: "unknown datastore: scratch"},
+		{desc: "unknown prefix", in: "other:running", wantErrSubstr: "unknown datastore: other:running"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := ms.DatastoreView(tt.in)
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Fatalf("DatastoreView(%q): %s", tt.in, diff)
+			}
+			var paths []string
+			for p, e := range got {
+				if e.Path() != p {
+					t.Errorf("entry %s has path %s", p, e.Path())
+				}
+				paths = append(paths, p)
+			}
+			sort.Strings(paths)
+			if diff := cmp.Diff(tt.want, paths); diff != "" {
+				t.Errorf("DatastoreView(%q) paths (-want, +got):\n%s", tt.in, diff)
+			}
+		})
+	}
+}
+
+func TestSortedModuleNames(t *testing.T) {
+	ms := NewModules()
+	for name, src := range map[string]string{
+		"c.yang":            `module c { prefix "c"; namespace "urn:c"; }`,
+		"a.yang":            `module a { prefix "a"; namespace "urn:a"; include a-sub; }`,
+		"a-sub.yang":        `submodule a-sub { belongs-to a { prefix "a"; } }`,
+		"b@2020-01-01.yang": `module b { prefix "b"; namespace "urn:b"; revision 2020-01-01; }`,
+	} {
+		if err := ms.Parse(src, name); err != nil {
+			t.Fatalf("cannot parse %s: %v", name, err)
+		}
+	}
+	if diff := cmp.Diff([]string{"a", "b", "c"}, ms.SortedModuleNames()); diff != "" {
+		t.Errorf("SortedModuleNames (-want, +got):\n%s", diff)
+	}
+}
+
+func TestProcessIsDeterministic(t *testing.T) {
+	tests := []struct {
+		desc string
+		in   map[string]string
+	}{{
+		desc: "augments",
+		in: map[string]string{
+			"base.yang": `
+				module base {
+					prefix "b";
+					namespace "urn:b";
+					container c { leaf own { type string; } }
+				}`,
+			"aug1.yang": `
+				module aug1 {
+					prefix "a1";
+					namespace "urn:a1";
+					import base { prefix b; }
+					augment "/b:c" { leaf shared { type string; } leaf one { type string; } }
+					augment "/b:c/a2:inner" { leaf nested { type string; } }
+					import aug2 { prefix a2; }
+				}`,
+			"aug2.yang": `
+				module aug2 {
+					prefix "a2";
+					namespace "urn:a2";
+					import base { prefix b; }
+					augment "/b:c" { leaf shared { type int8; } container inner { } }
+					leaf bad-ref { type leafref { path "/b:c/b:missing"; } }
+				}`,
+			"aug3.yang": `
+				module aug3 {
+					prefix "a3";
+					namespace "urn:a3";
+					import base { prefix b; }
+					augment "/b:c" { leaf shared { type boolean; } }
+					augment "/b:nowhere" { leaf lost { type string; } }
+				}`,
+		},
+	}, {
+		desc: "typedef cycle",
+		in: map[string]string{
+			"cycle.yang": `
+				module cycle {
+					prefix "c";
+					namespace "urn:c";
+					typedef a { type b; }
+					typedef b { type c; }
+					typedef c { type a; }
+					typedef d { type e; }
+					typedef e { type d; }
+					leaf l { type a; }
+				}`,
+		},
+	}}
+
+	// process returns the errors of processing the modules of tt, and the
+	// source of the entry at each path.
+	process := func(t *testing.T, in map[string]string) ([]string, map[string]string) {
+		ms := NewModules()
+		for name, src := range in {
+			if err := ms.Parse(src, name); err != nil {
+				t.Fatalf("cannot parse %s: %v", name, err)
+			}
+		}
+		var errs []string
+		for _, err := range ms.Process() {
+			errs = append(errs, err.Error())
+		}
+		sources := map[string]string{}
+		for p, e := range ms.AllEntries() {
+			sources[p] = Source(e.Node)
+			if e.Type != nil {
+				sources[p] += " " + e.Type.Name
+			}
+		}
+		return errs, sources
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			wantErrs, wantSources := process(t, tt.in)
+			if len(wantErrs) == 0 {
+				t.Fatalf("Process: got no errors")
+			}
+			for i := 0; i < 10; i++ {
+				gotErrs, gotSources := process(t, tt.in)
+				if diff := cmp.Diff(wantErrs, gotErrs); diff != "" {
+					t.Fatalf("run %d: Process errors differ (-first, +got):\n%s", i, diff)
+				}
+				if diff := cmp.Diff(wantSources, gotSources); diff != "" {
+					t.Fatalf("run %d: entries differ (-first, +got):\n%s", i, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestPrefixMap(t *testing.T) {
+	ms := NewModules()
+	for name, src := range map[string]string{
+		"test.yang": `
+			module test {
+				prefix "t";
+				namespace "urn:t";
+				import foo { prefix f; }
+				import bar { prefix "b"; }
+				include test-sub;
+			}`,
+		"test-sub.yang": `
+			submodule test-sub {
+				belongs-to test { prefix "ts"; }
+				import foo { prefix foo; }
+			}`,
+		"foo.yang": `module foo { prefix "foo"; namespace "urn:foo"; }`,
+		"bar.yang": `module bar { prefix "bar"; namespace "urn:bar"; }`,
+	} {
+		if err := ms.Parse(src, name); err != nil {
+			t.Fatalf("cannot parse %s: %v", name, err)
+		}
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process modules: %v", errs)
+	}
+
+	tests := []struct {
+		desc          string
+		inName        string
+		want          map[string]string
+		wantErrSubstr string
+	}{{
+		desc:   "module importing two others",
+		inName: "test",
+		want: map[string]string{
+			"t": "test",
+			"f": "foo",
+			"b": "bar",
+		},
+	}, {
+		desc:   "submodule",
+		inName: "test-sub",
+		want: map[string]string{
+			"ts":  "test",
+			"foo": "foo",
+		},
+	}, {
+		desc:   "module without imports",
+		inName: "foo",
+		want:   map[string]string{"foo": "foo"},
+	}, {
+		desc:          "unknown module",
+		inName:        "nope",
+		wantErrSubstr: "module not found: nope",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := ms.PrefixMap(tt.inName)
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Fatalf("PrefixMap: %s", diff)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("PrefixMap (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestUnknownStatementHandler(t *testing.T) {
+	const src = `
+		module test {
+			prefix "t";
+			namespace "urn:t";
+			container c {
+				vendor-option "fast";
+				leaf l { type string; }
+			}
+		}`
+
+	type seen struct {
+		Parent, Keyword, Argument string
+	}
+	var got []seen
+	tests := []struct {
+		desc          string
+		inHandler     func(Node, *Statement) error
+		wantSeen      []seen
+		wantErrSubstr string
+	}{{
+		desc:          "no handler",
+		wantErrSubstr: "test.yang:6:5: unknown container field: vendor-option",
+	}, {
+		desc: "ignore",
+		inHandler: func(n Node, s *Statement) error {
+			got = append(got, seen{n.NName(), s.Keyword, s.Argument})
+			return nil
+		},
+		wantSeen: []seen{{"c", "vendor-option", "fast"}},
+	}, {
+		desc: "error",
+		inHandler: func(n Node, s *Statement) error {
+			got = append(got, seen{n.NName(), s.Keyword, s.Argument})
+			return fmt.Errorf("unsupported vendor keyword %s", s.Keyword)
+		},
+		wantSeen:      []seen{{"c", "vendor-option", "fast"}},
+		wantErrSubstr: "test.yang:6:5: unsupported vendor keyword vendor-option",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got = nil
+			ms := NewModules()
+			ms.ParseOptions.UnknownStatementHandler = tt.inHandler
+			err := ms.Parse(src, "test.yang")
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Fatalf("Parse: %s", diff)
+			}
+			if diff := cmp.Diff(tt.wantSeen, got); diff != "" {
+				t.Errorf("handler calls (-want, +got):\n%s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if errs := ms.Process(); len(errs) > 0 {
+				t.Fatalf("cannot process module: %v", errs)
+			}
+			if e := ToEntry(ms.Modules["test"]).Find("/t:c/t:l"); e == nil {
+				t.Errorf("cannot find leaf l within container c")
+			}
+		})
+	}
+}
+
+func TestImportRevisionDate(t *testing.T) {
+	ms := NewModules()
+	for name, src := range map[string]string{
+		"foo-2020.yang": `
+			module foo {
+				prefix "f";
+				namespace "urn:f";
+				revision 2020-01-01;
+				typedef t { type string; }
+				grouping g { leaf old { type string; } }
+				identity base;
+				identity old-id { base base; }
+				container c { leaf x { type string; } }
+			}`,
+		"foo-2021.yang": `
+			module foo {
+				prefix "f";
+				namespace "urn:f";
+				revision 2021-01-01;
+				revision 2020-01-01;
+				typedef t { type uint32; }
+				grouping g { leaf new { type string; } }
+				identity base;
+				identity new-id { base base; }
+				container c { leaf y { type string; } }
+			}`,
+		"old.yang": `
+			module old {
+				prefix "o";
+				namespace "urn:o";
+				import foo { prefix f; revision-date 2020-01-01; }
+				leaf l { type f:t; }
+				container c { uses f:g; }
+				leaf id { type identityref { base f:base; } }
+				augment "/f:c" { leaf aug { type string; } }
+			}`,
+		"new.yang": `
+			module new {
+				prefix "n";
+				namespace "urn:n";
+				import foo { prefix f; revision-date 2021-01-01; }
+				leaf l { type f:t; }
+				container c { uses f:g; }
+				leaf id { type identityref { base f:base; } }
+			}`,
+		"latest.yang": `
+			module latest {
+				prefix "l";
+				namespace "urn:l";
+				import foo { prefix f; }
+				leaf l { type f:t; }
+			}`,
+	} {
+		if err := ms.Parse(src, name); err != nil {
+			t.Fatalf("cannot parse %s: %v", name, err)
+		}
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process modules: %v", errs)
+	}
+
+	tests := []struct {
+		desc         string
+		inModule     string
+		wantType     TypeKind
+		wantChildren []string
+		wantIDs      []string
+	}{{
+		desc:         "import of the older revision",
+		inModule:     "old",
+		wantType:     Ystring,
+		wantChildren: []string{"old"},
+		wantIDs:      []string{"old-id"},
+	}, {
+		desc:         "import of the newer revision",
+		inModule:     "new",
+		wantType:     Yuint32,
+		wantChildren: []string{"new"},
+		wantIDs:      []string{"new-id"},
+	}, {
+		desc:     "import without a revision-date",
+		inModule: "latest",
+		wantType: Yuint32,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			e := ToEntry(ms.Modules[tt.inModule])
+			if got := e.Dir["l"].Type.Kind; got != tt.wantType {
+				t.Errorf("type of l: got %v, want %v", got, tt.wantType)
+			}
+			if tt.wantChildren != nil {
+				var got []string
+				for name := range e.Dir["c"].Dir {
+					got = append(got, name)
+				}
+				if diff := cmp.Diff(tt.wantChildren, got); diff != "" {
+					t.Errorf("children of c (-want, +got):\n%s", diff)
+				}
+			}
+			if tt.wantIDs != nil {
+				var got []string
+				for _, v := range e.Dir["id"].Type.IdentityBase.Values {
+					got = append(got, v.Name)
+				}
+				if diff := cmp.Diff(tt.wantIDs, got); diff != "" {
+					t.Errorf("identities derived from f:base (-want, +got):\n%s", diff)
+				}
+			}
+		})
+	}
+
+	// The augment applies only to the revision that is imported.
+	if ToEntry(ms.Modules["foo@2020-01-01"]).Dir["c"].Dir["aug"] == nil {
+		t.Errorf("augment of foo@2020-01-01 was not applied")
+	}
+	if ToEntry(ms.Modules["foo@2021-01-01"]).Dir["c"].Dir["aug"] != nil {
+		t.Errorf("augment of foo@2020-01-01 was applied to foo@2021-01-01")
+	}
+}
+
+func TestUnknownGroupings(t *testing.T) {
+	ms := NewModules()
+	for name, src := range map[string]string{
+		"test.yang": `module test {
+  prefix "t";
+  namespace "urn:t";
+  import other { prefix o; }
+  include test-sub;
+
+  typedef bad-type { type missing-type; }
+
+  grouping used {
+    uses missing-in-used;
+  }
+  grouping unused {
+    uses missing-in-unused;
+  }
+  container c {
+    uses used;
+    uses missing;
+    uses o:missing;
+  }
+}`,
+		"test-sub.yang": `submodule test-sub {
+  belongs-to test { prefix t; }
+  container s {
+    uses missing-in-sub;
+  }
+}`,
+		"other.yang": `module other {
+  prefix "o";
+  namespace "urn:o";
+}`,
+	} {
+		if err := ms.Parse(src, name); err != nil {
+			t.Fatalf("cannot parse %s: %v", name, err)
+		}
+	}
+
+	var got []string
+	for _, err := range ms.Process() {
+		got = append(got, err.Error())
+	}
+	// The unknown type stops processing before the groupings are expanded.
+	want := []string{
+		"test-sub.yang:4:5: unknown group: missing-in-sub",
+		"test.yang:7:22: unknown type: t:missing-type",
+		"test.yang:10:5: unknown group: missing-in-used",
+		"test.yang:13:5: unknown group: missing-in-unused",
+		"test.yang:17:5: unknown group: missing",
+		"test.yang:18:5: unknown group: o:missing",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Process errors (-want, +got):\n%s", diff)
+	}
+}
+
+func TestOnEntry(t *testing.T) {
+	ms := NewModules()
+	if err := ms.Parse(`
+		module test {
+			prefix "t";
+			namespace "urn:t";
+			container c {
+				config false;
+				leaf l { type string; }
+				leaf-list ll { type int8; }
+				list li {
+					key "k";
+					leaf k { type string; }
+				}
+				choice ch {
+					case a {
+						leaf x { type boolean; }
+					}
+				}
+			}
+			grouping gr {
+				leaf gl { type string; }
+			}
+			container ga { uses gr; }
+			container gb { uses gr; }
+			rpc r {
+				input {
+					leaf i { type string; }
+				}
+			}
+		}`, "test.yang"); err != nil {
+		t.Fatalf("cannot parse module: %v", err)
+	}
+
+	seen := map[*Entry]int{}
+	ms.OnEntry = func(e *Entry) {
+		seen[e]++
+		if (e.Kind == LeafEntry) != (e.Type != nil) {
+			t.Errorf("OnEntry: entry %s of kind %v has type %v", e.Name, e.Kind, e.Type)
+		}
+		if e.Name == "ll" && e.ListAttr == nil {
+			t.Errorf("OnEntry: leaf-list %s has no ListAttr", e.Name)
+		}
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process module: %v", errs)
+	}
+
+	var entries []*Entry
+	var walk func(e *Entry)
+	walk = func(e *Entry) {
+		entries = append(entries, e)
+		for _, ce := range e.Dir {
+			walk(ce)
+		}
+		if e.RPC != nil {
+			walk(e.RPC.Input)
+			walk(e.RPC.Output)
+		}
+	}
+	walk(ToEntry(ms.Modules["test"]))
+
+	// The leaf of the grouping is passed once for each use, and the
+	// grouping itself is not passed.
+	var paths []string
+	for e := range seen {
+		paths = append(paths, e.Path())
+	}
+	sort.Strings(paths)
+	for _, p := range []string{"/test/ga/gl", "/test/gb/gl"} {
+		if i := sort.SearchStrings(paths, p); i == len(paths) || paths[i] != p {
+			t.Errorf("OnEntry: not called with %s, got %v", p, paths)
+		}
+	}
+	for _, p := range paths {
+		if p == "/gr" || p == "/gl" || p == "/test/gr" {
+			t.Errorf("OnEntry: called with %s, which is not within the tree", p)
+		}
+	}
+
+	if got, want := len(seen), 17; got != want {
+		t.Errorf("OnEntry: called with %d entries, want %d", got, want)
+	}
+	if got, want := len(entries), len(seen); got != want {
+		t.Errorf("got %d entries within the module, want %d", got, want)
+	}
+	for _, e := range entries {
+		if n := seen[e]; n != 1 {
+			t.Errorf("OnEntry: called %d times with %s, want 1", n, e.Path())
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		desc string
+		in   string
+		want []string
+	}{{
+		desc: "valid keys",
+		in: `
+			list config-list {
+				key "a t:b";
+				leaf a { type string; }
+				leaf b { type string; }
+			}
+			list state-list {
+				config false;
+				leaf a { type string; }
+			}
+			list state-key {
+				config false;
+				key "a";
+				leaf a { type string; }
+			}
+			container state {
+				config false;
+				list keyless { leaf a { type string; } }
+			}
+			rpc r {
+				input { list keyless { leaf a { type string; } } }
+			}
+			notification n {
+				list keyless { leaf a { type string; } }
+			}`,
+	}, {
+		desc: "key is not a child",
+		in: `
+			list l {
+				key "a missing";
+				leaf a { type string; }
+			}`,
+		want: []string{"test.yang:5:4: key missing of list l is not a child of the list"},
+	}, {
+		desc: "key is not a leaf",
+		in: `
+			list l {
+				key "c ll";
+				container c { }
+				leaf-list ll { type string; }
+			}`,
+		want: []string{
+			"test.yang:5:4: key c of list l is not a leaf",
+			"test.yang:5:4: key ll of list l is not a leaf",
+		},
+	}, {
+		desc: "key named twice",
+		in: `
+			list l {
+				key "a t:a";
+				leaf a { type string; }
+			}`,
+		want: []string{"test.yang:5:4: key a of list l is named more than once"},
+	}, {
+		desc: "config false key of a config list",
+		in: `
+			list l {
+				key "a";
+				leaf a { type string; config false; }
+			}`,
+		want: []string{"test.yang:7:5: key a of list l is config false, but the list is configuration data"},
+	}, {
+		desc: "config list without a key",
+		in: `
+			container c {
+				list l { leaf a { type string; } }
+			}`,
+		want: []string{"test.yang:6:5: list l is configuration data but has no key"},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ms := NewModules()
+			if err := ms.Parse(`
+		module test {
+			prefix "t";
+			namespace "urn:t";`+tt.in+`
+		}`, "test.yang"); err != nil {
+				t.Fatalf("cannot parse module: %v", err)
+			}
+			if errs := ms.Process(); len(errs) > 0 {
+				t.Fatalf("cannot process module: %v", errs)
+			}
+			var got []string
+			for _, err := range ms.Validate() {
+				got = append(got, err.Error())
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Validate (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestModuleVersion(t *testing.T) {
+	tests := []struct {
+		desc          string
+		in            string
+		want          string
+		wantErrSubstr string
+	}{{
+		desc: "no yang-version",
+		in:   `module test { prefix t; namespace urn:t; }`,
+		want: "1.0",
+	}, {
+		desc: "yang-version 1",
+		in:   `module test { yang-version 1; prefix t; namespace urn:t; }`,
+		want: "1.0",
+	}, {
+		desc: "yang-version 1.1",
+		in:   `module test { yang-version 1.1; prefix t; namespace urn:t; }`,
+		want: "1.1",
+	}, {
+		desc: "submodule",
+		in:   `submodule test { yang-version "1.1"; belongs-to t { prefix t; } }`,
+		want: "1.1",
+	}, {
+		desc:          "unknown yang-version",
+		in:            `module test { yang-version 2; prefix t; namespace urn:t; }`,
+		wantErrSubstr: `test.yang:1:15: unknown yang-version "2" in module test`,
+	}, {
+		desc:          "yang-version 1.0 is not defined",
+		in:            `submodule test { yang-version 1.0; belongs-to t { prefix t; } }`,
+		wantErrSubstr: `test.yang:1:18: unknown yang-version "1.0" in submodule test`,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ms := NewModules()
+			err := ms.Parse(tt.in, "test.yang")
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Fatalf("Parse: %s", diff)
+			}
+			if err != nil {
+				if len(ms.Modules)+len(ms.SubModules) != 0 {
+					t.Errorf("Parse: module with an unknown yang-version was added")
+				}
+				return
+			}
+			m := ms.Modules["test"]
+			if m == nil {
+				m = ms.SubModules["test"]
+			}
+			if got := m.Version(); got != tt.want {
+				t.Errorf("Version() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestConcurrentReads reads a processed tree from many goroutines at once.
+// It finds data races when run with -race.
+func TestConcurrentReads(t *testing.T) {
+	ms := NewModules()
+	for name, src := range map[string]string{
+		"test.yang": `
+			module test {
+				yang-version 1.1;
+				prefix "t";
+				namespace "urn:t";
+				include test-sub;
+				container c {
+					list l {
+						key "k";
+						leaf k { type string; }
+						leaf ref { type leafref { path "../k"; } }
+						action reset { input { leaf why { type string; } } }
+					}
+					leaf d { type int8; default 3; }
+				}
+				rpc r;
+				rpc s { output { leaf out { type string; } } }
+			}`,
+		"test-sub.yang": `
+			submodule test-sub {
+				yang-version 1.1;
+				belongs-to test { prefix "t"; }
+				container sub { leaf x { type string; } }
+			}`,
+		"aug.yang": `
+			module aug {
+				prefix "a";
+				namespace "urn:a";
+				import test { prefix t; }
+				augment "/t:c" { leaf extra { type string; } }
+			}`,
+	} {
+		if err := ms.Parse(src, name); err != nil {
+			t.Fatalf("cannot parse %s: %v", name, err)
+		}
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process modules: %v", errs)
+	}
+
+	paths := []string{
+		"/t:c/t:l/t:k",
+		"/t:c/a:extra",
+		"/t:r/input",
+		"/t:r/output",
+		"/t:s/output/out",
+		"c/l/reset/input/why",
+		"sub/x",
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, name := range []string{"test", "aug"} {
+				e, errs := ms.GetModule(name)
+				if len(errs) > 0 {
+					t.Errorf("GetModule(%s): %v", name, errs)
+					return
+				}
+				e.Namespace()
+			}
+			root := ToEntry(ms.Modules["test"])
+			for _, p := range paths {
+				e := root.Find(p)
+				if e == nil {
+					t.Errorf("Find(%s): not found", p)
+					continue
+				}
+				e.Path()
+				e.Namespace()
+				if _, err := e.InstantiatingModule(); err != nil {
+					t.Errorf("InstantiatingModule of %s: %v", p, err)
+				}
+				e.DefaultValues()
+			}
+			if _, err := ms.FindModuleByNamespace("urn:a"); err != nil {
+				t.Errorf("FindModuleByNamespace: %v", err)
+			}
+			ms.AllEntries()
+			ms.LeafPaths()
+		}()
+	}
+	wg.Wait()
+}
+
+// TestAugmentImplicitInputOutput checks that an augment may add nodes to the
+// input or output of an rpc or action that does not define it.
+func TestAugmentImplicitInputOutput(t *testing.T) {
+	ms := NewModules()
+	if err := ms.Parse(`
+		module m {
+			yang-version 1.1;
+			prefix "m";
+			namespace "urn:m";
+			rpc r;
+			container c {
+				action act;
+			}
+			augment "/m:r/m:input" { leaf x { type string; } }
+			augment "/m:c/m:act/m:output" { leaf y { type string; } }
+		}`, "m.yang"); err != nil {
+		t.Fatalf("cannot parse module: %v", err)
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process module: %v", errs)
+	}
+	root := ToEntry(ms.Modules["m"])
+	for _, tt := range []struct {
+		desc string
+		io   *Entry
+		leaf string
+		want string
+		find string
+	}{{
+		desc: "rpc input",
+		io:   root.Dir["r"].RPC.Input,
+		leaf: "x",
+		want: "/m/r/input/x",
+		find: "/m:r/m:input/m:x",
+	}, {
+		desc: "action output",
+		io:   root.Dir["c"].Dir["act"].RPC.Output,
+		leaf: "y",
+		want: "/m/c/act/output/y",
+		find: "/m:c/m:act/m:output/m:y",
+	}} {
+		if tt.io == nil {
+			t.Errorf("%s: not in the tree", tt.desc)
+			continue
+		}
+		e := tt.io.Dir[tt.leaf]
+		if e == nil {
+			t.Errorf("%s: augmented leaf %s not found", tt.desc, tt.leaf)
+			continue
+		}
+		if got := e.Path(); got != tt.want {
+			t.Errorf("%s: got path %s, want %s", tt.desc, got, tt.want)
+		}
+		if got := root.Find(tt.find); got != e {
+			t.Errorf("%s: Find(%s) = %v, want the augmented leaf", tt.desc, tt.find, got)
+		}
+	}
+}
+
+func TestImplicitInputOutputRoundTrip(t *testing.T) {
+	process := func(src string) *Entry {
+		t.Helper()
+		ms := NewModules()
+		if err := ms.Parse(src, "m.yang"); err != nil {
+			t.Fatalf("cannot parse module: %v\n%s", err, src)
+		}
+		if errs := ms.Process(); len(errs) > 0 {
+			t.Fatalf("cannot process module: %v\n%s", errs, src)
+		}
+		return ToEntry(ms.Modules["m"])
+	}
+	root := process(`
+		module m {
+			prefix "m";
+			namespace "urn:m";
+			rpc r {
+				output { leaf o { type string; } }
+			}
+		}`)
+	r := root.Dir["r"]
+	if r.RPC.Input == nil || len(r.RPC.Input.Dir) != 0 {
+		t.Fatalf("rpc without an input statement: got input %v, want an empty input", r.RPC.Input)
+	}
+
+	src := root.YANGString()
+	if strings.Contains(src, "input") {
+		t.Errorf("YANGString wrote an input for an rpc without one:\n%s", src)
+	}
+	round := process(src)
+	if got := round.Dir["r"].RPC; len(got.Input.Dir) != 0 || got.Output.Dir["o"] == nil {
+		t.Errorf("round trip of rpc r: got input %v and output %v, want an empty input and output o", got.Input.Dir, got.Output.Dir)
+	}
+
+	b, err := r.ToJSON()
+	if err != nil {
+		t.Fatalf("ToJSON: %v", err)
+	}
+	if got := string(b); strings.Contains(got, `"input"`) || !strings.Contains(got, `"output"`) {
+		t.Errorf("ToJSON of an rpc without an input statement: got %s, want only an output", got)
+	}
+}
+
+func TestMainModule(t *testing.T) {
+	ms := NewModules()
+	ms.AddPath("../../testdata")
+	if err := ms.Read("base"); err != nil {
+		t.Fatalf("cannot read base: %v", err)
+	}
+	if err := ms.Parse(`
+		submodule orphan {
+			belongs-to missing { prefix "m"; }
+		}`, "orphan.yang"); err != nil {
+		t.Fatalf("cannot parse orphan: %v", err)
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process modules: %v", errs)
+	}
+	base := ms.Modules["base"]
+
+	tests := []struct {
+		desc          string
+		in            *Module
+		want          *Module
+		wantErrSubstr string
+	}{{
+		desc: "submodule",
+		in:   ms.SubModules["sub"],
+		want: base,
+	}, {
+		desc: "module",
+		in:   base,
+		want: base,
+	}, {
+		desc:          "module not found",
+		in:            ms.SubModules["orphan"],
+		wantErrSubstr: "orphan.yang:3:4: module missing of submodule orphan not found",
+	}, {
+		desc:          "submodule not read into a Modules",
+		in:            &Module{Name: "lone", BelongsTo: &BelongsTo{Name: "base"}},
+		wantErrSubstr: "module base of submodule lone not found",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := tt.in.MainModule()
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Fatalf("MainModule: %s", diff)
+			}
+			if got != tt.want {
+				t.Errorf("MainModule() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetModuleForEntry(t *testing.T) {
+	ms := NewModules()
+	for name, src := range map[string]string{
+		"test.yang": `
+			module test {
+				prefix "t";
+				namespace "urn:t";
+				include test-sub;
+				container c {
+					list l {
+						key "k";
+						leaf k { type string; }
+					}
+				}
+				rpc r { input { leaf in { type string; } } }
+			}`,
+		"test-sub.yang": `
+			submodule test-sub {
+				belongs-to test { prefix "t"; }
+				container sub { leaf x { type string; } }
+			}`,
+		"aug.yang": `
+			module aug {
+				prefix "a";
+				namespace "urn:a";
+				import test { prefix t; }
+				augment "/t:c" {
+					container extra { leaf y { type string; } }
+				}
+			}`,
+	} {
+		if err := ms.Parse(src, name); err != nil {
+			t.Fatalf("cannot parse %s: %v", name, err)
+		}
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process modules: %v", errs)
+	}
+	root := ToEntry(ms.Modules["test"])
+
+	tests := []struct {
+		desc          string
+		in            *Entry
+		want          string
+		wantErrSubstr string
+	}{{
+		desc: "module",
+		in:   root,
+		want: "test",
+	}, {
+		desc: "list key",
+		in:   root.Dir["c"].Dir["l"].Dir["k"],
+		want: "test",
+	}, {
+		desc: "rpc input",
+		in:   root.Dir["r"].RPC.Input.Dir["in"],
+		want: "test",
+	}, {
+		desc: "node of submodule",
+		in:   root.Dir["sub"].Dir["x"],
+		want: "test",
+	}, {
+		desc: "augmented node",
+		in:   root.Dir["c"].Dir["extra"],
+		want: "aug",
+	}, {
+		desc: "child of augmented node",
+		in:   root.Dir["c"].Dir["extra"].Dir["y"],
+		want: "aug",
+	}, {
+		desc:          "nil",
+		wantErrSubstr: "nil entry",
+	}, {
+		desc:          "unknown namespace",
+		in:            &Entry{Name: "x", namespace: &Value{Name: "urn:unknown"}, Parent: &Entry{Name: "y"}},
+		wantErrSubstr: `/y/x: "urn:unknown": no such namespace`,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := ms.GetModuleForEntry(tt.in)
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Fatalf("GetModuleForEntry: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if got != ms.Modules[tt.want] {
+				t.Errorf("GetModuleForEntry(%s) = %s, want %s", tt.in.Path(), got.Name, tt.want)
+			}
+		})
+	}
+}
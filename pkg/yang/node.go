@@ -334,6 +334,57 @@ Loop:
 	return nil
 }
 
+// walkUses calls fn for each uses statement within n, recursively, in the
+// order in which they are defined.  The groupings referenced by the uses
+// statements are not walked.
+func walkUses(n Node, fn func(*Uses)) {
+	if u, ok := n.(*Uses); ok {
+		fn(u)
+	}
+	v := reflect.ValueOf(n).Elem()
+	t := v.Type()
+	nf := t.NumField()
+
+Loop:
+	for i := 0; i < nf; i++ {
+		ft := t.Field(i)
+		yang := ft.Tag.Get("yang")
+		if yang == "" {
+			continue
+		}
+		parts := strings.Split(yang, ",")
+		for _, p := range parts[1:] {
+			if p == "nomerge" {
+				continue Loop
+			}
+		}
+
+		// Skip uppercase elements.
+		if parts[0][0] >= 'A' && parts[0][0] <= 'Z' {
+			continue
+		}
+
+		f := v.Field(i)
+		if !f.IsValid() || f.IsNil() {
+			continue
+		}
+
+		switch ft.Type.Kind() {
+		case reflect.Ptr:
+			if ft.Type.Implements(nodeType) {
+				walkUses(f.Interface().(Node), fn)
+			}
+		case reflect.Slice:
+			if !ft.Type.Elem().Implements(nodeType) {
+				continue
+			}
+			for i := 0; i < f.Len(); i++ {
+				walkUses(f.Index(i).Interface().(Node), fn)
+			}
+		}
+	}
+}
+
 // PrintNode prints node n to w, recursively.
 // TODO(borman): display more information
 func PrintNode(w io.Writer, n Node) {
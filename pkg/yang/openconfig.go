@@ -0,0 +1,156 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+// This file implements helpers that are specific to OpenConfig modules.
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// isOpenConfigModule returns true if m follows the OpenConfig style guide,
+// i.e., it is versioned with the openconfig-version extension, or its name
+// has the "openconfig-" prefix.
+func isOpenConfigModule(m *Module) bool {
+	if m == nil {
+		return false
+	}
+	if m.Kind() == "submodule" && m.Modules != nil {
+		if bm := m.Modules.Modules[m.BelongsTo.Name]; bm != nil {
+			m = bm
+		}
+	}
+	if strings.HasPrefix(m.Name, "openconfig-") {
+		return true
+	}
+	exts, err := MatchingExtensions(m, "openconfig-extensions", "openconfig-version")
+	return err == nil && len(exts) > 0
+}
+
+// OCUnifiedView returns a copy of the subtree rooted at e in which the
+// config and state containers of OpenConfig modules are collapsed into their
+// parent, such that each leaf appears only once. This is the "compressed"
+// view of an OpenConfig schema.
+//
+// Where a node appears within both the config and state containers, the node
+// from the config container is used. Nodes that appear only within the state
+// container are included, and are marked as config false so that ReadOnly
+// continues to report the same value for each node as in the uncompressed
+// schema. A config node also replaces a node of the same name in the parent,
+// such as the key leaf of a list, which refers to it. Directories whose config
+// and state containers are not defined within an OpenConfig module are not
+// modified.
+func (e *Entry) OCUnifiedView() *Entry {
+	ne := e.dup()
+	ne.ocCompress()
+	return ne
+}
+
+// ocCompress collapses the OpenConfig config and state containers within the
+// subtree rooted at e.
+func (e *Entry) ocCompress() {
+	for _, ce := range e.Dir {
+		ce.ocCompress()
+	}
+
+	config, state := e.Dir["config"], e.Dir["state"]
+	if config == nil || state == nil || !config.IsContainer() || !state.IsContainer() || !isOpenConfigModule(RootNode(config.Node)) {
+		return
+	}
+
+	delete(e.Dir, "config")
+	delete(e.Dir, "state")
+	for name, ce := range config.Dir {
+		ce.ocMove(e)
+		e.Dir[name] = ce
+	}
+	for name, ce := range state.Dir {
+		if _, ok := e.Dir[name]; ok {
+			continue
+		}
+		ce.ocMove(e)
+		e.Dir[name] = ce
+	}
+}
+
+// ocMove reparents e to p, setting the config statement of e such that its
+// ReadOnly value is unchanged by the move.
+func (e *Entry) ocMove(p *Entry) {
+	if e.ReadOnly() && !p.ReadOnly() {
+		e.Config = TSFalse
+	}
+	e.Parent = p
+}
+
+// OCConfigStateConsistent returns true if, within the subtree rooted at e,
+// each leaf or leaf-list of the config container of an OpenConfig module has
+// the same type as the node of the same name within the sibling state
+// container, as is required for them to be unified by OCUnifiedView. Types
+// are compared with YangType.Equal. If they are not consistent, false is
+// returned along with an error describing each mismatched pair, ordered by
+// path. Config and state containers that are not defined within an
+// OpenConfig module are not checked.
+func (e *Entry) OCConfigStateConsistent() (bool, error) {
+	var msgs []string
+	e.ocCheckConfigState(func(msg string) {
+		msgs = append(msgs, msg)
+	})
+	if len(msgs) > 0 {
+		sort.Strings(msgs)
+		return false, fmt.Errorf("inconsistent OpenConfig config and state: %s", strings.Join(msgs, "; "))
+	}
+	return true, nil
+}
+
+// ocCheckConfigState calls f with a description of each config leaf or
+// leaf-list within the subtree rooted at e whose state counterpart differs
+// in kind or type.
+func (e *Entry) ocCheckConfigState(f func(string)) {
+	for _, ce := range e.Dir {
+		ce.ocCheckConfigState(f)
+	}
+
+	config, state := e.Dir["config"], e.Dir["state"]
+	if config == nil || state == nil || !config.IsContainer() || !state.IsContainer() || !isOpenConfigModule(RootNode(config.Node)) {
+		return
+	}
+	for name, ce := range config.Dir {
+		se := state.Dir[name]
+		if se == nil || !(ce.IsLeaf() || ce.IsLeafList()) {
+			continue
+		}
+		switch {
+		case ce.IsLeaf() != se.IsLeaf() || ce.IsLeafList() != se.IsLeafList():
+			f(fmt.Sprintf("%s is a %s but %s is a %s", ce.Path(), ocNodeKind(ce), se.Path(), ocNodeKind(se)))
+		case !ce.Type.Equal(se.Type):
+			f(fmt.Sprintf("%s has type %s but %s has type %s", ce.Path(), ce.Type.Name, se.Path(), se.Type.Name))
+		}
+	}
+}
+
+// ocNodeKind returns the kind of statement that defines e, as used within the
+// errors of OCConfigStateConsistent. Leaf-lists are represented by Leaf
+// nodes, so are distinguished by their ListAttr.
+func ocNodeKind(e *Entry) string {
+	switch {
+	case e.IsLeafList():
+		return "leaf-list"
+	case e.Node == nil:
+		return "node"
+	}
+	return e.Node.Kind()
+}
@@ -0,0 +1,247 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+)
+
+func TestOCUnifiedView(t *testing.T) {
+	const ocModule = `
+		module %s {
+			prefix "oc";
+			namespace "urn:%s";
+
+			%s
+
+			grouping intf-config {
+				leaf name { type string; }
+				leaf mtu { type uint16; }
+			}
+
+			grouping intf-state {
+				leaf oper-status { type string; }
+				container counters {
+					leaf in-pkts { type uint64; }
+				}
+			}
+
+			container interfaces {
+				list interface {
+					key "name";
+
+					leaf name {
+						type leafref { path "../config/name"; }
+					}
+
+					container config {
+						uses intf-config;
+					}
+
+					container state {
+						config false;
+						uses intf-config;
+						uses intf-state;
+					}
+				}
+			}
+		}`
+
+	tests := []struct {
+		desc string
+		// inModule is the name of the module, and inVersion is an optional
+		// openconfig-version statement.
+		inModule  string
+		inVersion string
+		// wantChildren are the children of the interface list, mapped to
+		// whether they are read-only.
+		wantChildren map[string]bool
+	}{{
+		desc:     "openconfig module by name",
+		inModule: "openconfig-interfaces",
+		wantChildren: map[string]bool{
+			"name":        false,
+			"mtu":         false,
+			"oper-status": true,
+			"counters":    true,
+		},
+	}, {
+		desc:      "openconfig module by version extension",
+		inModule:  "ifs",
+		inVersion: `import openconfig-extensions { prefix oc-ext; } oc-ext:openconfig-version "1.0.0";`,
+		wantChildren: map[string]bool{
+			"name":        false,
+			"mtu":         false,
+			"oper-status": true,
+			"counters":    true,
+		},
+	}, {
+		desc:     "non-openconfig module",
+		inModule: "ifs",
+		wantChildren: map[string]bool{
+			"name":   false,
+			"config": false,
+			"state":  true,
+		},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ms := NewModules()
+			if err := ms.Parse(fmt.Sprintf(ocModule, tt.inModule, tt.inModule, tt.inVersion), tt.inModule+".yang"); err != nil {
+				t.Fatalf("cannot parse module: %v", err)
+			}
+			if err := ms.Parse(`
+				module openconfig-extensions {
+					prefix "oc-ext";
+					namespace "urn:oc-ext";
+					extension openconfig-version { argument "semver"; }
+				}`, "openconfig-extensions.yang"); err != nil {
+				t.Fatalf("cannot parse openconfig-extensions: %v", err)
+			}
+			if errs := ms.Process(); len(errs) > 0 {
+				t.Fatalf("cannot process modules: %v", errs)
+			}
+			mod, errs := ms.GetModule(tt.inModule)
+			if errs != nil {
+				t.Fatalf("cannot get module: %v", errs)
+			}
+
+			got := mod.OCUnifiedView().Dir["interfaces"].Dir["interface"]
+			gotChildren := map[string]bool{}
+			for name, ce := range got.Dir {
+				gotChildren[name] = ce.ReadOnly()
+				if ce.Parent != got {
+					t.Errorf("%s: parent not updated", name)
+				}
+			}
+			if diff := cmp.Diff(tt.wantChildren, gotChildren); diff != "" {
+				t.Errorf("OCUnifiedView children (-want, +got):\n%s", diff)
+			}
+			if c := got.Dir["counters"]; c != nil {
+				if in := c.Dir["in-pkts"]; in == nil || !in.ReadOnly() {
+					t.Errorf("counters/in-pkts: got %v, want read-only leaf", in)
+				}
+			}
+			if _, ok := got.Dir["config"]; !ok {
+				// The key leaf is replaced by the config leaf it refers to.
+				if n := got.Dir["name"]; n.Type.Kind != Ystring {
+					t.Errorf("name: got type %v, want %v", n.Type.Kind, Ystring)
+				}
+			}
+
+			// The original tree must not be modified.
+			var origChildren []string
+			for name := range mod.Dir["interfaces"].Dir["interface"].Dir {
+				origChildren = append(origChildren, name)
+			}
+			sort.Strings(origChildren)
+			if diff := cmp.Diff([]string{"config", "name", "state"}, origChildren); diff != "" {
+				t.Errorf("original tree was modified (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOCConfigStateConsistent(t *testing.T) {
+	const ocModule = `
+		module %s {
+			prefix "oc";
+			namespace "urn:%s";
+
+			container interfaces {
+				list interface {
+					key "name";
+					leaf name {
+						type leafref { path "../config/name"; }
+					}
+					container config {
+						leaf name { type string; }
+						leaf mtu { type uint16; }
+						leaf-list tags { type string; }
+					}
+					container state {
+						config false;
+						leaf name { type string; }
+						leaf mtu { type %s; }
+						%s
+						leaf oper-status { type string; }
+					}
+				}
+			}
+		}`
+
+	tests := []struct {
+		desc          string
+		inModule      string
+		inStateMTU    string
+		inStateTags   string
+		want          bool
+		wantErrSubstr string
+	}{{
+		desc:        "matching types",
+		inModule:    "openconfig-interfaces",
+		inStateMTU:  "uint16",
+		inStateTags: "leaf-list tags { type string; }",
+		want:        true,
+	}, {
+		desc:          "mismatched types",
+		inModule:      "openconfig-interfaces",
+		inStateMTU:    "uint32",
+		inStateTags:   "leaf-list tags { type string; }",
+		wantErrSubstr: "/openconfig-interfaces/interfaces/interface/config/mtu has type uint16 but /openconfig-interfaces/interfaces/interface/state/mtu has type uint32",
+	}, {
+		desc:          "mismatched kinds",
+		inModule:      "openconfig-interfaces",
+		inStateMTU:    "uint16",
+		inStateTags:   "leaf tags { type string; }",
+		wantErrSubstr: "/openconfig-interfaces/interfaces/interface/config/tags is a leaf-list but /openconfig-interfaces/interfaces/interface/state/tags is a leaf",
+	}, {
+		desc:        "non-openconfig module is not checked",
+		inModule:    "ifs",
+		inStateMTU:  "uint32",
+		inStateTags: "leaf-list tags { type string; }",
+		want:        true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ms := NewModules()
+			if err := ms.Parse(fmt.Sprintf(ocModule, tt.inModule, tt.inModule, tt.inStateMTU, tt.inStateTags), tt.inModule+".yang"); err != nil {
+				t.Fatalf("cannot parse module: %v", err)
+			}
+			if errs := ms.Process(); len(errs) > 0 {
+				t.Fatalf("cannot process modules: %v", errs)
+			}
+			mod, errs := ms.GetModule(tt.inModule)
+			if errs != nil {
+				t.Fatalf("cannot get module: %v", errs)
+			}
+
+			got, err := mod.OCConfigStateConsistent()
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Fatalf("OCConfigStateConsistent: %s", diff)
+			}
+			if got != tt.want {
+				t.Errorf("OCConfigStateConsistent: got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
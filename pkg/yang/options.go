@@ -29,6 +29,15 @@ type Options struct {
 	StoreUses bool
 	// DeviateOptions contains options for how deviations are handled.
 	DeviateOptions DeviateOptions
+	// UnknownStatementHandler, if set, is called for each statement with
+	// an unprefixed keyword that is not valid within its parent statement,
+	// such as a vendor keyword within a container, when the statement is
+	// parsed. parent is the node being built from the parent statement. If
+	// the handler returns nil, the statement is ignored, otherwise the
+	// returned error, prefixed by the location of the statement, is
+	// returned from parsing. If UnknownStatementHandler is nil, such
+	// statements are reported as errors.
+	UnknownStatementHandler func(parent Node, s *Statement) error
 }
 
 // DeviateOptions contains options for how deviations are handled.
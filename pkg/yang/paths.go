@@ -0,0 +1,126 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+// This file implements the enumeration of the data tree paths of an Entry
+// tree.
+
+import (
+	"sort"
+)
+
+// SchemaPath returns the path of e within the schema tree, which includes
+// the names of choice and case entries, including the implicit case of a
+// choice's shorthand case, and of the input and output of RPCs and actions.
+// For example, the path of leaf l within case a of choice c of module m is
+// "/m/c/a/l". This is the path returned by Path, which SchemaPath is provided
+// to distinguish from the path of e within the data tree, "/m/l", as used by
+// StatePaths and ConfigPaths. A nil Entry returns "".
+func (e *Entry) SchemaPath() string {
+	return e.Path()
+}
+
+// dataPath returns the path of e within the data tree, which, unlike Path,
+// does not include the names of choice and case entries.
+func (e *Entry) dataPath() string {
+	if e == nil {
+		return ""
+	}
+	if e.IsChoice() || e.IsCase() {
+		return e.Parent.dataPath()
+	}
+	return e.Parent.dataPath() + "/" + e.Name
+}
+
+// StatePaths returns the sorted data tree paths of the leaves and leaf-lists
+// within the subtree rooted at e that are state data, i.e., that are config
+// false. The contents of RPC output statements and notifications are
+// considered to be state data. The contents of RPC input statements are
+// neither state nor configuration data, and so are not included.
+func (e *Entry) StatePaths() []string {
+	return e.leafPaths(true)
+}
+
+// ConfigPaths returns the sorted data tree paths of the leaves and
+// leaf-lists within the subtree rooted at e that are configuration data. It
+// is the complement of StatePaths, and so does not include the contents of
+// RPCs and notifications.
+func (e *Entry) ConfigPaths() []string {
+	return e.leafPaths(false)
+}
+
+// leafPaths returns the sorted data tree paths of the leaves and leaf-lists
+// within the subtree rooted at e that are state data if state is true, or
+// configuration data otherwise.
+func (e *Entry) leafPaths(state bool) []string {
+	var paths []string
+	var walk func(e *Entry, inState bool)
+	walk = func(e *Entry, inState bool) {
+		if e == nil {
+			return
+		}
+		switch {
+		case e.Kind == InputEntry:
+			return
+		case e.Kind == NotificationEntry, e.ReadOnly():
+			inState = true
+		}
+		if (e.IsLeaf() || e.IsLeafList()) && inState == state {
+			paths = append(paths, e.dataPath())
+		}
+		for _, ce := range e.Dir {
+			walk(ce, inState)
+		}
+		if e.RPC != nil {
+			walk(e.RPC.Output, inState)
+		}
+	}
+	walk(e, false)
+	sort.Strings(paths)
+	return paths
+}
+
+// LeafPaths returns the type of every leaf and leaf-list within the latest
+// revision of each module within ms, keyed by its data tree path, which, as
+// for StatePaths and ConfigPaths, does not include the names of choice and
+// case entries. Both configuration and state data are included, as are the
+// leaves within the input and output of RPCs and actions, whose paths
+// include "input" or "output", and within notifications. Process should be
+// called first. Comparing the LeafPaths of two releases of a set of modules
+// shows the leaves that were added or removed, or whose types changed, as
+// reported by YangType.Equal.
+func (ms *Modules) LeafPaths() map[string]*YangType {
+	paths := map[string]*YangType{}
+	var walk func(e *Entry)
+	walk = func(e *Entry) {
+		if e == nil {
+			return
+		}
+		if e.IsLeaf() || e.IsLeafList() {
+			paths[e.dataPath()] = e.Type
+		}
+		for _, ce := range e.Dir {
+			walk(ce)
+		}
+		if e.RPC != nil {
+			walk(e.RPC.Input)
+			walk(e.RPC.Output)
+		}
+	}
+	for _, name := range ms.SortedModuleNames() {
+		walk(ToEntry(ms.Modules[name]))
+	}
+	return paths
+}
@@ -0,0 +1,213 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestStateAndConfigPaths(t *testing.T) {
+	ms := NewModules()
+	if err := ms.Parse(`
+		module test {
+			prefix "t";
+			namespace "urn:t";
+
+			container c {
+				leaf name { type string; }
+				choice ch {
+					case a { leaf-list a { type string; } }
+					leaf b { type string; config false; }
+				}
+				container state {
+					config false;
+					leaf counter { type uint64; }
+				}
+				list l {
+					key "k";
+					leaf k { type string; }
+					leaf oper { type string; config false; }
+				}
+			}
+			rpc r {
+				input { leaf in { type string; } }
+				output { leaf out { type string; } }
+			}
+			notification n {
+				leaf note { type string; }
+			}
+		}`, "test.yang"); err != nil {
+		t.Fatalf("cannot parse module: %v", err)
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process module: %v", errs)
+	}
+	root := ToEntry(ms.Modules["test"])
+
+	tests := []struct {
+		desc       string
+		in         *Entry
+		wantState  []string
+		wantConfig []string
+	}{{
+		desc: "module",
+		in:   root,
+		wantState: []string{
+			"/test/c/b",
+			"/test/c/l/oper",
+			"/test/c/state/counter",
+			"/test/n/note",
+			"/test/r/output/out",
+		},
+		wantConfig: []string{
+			"/test/c/a",
+			"/test/c/l/k",
+			"/test/c/name",
+		},
+	}, {
+		desc:      "state container",
+		in:        root.Dir["c"].Dir["state"],
+		wantState: []string{"/test/c/state/counter"},
+	}, {
+		desc:      "rpc",
+		in:        root.Dir["r"],
+		wantState: []string{"/test/r/output/out"},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if diff := cmp.Diff(tt.wantState, tt.in.StatePaths()); diff != "" {
+				t.Errorf("StatePaths (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantConfig, tt.in.ConfigPaths()); diff != "" {
+				t.Errorf("ConfigPaths (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSchemaPath(t *testing.T) {
+	ms := NewModules()
+	if err := ms.Parse(`
+		module m {
+			prefix "m";
+			namespace "urn:m";
+
+			container top {
+				choice c {
+					case a {
+						leaf l { type string; }
+						choice inner {
+							case x { leaf deep { type string; } }
+							leaf shorthand { type string; }
+						}
+					}
+					leaf b { type string; }
+				}
+			}
+		}`, "m.yang"); err != nil {
+		t.Fatalf("cannot parse module: %v", err)
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process module: %v", errs)
+	}
+	top := ToEntry(ms.Modules["m"]).Dir["top"]
+	a := top.Dir["c"].Dir["a"]
+
+	tests := []struct {
+		desc           string
+		in             *Entry
+		wantSchemaPath string
+		wantDataPath   string
+	}{{
+		desc:           "leaf in case",
+		in:             a.Dir["l"],
+		wantSchemaPath: "/m/top/c/a/l",
+		wantDataPath:   "/m/top/l",
+	}, {
+		desc:           "leaf in nested choice",
+		in:             a.Dir["inner"].Dir["x"].Dir["deep"],
+		wantSchemaPath: "/m/top/c/a/inner/x/deep",
+		wantDataPath:   "/m/top/deep",
+	}, {
+		desc:           "shorthand case in nested choice",
+		in:             a.Dir["inner"].Dir["shorthand"].Dir["shorthand"],
+		wantSchemaPath: "/m/top/c/a/inner/shorthand/shorthand",
+		wantDataPath:   "/m/top/shorthand",
+	}, {
+		desc:           "shorthand case",
+		in:             top.Dir["c"].Dir["b"].Dir["b"],
+		wantSchemaPath: "/m/top/c/b/b",
+		wantDataPath:   "/m/top/b",
+	}, {
+		desc:           "choice",
+		in:             top.Dir["c"],
+		wantSchemaPath: "/m/top/c",
+		wantDataPath:   "/m/top",
+	}, {
+		desc: "nil",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if got := tt.in.SchemaPath(); got != tt.wantSchemaPath {
+				t.Errorf("SchemaPath() = %q, want %q", got, tt.wantSchemaPath)
+			}
+			if got := tt.in.dataPath(); got != tt.wantDataPath {
+				t.Errorf("dataPath() = %q, want %q", got, tt.wantDataPath)
+			}
+		})
+	}
+}
+
+func TestLeafPaths(t *testing.T) {
+	ms := NewModules()
+	if err := ms.Read("testdata/leafpaths.yang"); err != nil {
+		t.Fatalf("cannot read module: %v", err)
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process module: %v", errs)
+	}
+
+	// Each line of the golden file is the path of a leaf, the name of its
+	// type, and the signature of its type, separated by tabs.
+	paths := ms.LeafPaths()
+	var lines []string
+	for p, y := range paths {
+		lines = append(lines, fmt.Sprintf("%s\t%s\t%s", p, y.Name, y.signature()))
+	}
+	sort.Strings(lines)
+	got := strings.Join(lines, "\n") + "\n"
+
+	b, err := ioutil.ReadFile("testdata/leafpaths.txt")
+	if err != nil {
+		t.Fatalf("cannot read golden file: %v", err)
+	}
+	if diff := cmp.Diff(string(b), got); diff != "" {
+		t.Errorf("LeafPaths (-want, +got):\n%s", diff)
+	}
+
+	// The types are those of the entries.
+	root := ToEntry(ms.Modules["leafpaths"])
+	if got, want := paths["/leafpaths/system/load"], root.Dir["system"].Dir["load"].Type; got != want {
+		t.Errorf("LeafPaths: got type %v for /leafpaths/system/load, want %v", got, want)
+	}
+}
@@ -0,0 +1,278 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+// This file implements a stable JSON representation of a resolved Entry
+// tree, including the details of the types of its leaves.
+
+import (
+	"encoding/json"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ToJSONOptions contains options for ToJSON.
+type ToJSONOptions struct {
+	// Indent, if not empty, is used to indent each level of the JSON
+	// output, which is otherwise compact.
+	Indent string
+	// OmitDescriptions omits the description of each node from the output.
+	OmitDescriptions bool
+}
+
+// IsToJSONOpt ensures that ToJSONOptions satisfies the ToJSONOpt interface.
+func (ToJSONOptions) IsToJSONOpt() {}
+
+// ToJSONOpt is an interface that can be used in function arguments.
+type ToJSONOpt interface {
+	IsToJSONOpt()
+}
+
+// ToJSON returns a JSON representation of the resolved schema tree rooted at
+// e. Unlike marshalling e with encoding/json, the representation includes the
+// resolved details of the types of leaves, such as their ranges, lengths,
+// patterns and enumeration values, and does not depend on the layout of the
+// Go types of this package. The output for a given schema is stable.
+//
+// Each node is represented by an object with the members:
+//
+//	name         the name of the node
+//	path         the path of the node, as returned by Path
+//	kind         one of "module", "container", "list", "leaf", "leaf-list",
+//	             "choice", "case", "anydata", "anyxml", "rpc", "input",
+//	             "output" or "notification"
+//	config       true if the node is configuration data, i.e., it is not
+//	             config false, and is not within an RPC or notification
+//	mandatory    true if the node is mandatory (omitted if false)
+//	description  the description of the node, if any
+//	units        the units of the node, if any
+//	default      the default values of the node, if any
+//	key          the names of the key leaves of a list
+//	min-elements the min-elements of a list or leaf-list, if not 0
+//	max-elements the max-elements of a list or leaf-list, if bounded
+//	ordered-by   "user" for user ordered lists and leaf-lists
+//	type         the type of a leaf or leaf-list
+//	children     the child nodes, sorted by name
+//
+// The children of an RPC are its input and output, if it defines them. A type is represented by
+// an object with the members:
+//
+//	name             the name of the type, e.g., the name of a typedef
+//	kind             the name of the built-in type, e.g., "uint32"
+//	range            the ranges of a numeric type, e.g., ["1..10", "20"]
+//	length           the lengths of a string or binary type
+//	patterns         the patterns of a string type
+//	invert-match     the patterns of patterns that have the modifier
+//	                 invert-match, which a value must not match
+//	posix-patterns   the POSIX patterns of a string type
+//	fraction-digits  the fraction-digits of a decimal64 type
+//	enum             the values of an enumeration, keyed by name
+//	bits             the positions of the bits of a bits type, keyed by name
+//	path             the path of a leafref
+//	require-instance false for a leafref or instance-identifier that does
+//	                 not require an instance (omitted otherwise)
+//	base             the name of the base identity of an identityref
+//	default          the default value of the type, if any
+//	units            the units of the type, if any
+//	members          the member types of a union
+//
+// Members whose value is empty are omitted. The parent of a node is
+// represented only by its path.
+func (e *Entry) ToJSON(opts ...ToJSONOpt) ([]byte, error) {
+	var o ToJSONOptions
+	for _, opt := range opts {
+		if jo, ok := opt.(ToJSONOptions); ok {
+			o = jo
+		}
+	}
+	n := jsonSchemaNode(e, o)
+	if o.Indent != "" {
+		return json.MarshalIndent(n, "", o.Indent)
+	}
+	return json.Marshal(n)
+}
+
+// A jsonNode is the JSON representation of an Entry used by ToJSON.
+type jsonNode struct {
+	Name        string      `json:"name"`
+	Path        string      `json:"path"`
+	Kind        string      `json:"kind"`
+	Config      bool        `json:"config"`
+	Mandatory   bool        `json:"mandatory,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Units       string      `json:"units,omitempty"`
+	Default     []string    `json:"default,omitempty"`
+	Key         []string    `json:"key,omitempty"`
+	MinElements uint64      `json:"min-elements,omitempty"`
+	MaxElements uint64      `json:"max-elements,omitempty"`
+	OrderedBy   string      `json:"ordered-by,omitempty"`
+	Type        *jsonType   `json:"type,omitempty"`
+	Children    []*jsonNode `json:"children,omitempty"`
+}
+
+// A jsonType is the JSON representation of a YangType used by ToJSON.
+type jsonType struct {
+	Name            string           `json:"name"`
+	Kind            string           `json:"kind"`
+	Range           []string         `json:"range,omitempty"`
+	Length          []string         `json:"length,omitempty"`
+	Patterns        []string         `json:"patterns,omitempty"`
+	InvertMatch     []string         `json:"invert-match,omitempty"`
+	POSIXPatterns   []string         `json:"posix-patterns,omitempty"`
+	FractionDigits  int              `json:"fraction-digits,omitempty"`
+	Enum            map[string]int64 `json:"enum,omitempty"`
+	Bits            map[string]int64 `json:"bits,omitempty"`
+	Path            string           `json:"path,omitempty"`
+	RequireInstance *bool            `json:"require-instance,omitempty"`
+	Base            string           `json:"base,omitempty"`
+	Default         string           `json:"default,omitempty"`
+	Units           string           `json:"units,omitempty"`
+	Members         []*jsonType      `json:"members,omitempty"`
+}
+
+// jsonSchemaKind returns the kind of e as represented by ToJSON.
+func jsonSchemaKind(e *Entry) string {
+	switch {
+	case e.Parent == nil:
+		return "module"
+	case e.RPC != nil:
+		return "rpc"
+	case e.IsList():
+		return "list"
+	case e.IsLeafList():
+		return "leaf-list"
+	}
+	switch e.Kind {
+	case LeafEntry:
+		return "leaf"
+	case DirectoryEntry:
+		return "container"
+	case AnyDataEntry:
+		return "anydata"
+	case AnyXMLEntry:
+		return "anyxml"
+	case CaseEntry:
+		return "case"
+	case ChoiceEntry:
+		return "choice"
+	case InputEntry:
+		return "input"
+	case OutputEntry:
+		return "output"
+	case NotificationEntry:
+		return "notification"
+	}
+	return strings.ToLower(e.Kind.String())
+}
+
+// jsonSchemaNode returns the JSON representation of e and its descendants.
+func jsonSchemaNode(e *Entry, o ToJSONOptions) *jsonNode {
+	n := &jsonNode{
+		Name:      e.Name,
+		Path:      e.Path(),
+		Kind:      jsonSchemaKind(e),
+		Config:    e.isConfigData(),
+		Mandatory: e.Mandatory == TSTrue,
+		Units:     e.Units,
+		Default:   e.Default,
+		Type:      jsonSchemaType(e.Type),
+	}
+	if !o.OmitDescriptions {
+		n.Description = e.Description
+	}
+	if e.IsList() {
+		n.Key = strings.Fields(e.Key)
+	}
+	if la := e.ListAttr; la != nil {
+		n.MinElements = la.MinElements
+		if la.MaxElements != math.MaxUint64 {
+			n.MaxElements = la.MaxElements
+		}
+		if la.OrderedByUser {
+			n.OrderedBy = "user"
+		}
+	}
+
+	var names []string
+	for name := range e.Dir {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		n.Children = append(n.Children, jsonSchemaNode(e.Dir[name], o))
+	}
+	for _, ce := range e.definedOperationChildren() {
+		n.Children = append(n.Children, jsonSchemaNode(ce, o))
+	}
+	return n
+}
+
+// jsonSchemaType returns the JSON representation of y, or nil if y is nil.
+func jsonSchemaType(y *YangType) *jsonType {
+	if y == nil {
+		return nil
+	}
+	t := &jsonType{
+		Name:          y.Name,
+		Kind:          y.Kind.String(),
+		Patterns:      y.Pattern,
+		POSIXPatterns: y.POSIXPattern,
+		Path:          y.Path,
+		Default:       y.Default,
+		Units:         y.Units,
+	}
+	for _, p := range y.Pattern {
+		if y.PatternInverted(p) {
+			t.InvertMatch = append(t.InvertMatch, p)
+		}
+	}
+	switch {
+	case isIntegerKind(y.Kind), y.Kind == Ydecimal64:
+		for _, r := range y.Range {
+			t.Range = append(t.Range, r.String())
+		}
+	case y.Kind == Ystring, y.Kind == Ybinary:
+		for _, r := range y.Length {
+			t.Length = append(t.Length, r.String())
+		}
+	}
+	switch y.Kind {
+	case Ydecimal64:
+		t.FractionDigits = y.FractionDigits
+	case Yenum:
+		if y.Enum != nil {
+			t.Enum = y.Enum.NameMap()
+		}
+	case Ybits:
+		if y.Bit != nil {
+			t.Bits = y.Bit.NameMap()
+		}
+	case Yleafref, YinstanceIdentifier:
+		if y.OptionalInstance {
+			f := false
+			t.RequireInstance = &f
+		}
+	case Yidentityref:
+		if y.IdentityBase != nil {
+			t.Base = y.IdentityBase.Name
+		}
+	case Yunion:
+		for _, m := range y.Type {
+			t.Members = append(t.Members, jsonSchemaType(m))
+		}
+	}
+	return t
+}
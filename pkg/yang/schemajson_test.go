@@ -0,0 +1,77 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestToJSON(t *testing.T) {
+	ms := NewModules()
+	if err := ms.Read("testdata/schemajson.yang"); err != nil {
+		t.Fatalf("cannot read module: %v", err)
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process module: %v", errs)
+	}
+	root := ToEntry(ms.Modules["schemajson"])
+
+	tests := []struct {
+		desc     string
+		in       *Entry
+		inOpts   []ToJSONOpt
+		wantFile string
+		want     string
+	}{{
+		desc:     "module",
+		in:       root,
+		inOpts:   []ToJSONOpt{ToJSONOptions{Indent: "  "}},
+		wantFile: "testdata/schemajson.json",
+	}, {
+		desc:   "compact leaf without description",
+		in:     root.Dir["system"].Dir["load"],
+		inOpts: []ToJSONOpt{ToJSONOptions{OmitDescriptions: true}},
+		want: `{"name":"load","path":"/schemajson/system/load","kind":"leaf","config":false,` +
+			`"type":{"name":"percent","kind":"uint8","range":["0..100"],"units":"percent"}}`,
+	}, {
+		desc: "container without description",
+		in:   root.Dir["system"].Dir["user"],
+		want: `{"name":"user","path":"/schemajson/system/user","kind":"list","config":true,"key":["name"],"min-elements":1,` +
+			`"children":[{"name":"name","path":"/schemajson/system/user/name","kind":"leaf","config":true,"type":{"name":"string","kind":"string"}}]}`,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := tt.in.ToJSON(tt.inOpts...)
+			if err != nil {
+				t.Fatalf("ToJSON: %v", err)
+			}
+			want := tt.want
+			if tt.wantFile != "" {
+				b, err := ioutil.ReadFile(tt.wantFile)
+				if err != nil {
+					t.Fatalf("cannot read golden file: %v", err)
+				}
+				want = string(b)
+			}
+			if diff := cmp.Diff(want, string(got)); diff != "" {
+				t.Errorf("ToJSON (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+// This file implements the generation of skeleton instance data for an
+// Entry tree.
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// SkeletonJSON returns a minimal RFC7951 JSON document showing the shape of
+// the configuration data described by the subtree rooted at e.  Containers
+// are rendered as objects, lists as arrays holding a single entry, and leaves
+// and leaf-lists with their default value, or a placeholder appropriate to
+// their type when they have no default.  Only the default case (or, if there
+// is no default, the first case in the source) of a choice is rendered.
+// Nodes that are not configuration data, such as config false nodes, RPCs
+// and notifications, are omitted.
+//
+// If e is a module then the returned object contains the top-level nodes of
+// the module, otherwise it contains a single member for e.
+func (e *Entry) SkeletonJSON() ([]byte, error) {
+	if e == nil {
+		return nil, fmt.Errorf("nil entry")
+	}
+	if e.Parent == nil {
+		obj := map[string]interface{}{}
+		skeletonChildren(e, obj)
+		return json.Marshal(obj)
+	}
+	v, ok := skeletonValue(e)
+	if !ok {
+		return nil, fmt.Errorf("%s: not a configuration data node", e.Path())
+	}
+	return json.Marshal(map[string]interface{}{e.JSONName(): v})
+}
+
+// skeletonChildren adds the skeleton of each configuration data node child
+// of e to obj, descending into the selected case of any choice.
+func skeletonChildren(e *Entry, obj map[string]interface{}) {
+	for _, ce := range e.Dir {
+		switch {
+		case ce.IsChoice():
+			if c := skeletonCase(ce); c != nil {
+				skeletonChildren(c, obj)
+			}
+		case ce.IsCase():
+			skeletonChildren(ce, obj)
+		default:
+			if v, ok := skeletonValue(ce); ok {
+				obj[ce.JSONName()] = v
+			}
+		}
+	}
+}
+
+// skeletonCase returns the case of choice e that is rendered in a skeleton:
+// its default case, if it has one, and otherwise the first case in the YANG
+// source.
+func skeletonCase(e *Entry) *Entry {
+	if len(e.Default) > 0 {
+		if c := e.Dir[e.Default[0]]; c != nil {
+			return c
+		}
+	}
+	names := e.OrderedChildNames()
+	if len(names) == 0 {
+		return nil
+	}
+	return e.Dir[names[0]]
+}
+
+// skeletonValue returns the skeleton value for e and whether e is a
+// configuration data node that should be rendered.
+func skeletonValue(e *Entry) (interface{}, bool) {
+	switch {
+	case e.RPC != nil, e.Kind == NotificationEntry, e.Kind == InputEntry, e.Kind == OutputEntry:
+		return nil, false
+	case e.ReadOnly():
+		return nil, false
+	case e.Kind == AnyDataEntry, e.Kind == AnyXMLEntry:
+		return map[string]interface{}{}, true
+	case e.IsList():
+		obj := map[string]interface{}{}
+		skeletonChildren(e, obj)
+		return []interface{}{obj}, true
+	case e.IsDir():
+		obj := map[string]interface{}{}
+		skeletonChildren(e, obj)
+		return obj, true
+	case e.IsLeafList():
+		dvals := e.DefaultValues()
+		if len(dvals) == 0 {
+			return []interface{}{skeletonLeafValue(e.Type, "")}, true
+		}
+		var vals []interface{}
+		for _, d := range dvals {
+			vals = append(vals, skeletonLeafValue(e.Type, d))
+		}
+		return vals, true
+	default:
+		d, _ := e.SingleDefaultValue()
+		return skeletonLeafValue(e.Type, d), true
+	}
+}
+
+// skeletonLeafValue returns the RFC7951 representation of the value v of
+// type t, or of a placeholder value for t if v is empty.
+func skeletonLeafValue(t *YangType, v string) interface{} {
+	if t == nil {
+		return v
+	}
+	switch t.Kind {
+	case Yint8, Yint16, Yint32:
+		if v == "" {
+			return 0
+		}
+		if i, err := strconv.ParseInt(v, 0, 64); err == nil {
+			return i
+		}
+	case Yuint8, Yuint16, Yuint32:
+		if v == "" {
+			return 0
+		}
+		if u, err := strconv.ParseUint(v, 0, 64); err == nil {
+			return u
+		}
+	case Yint64, Yuint64, Ydecimal64:
+		// RFC7951 section 6.1: 64-bit numbers are encoded as strings.
+		if v == "" {
+			return "0"
+		}
+	case Ybool:
+		if v == "" {
+			return false
+		}
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	case Yempty:
+		return []interface{}{nil}
+	case Yenum:
+		if v == "" && t.Enum != nil {
+			if vals := t.Enum.Values(); len(vals) > 0 {
+				return t.Enum.Name(vals[0])
+			}
+		}
+	case Yunion:
+		if v == "" && len(t.Type) > 0 {
+			return skeletonLeafValue(t.Type[0], "")
+		}
+	}
+	return v
+}
@@ -0,0 +1,153 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+)
+
+func TestSkeletonJSON(t *testing.T) {
+	modules := map[string]string{
+		"skel.yang": `
+			module skel {
+				prefix "s";
+				namespace "urn:s";
+
+				typedef port { type uint16; default 22; }
+
+				container top {
+					leaf name { type string; }
+					leaf enabled { type boolean; default true; }
+					leaf counter { type uint64; }
+					leaf port { type port; }
+					leaf mode { type enumeration { enum slow { value 2; } enum fast { value 1; } } }
+					leaf-list tags { type string; }
+					leaf flag { type empty; }
+
+					list server {
+						key "address";
+						leaf address { type string; }
+						leaf weight { type union { type int8; type string; } }
+					}
+
+					choice transport {
+						default udp;
+						case tcp { leaf tcp-port { type uint16; } }
+						case udp { leaf udp-port { type uint16; default 514; } }
+					}
+
+					// Without a default, the first case in the source,
+					// rather than by name, is rendered.
+					choice auth {
+						case token { leaf token-id { type string; } }
+						case basic { leaf user-name { type string; } }
+					}
+
+					container state {
+						config false;
+						leaf uptime { type uint32; }
+					}
+				}
+
+				rpc reset { input { leaf force { type boolean; } } }
+			}`,
+		"skel-aug.yang": `
+			module skel-aug {
+				prefix "a";
+				namespace "urn:a";
+				import skel { prefix "s"; }
+
+				augment "/s:top" {
+					leaf extra { type int32; }
+				}
+			}`,
+	}
+
+	ms := NewModules()
+	for n, m := range modules {
+		if err := ms.Parse(m, n); err != nil {
+			t.Fatalf("cannot parse module %s, err: %v", n, err)
+		}
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process modules, errs: %v", errs)
+	}
+	mod, errs := ms.GetModule("skel")
+	if errs != nil {
+		t.Fatalf("cannot get module skel, errs: %v", errs)
+	}
+
+	wantTop := map[string]interface{}{
+		"name":           "",
+		"enabled":        true,
+		"counter":        "0",
+		"port":           float64(22),
+		"mode":           "fast",
+		"tags":           []interface{}{""},
+		"flag":           []interface{}{nil},
+		"server":         []interface{}{map[string]interface{}{"address": "", "weight": float64(0)}},
+		"udp-port":       float64(514),
+		"token-id":       "",
+		"skel-aug:extra": float64(0),
+	}
+
+	tests := []struct {
+		desc          string
+		inEntry       *Entry
+		want          map[string]interface{}
+		wantErrSubstr string
+	}{{
+		desc:    "module",
+		inEntry: mod,
+		want:    map[string]interface{}{"skel:top": wantTop},
+	}, {
+		desc:    "container",
+		inEntry: mod.Dir["top"],
+		want:    map[string]interface{}{"skel:top": wantTop},
+	}, {
+		desc:    "list",
+		inEntry: mod.Dir["top"].Dir["server"],
+		want: map[string]interface{}{
+			"server": []interface{}{map[string]interface{}{"address": "", "weight": float64(0)}},
+		},
+	}, {
+		desc:          "state container",
+		inEntry:       mod.Dir["top"].Dir["state"],
+		wantErrSubstr: "not a configuration data node",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			b, err := tt.inEntry.SkeletonJSON()
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Fatal(diff)
+			}
+			if err != nil {
+				return
+			}
+			var got map[string]interface{}
+			if err := json.Unmarshal(b, &got); err != nil {
+				t.Fatalf("cannot unmarshal skeleton %s, err: %v", b, err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("SkeletonJSON (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+// This file implements the gathering of statistics about the modules within
+// a Modules.
+
+// SchemaStats holds counts of the modules, schema nodes and definitions
+// within a Modules, as returned by Stats.
+type SchemaStats struct {
+	Modules    int
+	Submodules int
+
+	// The counts of schema nodes are taken from the Entry trees of the
+	// modules, so they include the nodes added by augments and those
+	// instantiated from groupings, at each place they are used.
+	Containers    int
+	Lists         int
+	Leaves        int
+	LeafLists     int
+	Choices       int
+	RPCs          int
+	Actions       int
+	Notifications int
+
+	// The counts of definitions are taken from the statements of the
+	// modules and submodules, so each is counted once, where it is defined.
+	Typedefs   int
+	Groupings  int
+	Identities int
+	Features   int
+	Deviations int
+	Augments   int
+}
+
+// Stats returns counts of the modules, submodules, schema nodes and
+// definitions within ms. Each module or submodule is counted once, however
+// many revisions of its name it is found by. Stats should be called after
+// Process.
+func (ms *Modules) Stats() SchemaStats {
+	var st SchemaStats
+	seen := map[*Module]bool{}
+	for _, m := range ms.Modules {
+		if seen[m] {
+			continue
+		}
+		seen[m] = true
+		st.Modules++
+		st.addStatements(m.Source)
+		st.addEntries(ToEntry(m))
+	}
+	for _, m := range ms.SubModules {
+		if seen[m] {
+			continue
+		}
+		seen[m] = true
+		st.Submodules++
+		st.addStatements(m.Source)
+	}
+	return st
+}
+
+// addStatements adds the definitions within s and its substatements to st.
+func (st *SchemaStats) addStatements(s *Statement) {
+	if s == nil {
+		return
+	}
+	switch s.Keyword {
+	case "typedef":
+		st.Typedefs++
+	case "grouping":
+		st.Groupings++
+	case "identity":
+		st.Identities++
+	case "feature":
+		st.Features++
+	case "deviation":
+		st.Deviations++
+	case "augment":
+		st.Augments++
+	}
+	for _, ss := range s.SubStatements() {
+		st.addStatements(ss)
+	}
+}
+
+// addEntries adds the schema nodes that are descendants of e to st.
+func (st *SchemaStats) addEntries(e *Entry) {
+	for _, ce := range e.Dir {
+		switch {
+		case ce.RPC != nil:
+			if ce.Parent != nil && ce.Parent.Parent == nil {
+				st.RPCs++
+			} else {
+				st.Actions++
+			}
+			for _, io := range []*Entry{ce.RPC.Input, ce.RPC.Output} {
+				if io != nil {
+					st.addEntries(io)
+				}
+			}
+			continue
+		case ce.Kind == NotificationEntry:
+			st.Notifications++
+		case ce.IsList():
+			st.Lists++
+		case ce.IsLeafList():
+			st.LeafLists++
+		case ce.IsLeaf():
+			st.Leaves++
+		case ce.IsChoice():
+			st.Choices++
+		case ce.IsContainer():
+			st.Containers++
+		}
+		st.addEntries(ce)
+	}
+}
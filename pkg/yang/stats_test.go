@@ -0,0 +1,115 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestStats(t *testing.T) {
+	ms := NewModules()
+	for name, src := range map[string]string{
+		"base.yang": `
+			module base {
+				yang-version 1.1;
+				prefix "b";
+				namespace "urn:base";
+				include base-sub;
+				revision 2020-01-01;
+
+				feature f;
+				identity id;
+				identity derived { base id; }
+				typedef t { type string; }
+
+				grouping g {
+					leaf from-grouping { type t; }
+				}
+
+				container c {
+					uses g;
+					list l {
+						key "k";
+						leaf k { type string; }
+						action reset {
+							input { leaf delay { type uint8; } }
+						}
+					}
+					leaf-list ll { type string; }
+					choice ch {
+						case one { leaf a { type string; } }
+						leaf b { type string; }
+					}
+					notification changed {
+						leaf what { type string; }
+					}
+				}
+				uses g;
+				rpc r {
+					output { leaf out { type string; } }
+				}
+				notification n;
+			}`,
+		"base-sub.yang": `
+			submodule base-sub {
+				belongs-to base { prefix "b"; }
+				typedef sub-t { type int8; }
+				container sub-c;
+			}`,
+		"other.yang": `
+			module other {
+				prefix "o";
+				namespace "urn:other";
+				import base { prefix b; }
+				augment "/b:c" {
+					leaf added { type string; }
+				}
+				deviation "/b:c/b:ll" {
+					deviate add { max-elements 10; }
+				}
+			}`,
+	} {
+		if err := ms.Parse(src, name); err != nil {
+			t.Fatalf("cannot parse %s: %v", name, err)
+		}
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process modules: %v", errs)
+	}
+
+	want := SchemaStats{
+		Modules:       2,
+		Submodules:    1,
+		Containers:    2, // c and sub-c.
+		Lists:         1,
+		Leaves:        9, // Including 2 from-grouping, added, delay and out.
+		LeafLists:     1,
+		Choices:       1,
+		RPCs:          1,
+		Actions:       1,
+		Notifications: 2,
+		Typedefs:      2,
+		Groupings:     1,
+		Identities:    2,
+		Features:      1,
+		Deviations:    1,
+		Augments:      1,
+	}
+	if diff := cmp.Diff(want, ms.Stats()); diff != "" {
+		t.Errorf("Stats (-want, +got):\n%s", diff)
+	}
+}
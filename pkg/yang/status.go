@@ -0,0 +1,80 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+// This file implements the status of the entries of a schema.
+
+import "fmt"
+
+// A Status is the status of a definition, as given by the status statement
+// of RFC7950 section 7.21.2. The statuses are ordered from the least to the
+// most severe.
+type Status int
+
+// Enumeration of the statuses.
+const (
+	StatusCurrent = Status(iota)
+	StatusDeprecated
+	StatusObsolete
+)
+
+// StatusToName maps Status to their names, as used by the status statement.
+var StatusToName = map[Status]string{
+	StatusCurrent:    "current",
+	StatusDeprecated: "deprecated",
+	StatusObsolete:   "obsolete",
+}
+
+func (s Status) String() string {
+	if n := StatusToName[s]; n != "" {
+		return n
+	}
+	return fmt.Sprintf("unknown-status-%d", s)
+}
+
+// Status returns the status of e. If e has no status statement of its own,
+// and was not added by a uses statement with one, then e has the status of
+// its parent, so that a child of a deprecated container is deprecated. An
+// entry without a status, and without a parent, is current.
+func (e *Entry) Status() Status {
+	for ; e != nil; e = e.Parent {
+		if s, ok := e.ownStatus(); ok {
+			return s
+		}
+	}
+	return StatusCurrent
+}
+
+// ownStatus returns the most severe of the status statements recorded in
+// the Extra of e, which are those of e itself and of any uses statement that
+// added e, and true. It returns false if there are none.
+func (e *Entry) ownStatus() (Status, bool) {
+	status, found := StatusCurrent, false
+	for _, v := range e.Extra["status"] {
+		v, ok := v.(*Value)
+		if !ok {
+			continue
+		}
+		for s, name := range StatusToName {
+			if v.Name == name {
+				found = true
+				if s > status {
+					status = s
+				}
+			}
+		}
+	}
+	return status, found
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+import (
+	"testing"
+)
+
+func TestStatus(t *testing.T) {
+	ms := NewModules()
+	if err := ms.Parse(`
+		module test {
+			prefix "t";
+			namespace "urn:t";
+
+			grouping g {
+				leaf from-g { type string; }
+			}
+
+			leaf plain { type string; }
+			leaf explicit-current { type string; status current; }
+			leaf explicit-deprecated { type string; status deprecated; }
+			leaf explicit-obsolete { type string; status obsolete; }
+			container old {
+				status deprecated;
+				leaf inherited { type string; }
+				leaf overridden { type string; status obsolete; }
+				container deeper {
+					leaf inherited { type string; }
+				}
+				choice ch {
+					case a { leaf in-case { type string; } }
+				}
+			}
+			container c {
+				uses g { status deprecated; }
+			}
+			rpc r {
+				status obsolete;
+				input { leaf arg { type string; } }
+			}
+		}`, "test.yang"); err != nil {
+		t.Fatalf("cannot parse module: %v", err)
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process module: %v", errs)
+	}
+	root := ToEntry(ms.Modules["test"])
+	old := root.Dir["old"]
+
+	tests := []struct {
+		desc string
+		in   *Entry
+		want Status
+	}{
+		{"module", root, StatusCurrent},
+		{"no status", root.Dir["plain"], StatusCurrent},
+		{"explicit current", root.Dir["explicit-current"], StatusCurrent},
+		{"explicit deprecated", root.Dir["explicit-deprecated"], StatusDeprecated},
+		{"explicit obsolete", root.Dir["explicit-obsolete"], StatusObsolete},
+		{"deprecated container", old, StatusDeprecated},
+		{"inherited from the parent", old.Dir["inherited"], StatusDeprecated},
+		{"own status overrides the parent", old.Dir["overridden"], StatusObsolete},
+		{"inherited from an ancestor", old.Dir["deeper"].Dir["inherited"], StatusDeprecated},
+		{"inherited through a choice", old.Dir["ch"].Dir["a"].Dir["in-case"], StatusDeprecated},
+		{"set by a uses statement", root.Dir["c"].Dir["from-g"], StatusDeprecated},
+		{"container of a uses", root.Dir["c"], StatusCurrent},
+		{"inherited from an rpc", root.Dir["r"].RPC.Input.Dir["arg"], StatusObsolete},
+	}
+	for _, tt := range tests {
+		if got := tt.in.Status(); got != tt.want {
+			t.Errorf("%s: Status of %s: got %v, want %v", tt.desc, tt.in.Path(), got, tt.want)
+		}
+	}
+
+	for s, want := range map[Status]string{
+		StatusCurrent:    "current",
+		StatusDeprecated: "deprecated",
+		StatusObsolete:   "obsolete",
+		Status(42):       "unknown-status-42",
+	} {
+		if got := s.String(); got != want {
+			t.Errorf("Status(%d).String(): got %q, want %q", int(s), got, want)
+		}
+	}
+}
@@ -20,6 +20,8 @@ import (
 	"errors"
 	"fmt"
 	"regexp/syntax"
+	"sort"
+	"strings"
 	"sync"
 )
 
@@ -31,6 +33,9 @@ type typeDictionary struct {
 	dict map[Node]map[string]*Typedef
 	// identities contains a dictionary of resolved identities.
 	identities identityDictionary
+	// unknownStatement is the handler for unknown statements that is
+	// called when building ASTs, see Options.UnknownStatementHandler.
+	unknownStatement func(parent Node, s *Statement) error
 }
 
 func newTypeDictionary() *typeDictionary {
@@ -76,7 +81,8 @@ func (d *typeDictionary) findExternal(n Node, prefix, name string) (*Typedef, er
 	return nil, fmt.Errorf("%s: unknown type %s", Source(n), name)
 }
 
-// typedefs returns a slice of all typedefs in d.
+// typedefs returns a slice of all typedefs in d, ordered by their source
+// locations.
 func (d *typeDictionary) typedefs() []*Typedef {
 	var tds []*Typedef
 	defer d.mu.Unlock()
@@ -86,6 +92,15 @@ func (d *typeDictionary) typedefs() []*Typedef {
 			tds = append(tds, td)
 		}
 	}
+	// Typedefs are resolved in the order of their source locations so that
+	// the errors found resolving them are the same every time.
+	sort.Slice(tds, func(i, j int) bool {
+		si, sj := Source(tds[i]), Source(tds[j])
+		if si != sj {
+			return si < sj
+		}
+		return tds[i].Name < tds[j].Name
+	})
 	return tds
 }
 
@@ -122,7 +137,19 @@ func (t *Typedef) resolve(d *typeDictionary) []error {
 		return nil
 	}
 
+	if t.resolving {
+		return []error{&typedefCycleError{start: t, names: []string{t.Name}}}
+	}
+	t.resolving = true
+	defer func() { t.resolving = false }()
+
 	if errs := t.Type.resolve(d); len(errs) != 0 {
+		for _, err := range errs {
+			if ce, ok := err.(*typedefCycleError); ok && !ce.complete {
+				ce.names = append([]string{t.Name}, ce.names...)
+				ce.complete = ce.start == t
+			}
+		}
 		return errs
 	}
 
@@ -157,6 +184,20 @@ func (t *Typedef) resolve(d *typeDictionary) []error {
 	return nil
 }
 
+// A typedefCycleError is returned when resolving a typedef that is derived,
+// directly or through other typedefs, from itself. It is created when the
+// resolution of start reaches start again, and the names of the typedefs
+// between are added as the resolution unwinds back to start.
+type typedefCycleError struct {
+	start    *Typedef
+	names    []string
+	complete bool // complete is set once the names reach start.
+}
+
+func (e *typedefCycleError) Error() string {
+	return fmt.Sprintf("%s: circular typedef reference: %s", Source(e.start), strings.Join(e.names, " → "))
+}
+
 // resolve resolves Type t, as well as the underlying typedef for t.  If t
 // cannot be resolved then one or more errors are returned.
 func (t *Type) resolve(d *typeDictionary) (errs []error) {
@@ -228,21 +269,35 @@ check:
 	y.Base = td.Type
 	t.YangType = &y
 
+	// RFC7950 section 9.9.3 and 9.13.2: the require-instance statement is
+	// only valid for leafref and instance-identifier types, and if it is
+	// not present it defaults to true.
 	if v := t.RequireInstance; v != nil {
-		b, err := v.asBool()
-		if err != nil {
-			errs = append(errs, err)
+		switch y.Kind {
+		case Yleafref, YinstanceIdentifier:
+			b, err := v.asBool()
+			if err != nil {
+				errs = append(errs, err)
+			}
+			y.OptionalInstance = !b
+		default:
+			errs = append(errs, fmt.Errorf("%s: require-instance only allowed for leafref and instance-identifier values", Source(t)))
 		}
-		y.OptionalInstance = !b
 	}
 	if v := t.Path; v != nil {
 		y.Path = v.asString()
 	}
+	// Errors in fraction-digits are reported at the fraction-digits
+	// statement, when there is one.
+	fdSource := Source(t)
+	if t.FractionDigits != nil && t.FractionDigits.Source != nil {
+		fdSource = Source(t.FractionDigits)
+	}
 	isDecimal64 := y.Kind == Ydecimal64 && (t.Name == "decimal64" || y.FractionDigits != 0)
 	switch {
 	case isDecimal64 && y.FractionDigits != 0:
 		if t.FractionDigits != nil {
-			return append(errs, fmt.Errorf("%s: overriding of fraction-digits not allowed", Source(t)))
+			return append(errs, fmt.Errorf("%s: overriding of fraction-digits not allowed", fdSource))
 		}
 		// FractionDigits already set via type inheritance.
 	case isDecimal64:
@@ -250,7 +305,7 @@ check:
 		// fraction-digits in the range from 1-18.
 		i, err := t.FractionDigits.asRangeInt(1, 18)
 		if err != nil {
-			errs = append(errs, fmt.Errorf("%s: %v", Source(t), err))
+			errs = append(errs, fmt.Errorf("%s: %v", fdSource, err))
 		}
 		y.FractionDigits = int(i)
 		// We only know to how to populate Range after knowing the
@@ -260,7 +315,7 @@ check:
 			Number{Value: MaxInt64, FractionDigits: uint8(i)},
 		}}
 	case t.FractionDigits != nil:
-		errs = append(errs, fmt.Errorf("%s: fraction-digits only allowed for decimal64 values", Source(t)))
+		errs = append(errs, fmt.Errorf("%s: fraction-digits only allowed for decimal64 values", fdSource))
 	case y.Kind == Yidentityref:
 		if source != "builtin" {
 			// This is a typedef that refers to an identityref, so we want to simply
@@ -343,6 +398,11 @@ check:
 		}
 		y.Enum = enum
 	}
+	if source == "builtin" && y.Kind == Yenum && len(t.Enum) == 0 {
+		// RFC7950 section 9.6.4: the enum statement MUST be present
+		// if the type is "enumeration".
+		errs = append(errs, fmt.Errorf("%s: enumeration must have at least one enum", Source(t)))
+	}
 
 	if len(t.Bit) > 0 {
 		bit := NewBitfield()
@@ -374,6 +434,13 @@ check:
 			seenPatterns[pv.Name] = true
 			y.Pattern = append(y.Pattern, pv.Name)
 		}
+		if pv.Modifier != nil && pv.Modifier.Name == "invert-match" && !y.invertMatch[pv.Name] {
+			inverted := map[string]bool{pv.Name: true}
+			for p := range y.invertMatch {
+				inverted[p] = true
+			}
+			y.invertMatch = inverted
+		}
 	}
 
 	// Then, parse out the posix-pattern statements, if they exist.
@@ -409,6 +476,7 @@ looking:
 		if ut.YangType != nil {
 			for _, yt := range y.Type {
 				if ut.YangType.Equal(yt) {
+					y.unionDeduped = true
 					continue looking
 				}
 			}
@@ -102,6 +102,23 @@ func (n Number) String() string {
 	return out
 }
 
+// Float64 returns n as a float64, rounded to the nearest float64 if n cannot
+// be represented exactly. It returns an error if n is a decimal number that is
+// not a valid decimal64 value, i.e., it has more than 18 fractional digits or
+// is outside the range of a signed 64-bit integer when scaled by its
+// fractional digits.
+func (n Number) Float64() (float64, error) {
+	if n.IsDecimal() {
+		if n.FractionDigits > MaxFractionDigits {
+			return 0, fmt.Errorf("%d fraction digits is more than the maximum of %d", n.FractionDigits, MaxFractionDigits)
+		}
+		if n.Value > MaxInt64 && !(n.Negative && n.Value == AbsMinInt64) {
+			return 0, errors.New("decimal64 overflow")
+		}
+	}
+	return strconv.ParseFloat(n.String(), 64)
+}
+
 // Int returns n as an int64. It returns an error if n overflows an int64 or
 // the number is decimal.
 func (n Number) Int() (int64, error) {
@@ -314,13 +331,15 @@ func FromUint(i uint64) Number {
 // outside the boundaries specified for the decimal64 value specified in
 // RFC6020/RFC7950 are clamped down to the closest boundary value.
 func FromFloat(f float64) Number {
-	if f > MaxDecimal64 {
+	// MinDecimal64 and MaxDecimal64 are rounded to the nearest float64,
+	// which is outside the range of decimal64 values.
+	if f >= MaxDecimal64 {
 		return Number{
 			Value:          FromInt(MaxInt64).Value,
 			FractionDigits: 1,
 		}
 	}
-	if f < MinDecimal64 {
+	if f <= MinDecimal64 {
 		return Number{
 			Negative:       true,
 			Value:          FromInt(MaxInt64).Value,
@@ -328,20 +347,23 @@ func FromFloat(f float64) Number {
 		}
 	}
 
-	// Per RFC7950/6020, fraction-digits must be at least 1.
-	fracDig := uint8(1)
-	f *= 10.0
-	for ; Frac(f) != 0.0 && fracDig <= MaxFractionDigits; fracDig++ {
-		f *= 10.0
+	// The shortest decimal representation of f is used, so that Float64
+	// returns f. Per RFC7950/6020, fraction-digits must be at least 1, and
+	// f is rounded if it needs more than the maximum.
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	fracDig := 1
+	if dx := strings.Index(s, "."); dx >= 0 {
+		fracDig = len(s) - 1 - dx
 	}
-	negative := false
-	if f < 0 {
-		negative = true
-		f = -f
+	if fracDig > int(MaxFractionDigits) {
+		fracDig = int(MaxFractionDigits)
+		s = strconv.FormatFloat(f, 'f', fracDig, 64)
 	}
-	v := uint64(f)
-
-	return Number{Negative: negative, Value: v, FractionDigits: fracDig}
+	// f is within the range of decimal64 values, and its shortest
+	// representation has at most 17 significant digits, so s is a valid
+	// decimal64 value.
+	n, _ := decimalValueFromString(s, uint8(fracDig))
+	return n
 }
 
 // ParseInt returns s as a Number with FractionDigits=0.
@@ -384,6 +406,37 @@ func ParseDecimal(s string, fracDigRequired uint8) (n Number, err error) {
 	return decimalValueFromString(s, fracDigRequired)
 }
 
+// ParseBoundNumber returns s, a value of a range or length argument, as a
+// Number within the bounds min and max, inclusive. The keywords "min" and
+// "max" are resolved to min and max. Otherwise s is parsed by ParseDecimal,
+// with the fraction-digits of min, if min has a non-zero FractionDigits, or
+// by ParseInt if not. An error is returned if s does not parse, or is outside
+// the bounds, or if max is less than min.
+func ParseBoundNumber(s string, min, max Number) (Number, error) {
+	if max.Less(min) {
+		return Number{}, fmt.Errorf("bounds out of order (%s less than %s)", max, min)
+	}
+	var n Number
+	var err error
+	switch s = strings.TrimSpace(s); {
+	case s == "min":
+		return min, nil
+	case s == "max":
+		return max, nil
+	case min.FractionDigits > 0:
+		n, err = ParseDecimal(s, min.FractionDigits)
+	default:
+		n, err = ParseInt(s)
+	}
+	if err != nil {
+		return Number{}, err
+	}
+	if n.Less(min) || max.Less(n) {
+		return Number{}, fmt.Errorf("%s is outside the bounds %s..%s", s, min, max)
+	}
+	return n, nil
+}
+
 // decimalValueFromString returns a decimal Number representation of numStr.
 // fracDigRequired is used to set the number of fractional digits, which must
 // be at least the greatest precision seen in numStr.
@@ -404,7 +457,7 @@ func decimalValueFromString(numStr string, fracDigRequired uint8) (n Number, err
 	}
 
 	if fracDig > fracDigRequired {
-		return n, fmt.Errorf("%s has too much precision, expect <= %d fractional digits", s, fracDigRequired)
+		return n, fmt.Errorf("%s has too much precision, expect <= %d fractional digits", numStr, fracDigRequired)
 	}
 
 	s += space18[:fracDigRequired-fracDig]
@@ -571,7 +624,8 @@ type EnumType struct {
 	last     int64            // maximum value assigned thus far
 	min      int64            // minimum value allowed
 	max      int64            // maximum value allowed
-	unique   bool             // numeric values must be unique (enums)
+	unique   bool             // numeric values must be unique
+	bitfield bool             // values are bit positions rather than enum values
 	ToString map[int64]string `json:",omitempty"` // map of enum entries by value (integer)
 	ToInt    map[string]int64 `json:",omitempty"` // map of enum entries by name (string)
 }
@@ -588,36 +642,42 @@ func NewEnumType() *EnumType {
 	}
 }
 
-// NewBitfield returns an EnumType initialized as a bitfield.  Multiple string
-// values may map to the same numeric values.  Numeric values must be small
-// non-negative integers.
+// NewBitfield returns an EnumType initialized as a bitfield.  Numeric values
+// are the positions of the bits, which must be unique non-negative integers
+// less than MaxBitfieldSize (RFC7950 section 9.7.4.2).
 func NewBitfield() *EnumType {
 	return &EnumType{
 		last:     -1, // +1 will start at 0
 		min:      0,
 		max:      MaxBitfieldSize - 1,
+		unique:   true,
+		bitfield: true,
 		ToString: map[int64]string{},
 		ToInt:    map[string]int64{},
 	}
 }
 
 // Set sets name in e to the provided value.  Set returns an error if the value
-// is invalid, name is already signed, or when used as an enum rather than a
-// bitfield, the value has previousl been used.  When two different names are
-// assigned to the same value, the conversion from value to name will result in
-// the most recently assigned name.
+// is invalid, name is already assigned, or the value has previously been used.
+// When e was not created by NewEnumType or NewBitfield and two different names
+// are assigned to the same value, the conversion from value to name will result
+// in the most recently assigned name.
 func (e *EnumType) Set(name string, value int64) error {
+	field, val := "field", "value"
+	if e.bitfield {
+		field, val = "bit", "position"
+	}
 	if _, ok := e.ToInt[name]; ok {
-		return fmt.Errorf("field %s already assigned", name)
+		return fmt.Errorf("%s %s already assigned", field, name)
 	}
 	if oname, ok := e.ToString[value]; e.unique && ok {
-		return fmt.Errorf("fields %s and %s conflict on value %d", name, oname, value)
+		return fmt.Errorf("%ss %s and %s conflict on %s %d", field, name, oname, val, value)
 	}
 	if value < e.min {
-		return fmt.Errorf("value %d for %s too small (minimum is %d)", value, name, e.min)
+		return fmt.Errorf("%s %d for %s too small (minimum is %d)", val, value, name, e.min)
 	}
 	if value > e.max {
-		return fmt.Errorf("value %d for %s too large (maximum is %d)", value, name, e.max)
+		return fmt.Errorf("%s %d for %s too large (maximum is %d)", val, value, name, e.max)
 	}
 	e.ToString[value] = name
 	e.ToInt[name] = value
@@ -630,6 +690,9 @@ func (e *EnumType) Set(name string, value int64) error {
 // SetNext sets the name in e using the next possible value that is greater than
 // all previous values.
 func (e *EnumType) SetNext(name string) error {
+	if e.bitfield && e.last == e.max {
+		return fmt.Errorf("bit %q must specify a position since previous bit is the maximum position allowed", name)
+	}
 	if e.last == MaxEnum {
 		return fmt.Errorf("enum %q must specify a value since previous enum is the maximum value allowed", name)
 	}
@@ -698,3 +761,14 @@ func (e *EnumType) ValueMap() map[int64]string {
 	}
 	return m
 }
+
+// key returns a string that is equal for two EnumTypes if, and only if, they
+// assign the same values to the same names. Each name is quoted, so a name
+// that contains a separator cannot be confused with two names.
+func (e *EnumType) key() string {
+	parts := make([]string, 0, len(e.ToInt))
+	for _, name := range e.Names() {
+		parts = append(parts, fmt.Sprintf("%q=%d", name, e.ToInt[name]))
+	}
+	return strings.Join(parts, ",")
+}
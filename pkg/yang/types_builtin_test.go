@@ -144,6 +144,95 @@ func TestNumberInt(t *testing.T) {
 	}
 }
 
+func TestNumberFloat64(t *testing.T) {
+	tests := []struct {
+		desc    string
+		in      Number
+		want    float64
+		wantErr bool
+	}{{
+		desc: "integer",
+		in:   FromInt(-42),
+		want: -42,
+	}, {
+		desc: "decimal",
+		in:   Number{Value: 1015, FractionDigits: 2},
+		want: 10.15,
+	}, {
+		desc: "negative decimal less than one",
+		in:   Number{Value: 5, FractionDigits: 3, Negative: true},
+		want: -0.005,
+	}, {
+		desc: "maximum decimal64",
+		in:   Number{Value: MaxInt64, FractionDigits: 1},
+		want: MaxDecimal64,
+	}, {
+		desc: "minimum decimal64",
+		in:   Number{Value: AbsMinInt64, FractionDigits: 1, Negative: true},
+		want: MinDecimal64,
+	}, {
+		desc:    "decimal64 overflow",
+		in:      Number{Value: AbsMinInt64, FractionDigits: 1},
+		wantErr: true,
+	}, {
+		desc:    "too many fraction digits",
+		in:      Number{Value: 1, FractionDigits: 19},
+		wantErr: true,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := tt.in.Float64()
+			if got != tt.want {
+				t.Errorf("got: %v, want: %v", got, tt.want)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("gotErr: %v, wantErr: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNumberRoundTrip(t *testing.T) {
+	for _, f := range []float64{
+		0.1, 0.3, 1.1, 3.14, -2.5, 10.15, -10.15, 42, -0.0001, 1e-5, 1e-18,
+		123456.789, MaxDecimal64, MinDecimal64,
+	} {
+		n := FromFloat(f)
+		got, err := n.Float64()
+		if err != nil {
+			t.Errorf("FromFloat(%v).Float64(): %v", f, err)
+			continue
+		}
+		if got != f {
+			t.Errorf("FromFloat(%v).Float64(): got %v, want %v", f, got, f)
+		}
+	}
+
+	for _, n := range []Number{
+		{Value: 1015, FractionDigits: 2},
+		{Value: 1015, FractionDigits: 2, Negative: true},
+		{Value: 5, FractionDigits: 18},
+		{Value: 5, FractionDigits: 18, Negative: true},
+		{Value: 100, FractionDigits: 1},
+		{Value: MaxInt64, FractionDigits: 1},
+		{Value: AbsMinInt64, FractionDigits: 1, Negative: true},
+		{Value: MaxInt64, FractionDigits: 18},
+		FromFloat(1e-18),
+		FromFloat(MaxDecimal64),
+	} {
+		s := n.String()
+		got, err := ParseDecimal(s, n.FractionDigits)
+		if err != nil {
+			t.Errorf("ParseDecimal(%q, %d): %v", s, n.FractionDigits, err)
+			continue
+		}
+		if diff := cmp.Diff(n, got, cmp.Comparer(func(a, b Number) bool { return a == b })); diff != "" {
+			t.Errorf("ParseDecimal(%q, %d) (-want, +got):\n%s", s, n.FractionDigits, diff)
+		}
+	}
+}
+
 func TestRangeEqual(t *testing.T) {
 	tests := []struct {
 		desc        string
@@ -821,6 +910,118 @@ func TestParseDecimal(t *testing.T) {
 	}
 }
 
+func TestParseBoundNumber(t *testing.T) {
+	dec := func(v uint64, neg bool) Number {
+		return Number{Value: v, Negative: neg, FractionDigits: 2}
+	}
+	tests := []struct {
+		desc             string
+		in               string
+		inMin, inMax     Number
+		want             Number
+		wantErrSubstring string
+	}{{
+		desc:  "min",
+		in:    "min",
+		inMin: FromInt(-10),
+		inMax: FromInt(10),
+		want:  FromInt(-10),
+	}, {
+		desc:  "max",
+		in:    " max ",
+		inMin: FromInt(-10),
+		inMax: FromInt(10),
+		want:  FromInt(10),
+	}, {
+		desc:  "within bounds",
+		in:    "-3",
+		inMin: FromInt(-10),
+		inMax: FromInt(10),
+		want:  FromInt(-3),
+	}, {
+		desc:  "equal to upper bound",
+		in:    "255",
+		inMin: FromUint(0),
+		inMax: FromUint(255),
+		want:  FromUint(255),
+	}, {
+		desc:             "below lower bound",
+		in:               "-11",
+		inMin:            FromInt(-10),
+		inMax:            FromInt(10),
+		wantErrSubstring: "-11 is outside the bounds -10..10",
+	}, {
+		desc:             "above upper bound",
+		in:               "256",
+		inMin:            FromUint(0),
+		inMax:            FromUint(255),
+		wantErrSubstring: "256 is outside the bounds 0..255",
+	}, {
+		desc:             "plus sign only",
+		in:               "+",
+		inMin:            FromInt(-10),
+		inMax:            FromInt(10),
+		wantErrSubstring: "sign with no value",
+	}, {
+		desc:             "minus sign only",
+		in:               "-",
+		inMin:            FromInt(-10),
+		inMax:            FromInt(10),
+		wantErrSubstring: "sign with no value",
+	}, {
+		desc:             "not a number",
+		in:               "fish",
+		inMin:            FromInt(-10),
+		inMax:            FromInt(10),
+		wantErrSubstring: "fish",
+	}, {
+		desc:  "decimal min",
+		in:    "min",
+		inMin: dec(150, true),
+		inMax: dec(150, false),
+		want:  dec(150, true),
+	}, {
+		desc:  "decimal within bounds",
+		in:    "1.25",
+		inMin: dec(150, true),
+		inMax: dec(150, false),
+		want:  dec(125, false),
+	}, {
+		desc:             "decimal above upper bound",
+		in:               "1.51",
+		inMin:            dec(150, true),
+		inMax:            dec(150, false),
+		wantErrSubstring: "1.51 is outside the bounds -1.50..1.50",
+	}, {
+		desc:             "decimal with too much precision",
+		in:               "1.255",
+		inMin:            dec(150, true),
+		inMax:            dec(150, false),
+		wantErrSubstring: "has too much precision",
+	}, {
+		desc:             "bounds out of order",
+		in:               "min",
+		inMin:            FromInt(10),
+		inMax:            FromInt(-10),
+		wantErrSubstring: "bounds out of order",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := ParseBoundNumber(tt.in, tt.inMin, tt.inMax)
+			if diff := errdiff.Substring(err, tt.wantErrSubstring); diff != "" {
+				t.Fatalf("did not get expected error, %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("ParseBoundNumber(%q, %s, %s) (-want, +got):\n%s", tt.in, tt.inMin, tt.inMax, diff)
+			}
+		})
+	}
+}
+
 func TestNumberString(t *testing.T) {
 	tests := []struct {
 		desc string
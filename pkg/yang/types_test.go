@@ -16,6 +16,7 @@ package yang
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -180,6 +181,59 @@ func TestTypeResolve(t *testing.T) {
 			RequireInstance: &Value{Name: "foo"},
 		},
 		err: "invalid boolean: foo",
+	}, {
+		desc: "leafref with unspecified require-instance value (default true)",
+		in: &Type{
+			Name: "leafref",
+			Path: &Value{Name: "../foo"},
+		},
+		out: &YangType{
+			Name:             "leafref",
+			Kind:             Yleafref,
+			Path:             "../foo",
+			OptionalInstance: false,
+		},
+	}, {
+		desc: "leafref with true require-instance value",
+		in: &Type{
+			Name:            "leafref",
+			Path:            &Value{Name: "../foo"},
+			RequireInstance: &Value{Name: "true"},
+		},
+		out: &YangType{
+			Name:             "leafref",
+			Kind:             Yleafref,
+			Path:             "../foo",
+			OptionalInstance: false,
+		},
+	}, {
+		desc: "leafref with false require-instance value",
+		in: &Type{
+			Name:            "leafref",
+			Path:            &Value{Name: "../foo"},
+			RequireInstance: &Value{Name: "false"},
+		},
+		out: &YangType{
+			Name:             "leafref",
+			Kind:             Yleafref,
+			Path:             "../foo",
+			OptionalInstance: true,
+		},
+	}, {
+		desc: "leafref with invalid require-instance value",
+		in: &Type{
+			Name:            "leafref",
+			Path:            &Value{Name: "../foo"},
+			RequireInstance: &Value{Name: "foo"},
+		},
+		err: "invalid boolean: foo",
+	}, {
+		desc: "require-instance on a string",
+		in: &Type{
+			Name:            "string",
+			RequireInstance: &Value{Name: "false"},
+		},
+		err: "unknown: require-instance only allowed for leafref and instance-identifier values",
 	}, {
 		desc: "enum with unspecified values",
 		in: &Type{
@@ -336,6 +390,121 @@ func TestTypeResolve(t *testing.T) {
 			},
 		},
 		err: `unknown: strconv.ParseUint: parsing "five": invalid syntax`,
+	}, {
+		desc: "bits with unspecified positions",
+		in: &Type{
+			Name: "bits",
+			Bit: []*Bit{
+				{Name: "MERCURY"},
+				{Name: "VENUS"},
+				{Name: "EARTH"},
+			},
+		},
+		out: &YangType{
+			Name: "bits",
+			Kind: Ybits,
+			Bit: &EnumType{
+				last:     2,
+				min:      0,
+				max:      MaxBitfieldSize - 1,
+				unique:   true,
+				bitfield: true,
+				ToString: map[int64]string{
+					0: "MERCURY",
+					1: "VENUS",
+					2: "EARTH",
+				},
+				ToInt: map[string]int64{
+					"MERCURY": 0,
+					"VENUS":   1,
+					"EARTH":   2,
+				},
+			},
+		},
+	}, {
+		desc: "bits with repeated specified positions",
+		in: &Type{
+			Name: "bits",
+			Bit: []*Bit{
+				{Name: "MERCURY", Position: &Value{Name: "1"}},
+				{Name: "VENUS", Position: &Value{Name: "10"}},
+				{Name: "EARTH", Position: &Value{Name: "1"}},
+			},
+		},
+		err: "unknown: bits EARTH and MERCURY conflict on position 1",
+	}, {
+		desc: "bits with a repeated unspecified position",
+		in: &Type{
+			Name: "bits",
+			Bit: []*Bit{
+				{Name: "MERCURY", Position: &Value{Name: "1"}},
+				{Name: "VENUS", Position: &Value{Name: "0"}},
+				{Name: "EARTH"},
+				{Name: "MARS", Position: &Value{Name: "2"}},
+			},
+		},
+		err: "unknown: bits MARS and EARTH conflict on position 2",
+	}, {
+		desc: "bits with repeated specified names",
+		in: &Type{
+			Name: "bits",
+			Bit: []*Bit{
+				{Name: "MERCURY", Position: &Value{Name: "1"}},
+				{Name: "VENUS", Position: &Value{Name: "10"}},
+				{Name: "MERCURY", Position: &Value{Name: "30"}},
+			},
+		},
+		err: "unknown: bit MERCURY already assigned",
+	}, {
+		desc: "bits with last specified position equal to the max position",
+		in: &Type{
+			Name: "bits",
+			Bit: []*Bit{
+				{Name: "MERCURY", Position: &Value{Name: "0"}},
+				{Name: "VENUS", Position: &Value{Name: "4294967295"}},
+				{Name: "EARTH"},
+			},
+		},
+		err: `unknown: bit "EARTH" must specify a position since previous bit is the maximum position allowed`,
+	}, {
+		desc: "bit position too small",
+		in: &Type{
+			Name: "bits",
+			Bit: []*Bit{
+				{Name: "MERCURY", Position: &Value{Name: "-1"}},
+				{Name: "VENUS", Position: &Value{Name: "0"}},
+				{Name: "EARTH"},
+			},
+		},
+		err: `unknown: position -1 for MERCURY too small (minimum is 0)`,
+	}, {
+		desc: "bit position too large",
+		in: &Type{
+			Name: "bits",
+			Bit: []*Bit{
+				{Name: "MERCURY", Position: &Value{Name: "0"}},
+				{Name: "VENUS", Position: &Value{Name: "4294967296"}},
+				{Name: "EARTH"},
+			},
+		},
+		err: `unknown: position 4294967296 for VENUS too large (maximum is 4294967295)`,
+	}, {
+		desc: "bits with an unparseable position",
+		in: &Type{
+			Name: "bits",
+			Bit: []*Bit{
+				{Name: "MERCURY", Position: &Value{Name: "1"}},
+				{Name: "VENUS", Position: &Value{Name: "10"}},
+				{Name: "EARTH", Position: &Value{Name: "five"}},
+			},
+		},
+		err: `unknown: strconv.ParseUint: parsing "five": invalid syntax`,
+	}, {
+		desc: "enumeration without any enums",
+		in: &Type{
+			Name: "enumeration",
+		},
+		err: "unknown: enumeration must have at least one enum",
 		// TODO(borman): Add in more tests as we honor more fields
 		// in Type.
 	}}
@@ -452,6 +621,68 @@ func TestTypedefResolve(t *testing.T) {
 	}
 }
 
+func TestTypedefCycle(t *testing.T) {
+	tests := []struct {
+		desc    string
+		in      string
+		wantErr string // wantErr is a substring of one of the errors.
+	}{{
+		desc: "mutually referencing typedefs",
+		in: `module test {
+  prefix "t";
+  namespace "urn:t";
+  typedef a { type b; }
+  typedef b { type t:a; }
+  leaf l { type a; }
+}`,
+		wantErr: "test.yang:4:3: circular typedef reference: a → b → a",
+	}, {
+		desc: "self referencing typedef",
+		in: `module test {
+  prefix "t";
+  namespace "urn:t";
+  typedef a { type a; }
+}`,
+		wantErr: "test.yang:4:3: circular typedef reference: a → a",
+	}, {
+		desc: "cycle through a union",
+		in: `module test {
+  prefix "t";
+  namespace "urn:t";
+  typedef a {
+    type union {
+      type string;
+      type b;
+    }
+  }
+  typedef b { type c; }
+  typedef c { type a; }
+}`,
+		// The typedef at which the cycle is found depends on the order
+		// in which the typedefs are resolved.
+		wantErr: "circular typedef reference: ",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ms := NewModules()
+			if err := ms.Parse(tt.in, "test.yang"); err != nil {
+				t.Fatalf("cannot parse module: %v", err)
+			}
+			var got []string
+			for _, err := range ms.Process() {
+				got = append(got, err.Error())
+			}
+			for _, err := range got {
+				if strings.Contains(err, tt.wantErr) {
+					return
+				}
+			}
+			t.Errorf("Process: got errors %q, want %q", got, tt.wantErr)
+		})
+	}
+}
+
 func TestTypeResolveUnions(t *testing.T) {
 	tests := []struct {
 		desc          string
@@ -907,6 +1138,80 @@ func TestTypeResolveUnions(t *testing.T) {
 				}},
 			}},
 		},
+	}, {
+		desc: "empty enumeration typedef used as leaf-list type",
+		leafNode: `
+			typedef alpha {
+				type enumeration {}
+			}
+
+			leaf-list test-leaf {
+				type alpha;
+			}
+		} // end module`,
+		wantErrSubstr: "enumeration must have at least one enum",
+	}, {
+		desc: "union with empty enumeration",
+		leafNode: `
+			leaf test-leaf {
+				type union {
+					type string;
+					type enumeration {}
+				}
+			}
+		} // end module`,
+		wantErrSubstr: "enumeration must have at least one enum",
+	}, {
+		desc: "union with enumeration with conflicting values",
+		leafNode: `
+			leaf test-leaf {
+				type union {
+					type string;
+					type enumeration {
+						enum zero;
+						enum one {
+							value 0;
+						}
+					}
+				}
+			}
+		} // end module`,
+		wantErrSubstr: "test:12:7: fields one and zero conflict on value 0",
+	}, {
+		desc: "union with bits with conflicting positions",
+		leafNode: `
+			typedef alpha {
+				type bits {
+					bit zero;
+					bit one {
+						position 0;
+					}
+				}
+			}
+
+			leaf test-leaf {
+				type union {
+					type string;
+					type alpha;
+				}
+			}
+		} // end module`,
+		wantErrSubstr: "test:10:6: bits one and zero conflict on position 0",
+	}, {
+		desc: "union with bits with a position out of range",
+		leafNode: `
+			leaf test-leaf {
+				type union {
+					type string;
+					type bits {
+						bit big {
+							position 4294967296;
+						}
+					}
+				}
+			}
+		} // end module`,
+		wantErrSubstr: "position 4294967296 for big too large (maximum is 4294967295)",
 	}}
 
 	getTestLeaf := func(ms *Modules) (*YangType, error) {
@@ -1718,3 +2023,205 @@ func filterRanges(ytype *YangType) *testRangeTypeStruct {
 	}
 	return filteredType
 }
+
+func TestUnionMembersDeduped(t *testing.T) {
+	tests := []struct {
+		desc        string
+		inType      string
+		wantMembers int
+		wantDeduped bool
+	}{{
+		desc: "identical string members",
+		inType: `type union {
+				type string;
+				type string;
+			}`,
+		wantMembers: 1,
+		wantDeduped: true,
+	}, {
+		desc: "differing string members",
+		inType: `type union {
+				type string { pattern "a.*"; }
+				type string { pattern "b.*"; }
+			}`,
+		wantMembers: 2,
+	}, {
+		desc:        "typedef with identical members",
+		inType:      `type t:u;`,
+		wantMembers: 2,
+		wantDeduped: true,
+	}, {
+		desc:        "not a union",
+		inType:      `type string;`,
+		wantMembers: 0,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ms := NewModules()
+			if err := ms.Parse(`
+				module test {
+					prefix "t";
+					namespace "urn:t";
+					typedef u {
+						type union {
+							type int8;
+							type boolean;
+							type int8;
+						}
+					}
+					leaf l {
+						`+tt.inType+`
+					}
+				}`, "test.yang"); err != nil {
+				t.Fatalf("cannot parse module: %v", err)
+			}
+			if errs := ms.Process(); len(errs) > 0 {
+				t.Fatalf("cannot process module: %v", errs)
+			}
+			y := ToEntry(ms.Modules["test"]).Dir["l"].Type
+			if got := len(y.Type); got != tt.wantMembers {
+				t.Errorf("got %d union members, want %d", got, tt.wantMembers)
+			}
+			if got := y.UnionMembersDeduped(); got != tt.wantDeduped {
+				t.Errorf("UnionMembersDeduped() = %v, want %v", got, tt.wantDeduped)
+			}
+		})
+	}
+}
+
+func TestFractionDigits(t *testing.T) {
+	tests := []struct {
+		desc               string
+		inType             string
+		wantFractionDigits int
+		// wantRange, if set, is the range of the type of the leaf.
+		wantRange     string
+		wantErrSubstr string
+	}{{
+		desc: "minimum",
+		inType: `type decimal64 {
+				fraction-digits 1;
+			}`,
+		wantFractionDigits: 1,
+	}, {
+		desc: "maximum",
+		inType: `type decimal64 {
+				fraction-digits 18;
+			}`,
+		wantFractionDigits: 18,
+	}, {
+		desc:               "inherited from typedef",
+		inType:             `type t:d2;`,
+		wantFractionDigits: 2,
+	}, {
+		desc: "zero",
+		inType: `type decimal64 {
+				fraction-digits 0;
+			}`,
+		wantErrSubstr: "test.yang:8:5: value 0 out of range [1..18]",
+	}, {
+		desc: "too large",
+		inType: `type decimal64 {
+				fraction-digits 19;
+			}`,
+		wantErrSubstr: "test.yang:8:5: value 19 out of range [1..18]",
+	}, {
+		desc: "negative",
+		inType: `type decimal64 {
+				fraction-digits -1;
+			}`,
+		wantErrSubstr: "test.yang:8:5: value -1 out of range [1..18]",
+	}, {
+		desc: "not an integer",
+		inType: `type decimal64 {
+				fraction-digits 1.5;
+			}`,
+		wantErrSubstr: "test.yang:8:5: value 1.5 is not an integer in the range [1..18]",
+	}, {
+		desc:          "missing",
+		inType:        `type decimal64;`,
+		wantErrSubstr: "test.yang:7:4: value is required in the range of [1..18]",
+	}, {
+		desc: "not decimal64",
+		inType: `type int32 {
+				fraction-digits 2;
+			}`,
+		wantErrSubstr: "test.yang:8:5: fraction-digits only allowed for decimal64 values",
+	}, {
+		desc: "overriding typedef",
+		inType: `type t:d2 {
+				fraction-digits 3;
+			}`,
+		wantErrSubstr: "test.yang:8:5: overriding of fraction-digits not allowed",
+	}, {
+		desc:               "inherited through a chain of typedefs",
+		inType:             `type d2rr;`,
+		wantFractionDigits: 2,
+		wantRange:          "-10.00..10.00",
+	}, {
+		desc: "inherited through a chain of typedefs with a further range",
+		inType: `type d2rr {
+				range "-1.5..1.25";
+			}`,
+		wantFractionDigits: 2,
+		wantRange:          "-1.50..1.25",
+	}, {
+		desc: "overriding a chain of typedefs",
+		inType: `type d2rr {
+				fraction-digits 2;
+			}`,
+		wantErrSubstr: "test.yang:8:5: overriding of fraction-digits not allowed",
+	}, {
+		desc: "range more precise than a chain of typedefs",
+		inType: `type d2rr {
+				range "0..1.125";
+			}`,
+		wantErrSubstr: "test.yang:8:5: bad range: 1.125 has too much precision",
+	}, {
+		desc:      "not decimal64",
+		inType:    `type int32;`,
+		wantRange: "-2147483648..2147483647",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ms := NewModules()
+			if err := ms.Parse(`
+module test {
+prefix "t";
+namespace "urn:t";
+typedef d2 { type decimal64 { fraction-digits 2; } }
+leaf l {
+			`+tt.inType+`
+}
+typedef d2r { type d2 { range "-10..10"; } }
+typedef d2rr { type d2r; }
+}`, "test.yang"); err != nil {
+				t.Fatalf("cannot parse module: %v", err)
+			}
+			var err error
+			if errs := ms.Process(); len(errs) > 0 {
+				err = fmt.Errorf("%v", errs)
+			}
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Fatalf("Process: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			typ := ToEntry(ms.Modules["test"]).Dir["l"].Type
+			if got := typ.FractionDigits; got != tt.wantFractionDigits {
+				t.Errorf("got fraction-digits %d, want %d", got, tt.wantFractionDigits)
+			}
+			if got, ok := typ.Precision(); got != tt.wantFractionDigits || ok != (typ.Kind == Ydecimal64) {
+				t.Errorf("got Precision() %d, %t, want %d, %t", got, ok, tt.wantFractionDigits, typ.Kind == Ydecimal64)
+			}
+			if tt.wantRange != "" {
+				if got := typ.Range.String(); got != tt.wantRange {
+					t.Errorf("got range %s, want %s", got, tt.wantRange)
+				}
+			}
+		})
+	}
+}
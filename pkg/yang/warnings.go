@@ -0,0 +1,83 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+// This file implements the separation of the warnings found while processing
+// modules from the errors.
+
+import (
+	"fmt"
+)
+
+// An augmentNotFoundError is returned when the target of an augment cannot
+// be found.
+type augmentNotFoundError struct {
+	source string
+	target string
+	// conditional is set if the augment has an if-feature statement, so
+	// that the nodes it adds are optional.
+	conditional bool
+}
+
+func (e *augmentNotFoundError) Error() string {
+	return fmt.Sprintf("%s: augment %s not found", e.source, e.target)
+}
+
+// ProcessWithWarnings processes the modules of ms as Process does, but
+// returns the problems found as fatal errors and non-fatal warnings. The
+// errors are those returned by Process, less those classified as warnings,
+// so the schema can be used if errs is empty. The warnings are:
+//
+//   - an augment, with an if-feature statement, whose target cannot be
+//     found, which Process reports as an error. As the nodes it adds are
+//     conditional, the schema is usable without them, and they are not added.
+//   - a node whose status is deprecated or obsolete, whether set by its own
+//     status statement or by that of the uses statement that added it. The
+//     descendants of the node, which inherit its status, are not reported
+//     separately. These are not reported by Process.
+//
+// Each list is sorted, as are the errors returned by Process.
+func (ms *Modules) ProcessWithWarnings() (errs []error, warnings []error) {
+	for _, err := range ms.Process() {
+		if ae, ok := err.(*augmentNotFoundError); ok && ae.conditional {
+			warnings = append(warnings, err)
+			continue
+		}
+		errs = append(errs, err)
+	}
+
+	seen := map[*Entry]bool{}
+	var walk func(e *Entry)
+	walk = func(e *Entry) {
+		if e == nil || seen[e] {
+			return
+		}
+		seen[e] = true
+		if s, ok := e.ownStatus(); ok && e.Parent != nil && s != StatusCurrent {
+			warnings = append(warnings, fmt.Errorf("%s: %s is %s", Source(e.Node), e.Path(), s))
+		}
+		for _, ce := range e.Dir {
+			walk(ce)
+		}
+		if e.RPC != nil {
+			walk(e.RPC.Input)
+			walk(e.RPC.Output)
+		}
+	}
+	for _, name := range ms.SortedModuleNames() {
+		walk(ToEntry(ms.Modules[name]))
+	}
+	return errs, errorSort(warnings)
+}
@@ -0,0 +1,132 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProcessWithWarnings(t *testing.T) {
+	tests := []struct {
+		desc         string
+		in           string
+		wantErrs     []string
+		wantWarnings []string
+		// wantProcessErrs are the errors returned by Process.
+		wantProcessErrs []string
+	}{{
+		desc: "no problems",
+		in: `
+			module test {
+				prefix "t";
+				namespace "urn:t";
+				leaf l { type string; status current; }
+			}`,
+	}, {
+		desc: "deprecated and obsolete nodes",
+		in: `
+			module test {
+				prefix "t";
+				namespace "urn:t";
+				leaf old { type string; status deprecated; }
+				container gone {
+					status obsolete;
+					leaf inner { type string; }
+				}
+				rpc r {
+					input { leaf arg { type string; status deprecated; } }
+				}
+			}`,
+		wantWarnings: []string{
+			"test.yang:5:5: /test/old is deprecated",
+			"test.yang:6:5: /test/gone is obsolete",
+			"test.yang:11:14: /test/r/input/arg is deprecated",
+		},
+	}, {
+		desc: "deprecated by a uses statement",
+		in: `
+			module test {
+				prefix "t";
+				namespace "urn:t";
+				grouping g {
+					leaf from-g { type string; }
+					leaf obsolete-in-g { type string; status obsolete; }
+				}
+				container c {
+					uses g { status deprecated; }
+				}
+			}`,
+		wantWarnings: []string{
+			"test.yang:6:6: /test/c/from-g is deprecated",
+			"test.yang:7:6: /test/c/obsolete-in-g is obsolete",
+		},
+	}, {
+		desc: "augments that are not found",
+		in: `
+			module test {
+				prefix "t";
+				namespace "urn:t";
+				feature f;
+				container c { }
+				augment "/t:missing" {
+					if-feature f;
+					leaf optional { type string; }
+				}
+				augment "/t:also-missing" {
+					leaf required { type string; }
+				}
+			}`,
+		wantErrs:     []string{"test.yang:11:5: augment /t:also-missing not found"},
+		wantWarnings: []string{"test.yang:7:5: augment /t:missing not found"},
+		wantProcessErrs: []string{
+			"test.yang:7:5: augment /t:missing not found",
+			"test.yang:11:5: augment /t:also-missing not found",
+		},
+	}}
+
+	errStrings := func(errs []error) []string {
+		var s []string
+		for _, err := range errs {
+			s = append(s, err.Error())
+		}
+		return s
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			parse := func() *Modules {
+				ms := NewModules()
+				if err := ms.Parse(tt.in, "test.yang"); err != nil {
+					t.Fatalf("cannot parse module: %v", err)
+				}
+				return ms
+			}
+			errs, warnings := parse().ProcessWithWarnings()
+			if diff := cmp.Diff(tt.wantErrs, errStrings(errs)); diff != "" {
+				t.Errorf("ProcessWithWarnings errors (-want, +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantWarnings, errStrings(warnings)); diff != "" {
+				t.Errorf("ProcessWithWarnings warnings (-want, +got):\n%s", diff)
+			}
+			// Process is unchanged, reporting the warnings of augments
+			// as errors and not reporting deprecated nodes.
+			if diff := cmp.Diff(tt.wantProcessErrs, errStrings(parse().Process())); diff != "" {
+				t.Errorf("Process errors (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
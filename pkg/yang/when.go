@@ -0,0 +1,221 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+// This file implements the resolution of the nodes referenced by the XPath
+// expression of a when statement.
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// WhenDependencies returns the entries referenced by the location paths
+// within the when statement of e, such as "../condition", in the order in
+// which they first appear. Relative paths are resolved from the context node
+// of the when statement, which is e, or for a choice or case, its closest
+// ancestor data node. Choice and case entries are looked through as they are
+// not data nodes. Paths that cannot be resolved, such as those that use axes
+// other than the abbreviated child and parent steps, wildcards, or that name
+// nodes that do not exist, are skipped, as are the contents of predicates,
+// function names and literals. The function call current() is treated as
+// the context node.
+//
+// Nil is returned if e has no when statement. If the expression cannot be
+// parsed, an error is returned and also added to the errors of e.
+func (e *Entry) WhenDependencies() ([]*Entry, error) {
+	xpath, ok := e.GetWhenXPath()
+	if !ok {
+		return nil, nil
+	}
+	paths, err := xpathLocationPaths(xpath)
+	if err != nil {
+		err = fmt.Errorf("%s: cannot parse when statement %q: %v", Source(e.Node), xpath, err)
+		e.addError(err)
+		return nil, err
+	}
+
+	ctx := e
+	if e.IsChoice() || e.IsCase() {
+		ctx = e.dataParent()
+	}
+	var deps []*Entry
+	seen := map[*Entry]bool{}
+	for _, p := range paths {
+		if t := ctx.resolveXPathPath(e.Node, p); t != nil && !seen[t] {
+			seen[t] = true
+			deps = append(deps, t)
+		}
+	}
+	return deps, nil
+}
+
+// resolveXPathPath returns the entry referenced by the location path p,
+// relative to the context entry e, or nil if it cannot be resolved. The
+// prefixes within p are resolved within the module of n.
+func (e *Entry) resolveXPathPath(n Node, p string) *Entry {
+	if e == nil || n == nil || strings.Contains(p, "//") {
+		return nil
+	}
+	cur := e
+	if strings.HasPrefix(p, "/") {
+		p = p[1:]
+		pfx, _ := getPrefix(strings.SplitN(p, "/", 2)[0])
+		m := FindModuleByPrefix(n, pfx)
+		if m == nil || RootNode(n) == nil {
+			return nil
+		}
+		if m.BelongsTo != nil {
+			if m.Modules == nil {
+				return nil
+			}
+			if m = m.Modules.Modules[m.BelongsTo.Name]; m == nil {
+				return nil
+			}
+		}
+		cur = ToEntry(m)
+	}
+	for _, step := range strings.Split(p, "/") {
+		switch step {
+		case ".":
+		case "..":
+			if cur = cur.dataParent(); cur == nil {
+				return nil
+			}
+		default:
+			pfx, name := getPrefix(step)
+			if pfx != "" && FindModuleByPrefix(n, pfx) == nil {
+				return nil
+			}
+			if cur = cur.findDataChild(name); cur == nil {
+				return nil
+			}
+		}
+	}
+	return cur
+}
+
+// xpathLocationPaths returns the location paths within the XPath expression
+// s that consist only of abbreviated steps, e.g., "../a/b" or "/p:a", in the
+// order in which they appear, with any predicates removed. The path
+// "current()/x" is returned as "./x". Paths that use axes, wildcards or
+// attributes, and the contents of literals and predicates, are skipped. An
+// error is returned if s has an unterminated literal, or unbalanced brackets
+// or parentheses.
+func xpathLocationPaths(s string) ([]string, error) {
+	toks, err := xpathTokens(s)
+	if err != nil {
+		return nil, err
+	}
+	// skipPredicate returns the index of the token after the predicate
+	// that starts at the token i.
+	skipPredicate := func(i int) (int, error) {
+		depth := 0
+		for ; i < len(toks); i++ {
+			switch {
+			case toks[i].is("["):
+				depth++
+			case toks[i].is("]"):
+				if depth--; depth == 0 {
+					return i + 1, nil
+				}
+			}
+		}
+		return 0, errors.New("unbalanced brackets")
+	}
+	// inPath returns true if the token t continues a location path.
+	inPath := func(t xpathToken) bool {
+		if t.kind == xpathName {
+			return true
+		}
+		switch t.text {
+		case "/", "//", ".", "..", "*", "@", "::":
+			return t.kind == xpathOperator
+		}
+		return false
+	}
+	// scanPath returns the location path that starts at the token i, with
+	// any predicates removed, and the index of the token after it. The
+	// tokens of a path are not separated by whitespace.
+	scanPath := func(i int) (string, int, error) {
+		var b strings.Builder
+		for i < len(toks) && (b.Len() == 0 || toks[i].pos == toks[i-1].end()) {
+			switch t := toks[i]; {
+			case t.is("["):
+				var err error
+				if i, err = skipPredicate(i); err != nil {
+					return "", 0, err
+				}
+			case inPath(t):
+				b.WriteString(t.text)
+				i++
+			default:
+				return b.String(), i, nil
+			}
+		}
+		return b.String(), i, nil
+	}
+
+	var paths []string
+	parens := 0
+	for i := 0; i < len(toks); {
+		switch t := toks[i]; {
+		case t.is("["):
+			if i, err = skipPredicate(i); err != nil {
+				return nil, err
+			}
+		case t.is("]"):
+			return nil, errors.New("unbalanced brackets")
+		case t.is("("):
+			parens++
+			i++
+		case t.is(")"):
+			if parens--; parens < 0 {
+				return nil, errors.New("unbalanced parentheses")
+			}
+			i++
+		case t.kind == xpathName || t.is("/") || t.is("//") || t.is(".") || t.is(".."):
+			var p string
+			if p, i, err = scanPath(i); err != nil {
+				return nil, err
+			}
+			switch {
+			case i < len(toks) && toks[i].is("("):
+				// A function call. The path following current() is
+				// relative to the context node.
+				if p != "current" || i+2 >= len(toks) || !toks[i+1].is(")") || !toks[i+2].is("/") {
+					continue
+				}
+				if p, i, err = scanPath(i + 2); err != nil {
+					return nil, err
+				}
+				p = "." + p
+			case p == "and" || p == "or" || p == "div" || p == "mod":
+				continue
+			}
+			if strings.ContainsAny(p, "*@") || strings.Contains(p, "::") {
+				continue
+			}
+			paths = append(paths, p)
+		default:
+			i++
+		}
+	}
+	if parens != 0 {
+		return nil, errors.New("unbalanced parentheses")
+	}
+	return paths, nil
+}
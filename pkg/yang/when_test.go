@@ -0,0 +1,196 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+)
+
+func TestXPathLocationPaths(t *testing.T) {
+	tests := []struct {
+		in            string
+		want          []string
+		wantErrSubstr string
+	}{{
+		in:   "../condition = 'x'",
+		want: []string{"../condition"},
+	}, {
+		in:   "count(../items) > 0 and not(../t:flag)",
+		want: []string{"../items", "../t:flag"},
+	}, {
+		in:   "current()/../a != /t:top/t:mode",
+		want: []string{"./../a", "/t:top/t:mode"},
+	}, {
+		in:   "../l[name = current()/../x]/value = \"../not-a-path\"",
+		want: []string{"../l/value"},
+	}, {
+		in:   "../a div 2 = 1.5 or ../b mod 3 = 0",
+		want: []string{"../a", "../b"},
+	}, {
+		in:   "../* and ../@attr and child::a and ../c",
+		want: []string{"../c"},
+	}, {
+		in:   "true()",
+		want: nil,
+	}, {
+		in:            "../a = 'x",
+		wantErrSubstr: "unterminated literal",
+	}, {
+		in:            "../a[b = 1",
+		wantErrSubstr: "unbalanced brackets",
+	}, {
+		in:            "not(../a",
+		wantErrSubstr: "unbalanced parentheses",
+	}, {
+		in:            "../a)",
+		wantErrSubstr: "unbalanced parentheses",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := xpathLocationPaths(tt.in)
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Fatalf("xpathLocationPaths: %s", diff)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("xpathLocationPaths (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWhenDependencies(t *testing.T) {
+	ms := NewModules()
+	if err := ms.Parse(`
+		module test {
+			prefix "t";
+			namespace "urn:t";
+
+			container top {
+				leaf mode { type string; }
+				leaf flag { type boolean; }
+				list items {
+					key "name";
+					leaf name { type string; }
+					leaf value { type string; }
+				}
+
+				container simple {
+					when "../mode = 'simple'";
+				}
+				container functions {
+					when "count(../items) > 0 and not(../t:flag) and ../missing";
+				}
+				leaf current {
+					when "current()/../mode = /t:top/t:flag";
+					type string;
+				}
+				leaf predicate {
+					when "../items[name = current()/../mode]/value = 'x'";
+					type string;
+				}
+				choice ch {
+					when "mode = 'choice'";
+					case one {
+						leaf in-case {
+							when "../flag";
+							type string;
+						}
+					}
+				}
+				leaf literal {
+					when "'../mode' = 'x'";
+					type string;
+				}
+				leaf bad {
+					when "../mode = 'x";
+					type string;
+				}
+				leaf none { type string; }
+			}
+		}`, "test.yang"); err != nil {
+		t.Fatalf("cannot parse module: %v", err)
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process module: %v", errs)
+	}
+	top := ToEntry(ms.Modules["test"]).Dir["top"]
+
+	tests := []struct {
+		desc          string
+		in            *Entry
+		want          []string
+		wantErrSubstr string
+	}{{
+		desc: "simple",
+		in:   top.Dir["simple"],
+		want: []string{"/test/top/mode"},
+	}, {
+		desc: "function calls and unresolvable path",
+		in:   top.Dir["functions"],
+		want: []string{"/test/top/items", "/test/top/flag"},
+	}, {
+		desc: "current and absolute path",
+		in:   top.Dir["current"],
+		want: []string{"/test/top/mode", "/test/top/flag"},
+	}, {
+		desc: "predicate",
+		in:   top.Dir["predicate"],
+		want: []string{"/test/top/items/value"},
+	}, {
+		desc: "choice context is its parent",
+		in:   top.Dir["ch"],
+		want: []string{"/test/top/mode"},
+	}, {
+		desc: "leaf within case",
+		in:   top.Dir["ch"].Dir["one"].Dir["in-case"],
+		want: []string{"/test/top/flag"},
+	}, {
+		desc: "literal",
+		in:   top.Dir["literal"],
+	}, {
+		desc: "no when",
+		in:   top.Dir["none"],
+	}, {
+		desc:          "parse error",
+		in:            top.Dir["bad"],
+		wantErrSubstr: "unterminated literal",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			deps, err := tt.in.WhenDependencies()
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Fatalf("WhenDependencies: %s", diff)
+			}
+			if err != nil {
+				if len(tt.in.Errors) == 0 {
+					t.Errorf("error %v not added to the entry", err)
+				}
+				return
+			}
+			var got []string
+			for _, d := range deps {
+				got = append(got, d.Path())
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("WhenDependencies (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+// This file implements a lexer for the XPath expressions of the when, must
+// and path statements, which is shared by the code that inspects them.
+
+import (
+	"errors"
+)
+
+// An xpathTokenKind is the kind of an xpathToken.
+type xpathTokenKind int
+
+const (
+	// xpathName is a name, which may be prefixed, e.g., "a", "p:a" or
+	// "p:*". Axis names, operator names such as "and", and function names
+	// are also returned as names.
+	xpathName xpathTokenKind = iota
+	// xpathLiteral is a quoted string literal, including its quotes.
+	xpathLiteral
+	// xpathNumber is a number, e.g., "42" or "1.5".
+	xpathNumber
+	// xpathOperator is any other token, e.g., "/", "//", "..", "::",
+	// "[", "(", "!=" or "*".
+	xpathOperator
+)
+
+// An xpathToken is a single token of an XPath expression.
+type xpathToken struct {
+	kind xpathTokenKind
+	text string
+	pos  int // offset of the token within the expression
+}
+
+// end returns the offset within the expression of the byte after t.
+func (t xpathToken) end() int { return t.pos + len(t.text) }
+
+// is returns true if t is the operator op.
+func (t xpathToken) is(op string) bool { return t.kind == xpathOperator && t.text == op }
+
+// prefix returns the prefix of the name t, or "" if t is not a prefixed name.
+func (t xpathToken) prefix() string {
+	if t.kind != xpathName {
+		return ""
+	}
+	pfx, _ := getPrefix(t.text)
+	if pfx == t.text {
+		return ""
+	}
+	return pfx
+}
+
+// isXPathNameStart returns true if c may start a name.
+func isXPathNameStart(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
+
+// isXPathNameChar returns true if c may be within a name.
+func isXPathNameChar(c byte) bool {
+	return isXPathNameStart(c) || c == '-' || c == '.' || ('0' <= c && c <= '9')
+}
+
+// isXPathDigit returns true if c is a decimal digit.
+func isXPathDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// xpathTokens returns the tokens of the XPath expression s, skipping
+// whitespace. If s has an unterminated literal, the tokens that precede it are
+// returned with an error.
+func xpathTokens(s string) ([]xpathToken, error) {
+	var toks []xpathToken
+	add := func(kind xpathTokenKind, start, end int) {
+		toks = append(toks, xpathToken{kind: kind, text: s[start:end], pos: start})
+	}
+	for i := 0; i < len(s); {
+		start := i
+		switch c := s[i]; {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case c == '\'' || c == '"':
+			j := 1
+			for i+j < len(s) && s[i+j] != c {
+				j++
+			}
+			if i+j == len(s) {
+				return toks, errors.New("unterminated literal")
+			}
+			i += j + 1
+			add(xpathLiteral, start, i)
+		case isXPathDigit(c) || (c == '.' && i+1 < len(s) && isXPathDigit(s[i+1])):
+			for i < len(s) && (isXPathDigit(s[i]) || s[i] == '.') {
+				i++
+			}
+			add(xpathNumber, start, i)
+		case isXPathNameStart(c):
+			for i < len(s) && isXPathNameChar(s[i]) {
+				i++
+			}
+			// A single colon separates a prefix from a name, while a
+			// double colon follows an axis name.
+			if i+1 < len(s) && s[i] == ':' {
+				switch n := s[i+1]; {
+				case n == '*':
+					i += 2
+				case isXPathNameStart(n):
+					for i++; i < len(s) && isXPathNameChar(s[i]); i++ {
+					}
+				}
+			}
+			add(xpathName, start, i)
+		default:
+			i++
+			if i < len(s) {
+				switch s[start : i+1] {
+				case "//", "..", "::", "!=", "<=", ">=":
+					i++
+				}
+			}
+			add(xpathOperator, start, i)
+		}
+	}
+	return toks, nil
+}
@@ -0,0 +1,63 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+)
+
+func TestXPathTokens(t *testing.T) {
+	name := func(s string, pos int) xpathToken { return xpathToken{kind: xpathName, text: s, pos: pos} }
+	lit := func(s string, pos int) xpathToken { return xpathToken{kind: xpathLiteral, text: s, pos: pos} }
+	num := func(s string, pos int) xpathToken { return xpathToken{kind: xpathNumber, text: s, pos: pos} }
+	op := func(s string, pos int) xpathToken { return xpathToken{kind: xpathOperator, text: s, pos: pos} }
+
+	tests := []struct {
+		in            string
+		want          []xpathToken
+		wantErrSubstr string
+	}{{
+		in:   "../p:a/b",
+		want: []xpathToken{op("..", 0), op("/", 2), name("p:a", 3), op("/", 6), name("b", 7)},
+	}, {
+		in:   "child::a and p:*",
+		want: []xpathToken{name("child", 0), op("::", 5), name("a", 7), name("and", 9), name("p:*", 13)},
+	}, {
+		in:   `x[y != 'a]b"'] >= .5`,
+		want: []xpathToken{name("x", 0), op("[", 1), name("y", 2), op("!=", 4), lit(`'a]b"'`, 7), op("]", 13), op(">=", 15), num(".5", 18)},
+	}, {
+		in:   "current()//x-y.z",
+		want: []xpathToken{name("current", 0), op("(", 7), op(")", 8), op("//", 9), name("x-y.z", 11)},
+	}, {
+		in:            "a = 'b",
+		want:          []xpathToken{name("a", 0), op("=", 2)},
+		wantErrSubstr: "unterminated literal",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := xpathTokens(tt.in)
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Fatalf("xpathTokens: %s", diff)
+			}
+			if diff := cmp.Diff(tt.want, got, cmp.AllowUnexported(xpathToken{})); diff != "" {
+				t.Errorf("xpathTokens (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
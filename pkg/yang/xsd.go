@@ -0,0 +1,341 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+// This file implements the generation of an XML Schema (XSD) skeleton for
+// the data nodes of an Entry tree.
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// xsdBuiltin maps the YANG built-in types to the XSD built-in type that is
+// used as the base of their restriction.
+var xsdBuiltin = map[TypeKind]string{
+	Yint8:               "xs:byte",
+	Yint16:              "xs:short",
+	Yint32:              "xs:int",
+	Yint64:              "xs:long",
+	Yuint8:              "xs:unsignedByte",
+	Yuint16:             "xs:unsignedShort",
+	Yuint32:             "xs:unsignedInt",
+	Yuint64:             "xs:unsignedLong",
+	Ydecimal64:          "xs:decimal",
+	Ystring:             "xs:string",
+	Ybool:               "xs:boolean",
+	Ybinary:             "xs:base64Binary",
+	Yenum:               "xs:string",
+	Ybits:               "xs:string",
+	Yidentityref:        "xs:QName",
+	YinstanceIdentifier: "xs:string",
+	Yleafref:            "xs:string",
+}
+
+// XSDSkeleton returns an XML Schema document describing the data nodes of
+// the subtree rooted at e, as they are encoded in XML. Containers are
+// described by complex types, lists and leaf-lists by elements with maxOccurs
+// set, and leaves by simple types restricted by their range, length,
+// patterns or enumeration values. As the patterns of a facet are alternatives
+// in XSD, while each pattern of a YANG type must match, a type with several
+// patterns is described by nested restrictions that each add one pattern.
+// Patterns with an invert-match modifier cannot be described, so are omitted.
+// The children of each node are described
+// in order of name, following the keys of a list, and each choice is
+// described by an xs:choice of its cases. RPCs and notifications are
+// omitted.
+//
+// The target namespace of the schema is the namespace of the module that
+// instantiates e. Nodes within the subtree that are in another namespace,
+// such as those added by an augment from another module, are described by an
+// xs:any wildcard for that namespace.
+//
+// If e is a module then the schema describes the top-level data nodes of the
+// module, otherwise it describes e.
+func (e *Entry) XSDSkeleton() ([]byte, error) {
+	if e == nil {
+		return nil, fmt.Errorf("nil entry")
+	}
+	ns := e.Namespace()
+	if ns == nil || ns.Name == "" {
+		return nil, fmt.Errorf("%s: cannot determine namespace", e.Path())
+	}
+	w := &xsdWriter{ns: ns.Name}
+	w.line(`<?xml version="1.0" encoding="UTF-8"?>`)
+	w.open(`<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace=%s xmlns=%s elementFormDefault="qualified">`, xsdAttr(ns.Name), xsdAttr(ns.Name))
+	if e.Parent == nil {
+		for _, ce := range xsdChildren(e) {
+			w.node(ce)
+		}
+	} else {
+		if !e.IsDataNode() {
+			return nil, fmt.Errorf("%s: not a data node", e.Path())
+		}
+		w.node(e)
+	}
+	w.close("</xs:schema>")
+	return w.buf.Bytes(), nil
+}
+
+// An xsdWriter writes an indented XSD document.
+type xsdWriter struct {
+	buf   bytes.Buffer
+	ns    string // the target namespace.
+	depth int
+}
+
+// line writes a line formatted from format and v at the current depth.
+func (w *xsdWriter) line(format string, v ...interface{}) {
+	w.buf.WriteString(strings.Repeat("  ", w.depth))
+	fmt.Fprintf(&w.buf, format, v...)
+	w.buf.WriteByte('\n')
+}
+
+// open writes a line that opens an element and increases the depth.
+func (w *xsdWriter) open(format string, v ...interface{}) {
+	w.line(format, v...)
+	w.depth++
+}
+
+// close decreases the depth and writes a line that closes an element.
+func (w *xsdWriter) close(format string, v ...interface{}) {
+	w.depth--
+	w.line(format, v...)
+}
+
+// xsdAttr returns s escaped and quoted for use as an XML attribute value.
+func xsdAttr(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return `"` + strings.ReplaceAll(b.String(), `"`, "&#34;") + `"`
+}
+
+// xsdChildren returns the children of e that are described by the schema.
+// The keys of a list come first, in the order of its key statement, as
+// required by RFC7950 section 7.8.5, followed by the other children sorted
+// by name.
+func xsdChildren(e *Entry) []*Entry {
+	var es []*Entry
+	keys := map[string]bool{}
+	if e.IsList() {
+		for _, k := range e.keyNames() {
+			if ke := e.Dir[k]; ke != nil && !keys[k] {
+				keys[k] = true
+				es = append(es, ke)
+			}
+		}
+	}
+	var names []string
+	for name, ce := range e.Dir {
+		if keys[name] || ce.RPC != nil || ce.Kind == NotificationEntry {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		es = append(es, e.Dir[name])
+	}
+	return es
+}
+
+// node writes the description of the data node or choice e.
+func (w *xsdWriter) node(e *Entry) {
+	if ns := e.Namespace(); ns != nil && ns.Name != w.ns {
+		w.line(`<xs:any namespace=%s processContents="lax" minOccurs="0" maxOccurs="unbounded"/>`, xsdAttr(ns.Name))
+		return
+	}
+
+	if e.IsChoice() {
+		w.open(`<xs:choice minOccurs=%s>`, xsdMinOccurs(e.Mandatory == TSTrue))
+		for _, c := range xsdChildren(e) {
+			w.open("<xs:sequence>")
+			for _, ce := range xsdChildren(c) {
+				w.node(ce)
+			}
+			w.close("</xs:sequence>")
+		}
+		w.close("</xs:choice>")
+		return
+	}
+
+	occurs := fmt.Sprintf("minOccurs=%s", xsdMinOccurs(e.Mandatory == TSTrue || e.IsKey()))
+	if e.ListAttr != nil {
+		max := "unbounded"
+		if e.ListAttr.MaxElements != math.MaxUint64 {
+			max = fmt.Sprint(e.ListAttr.MaxElements)
+		}
+		occurs = fmt.Sprintf(`minOccurs="%d" maxOccurs="%s"`, e.ListAttr.MinElements, max)
+	}
+
+	switch {
+	case e.Kind == AnyDataEntry, e.Kind == AnyXMLEntry:
+		w.open(`<xs:element name=%s %s>`, xsdAttr(e.Name), occurs)
+		w.open(`<xs:complexType mixed="true">`)
+		w.open("<xs:sequence>")
+		w.line(`<xs:any processContents="lax" minOccurs="0" maxOccurs="unbounded"/>`)
+		w.close("</xs:sequence>")
+		w.close("</xs:complexType>")
+		w.close("</xs:element>")
+	case e.IsDir():
+		w.open(`<xs:element name=%s %s>`, xsdAttr(e.Name), occurs)
+		w.open("<xs:complexType>")
+		w.open("<xs:sequence>")
+		for _, ce := range xsdChildren(e) {
+			w.node(ce)
+		}
+		w.close("</xs:sequence>")
+		w.close("</xs:complexType>")
+		w.close("</xs:element>")
+	case e.Type != nil && e.Type.Kind == Yempty:
+		w.open(`<xs:element name=%s %s>`, xsdAttr(e.Name), occurs)
+		w.line("<xs:complexType/>")
+		w.close("</xs:element>")
+	default:
+		w.open(`<xs:element name=%s %s>`, xsdAttr(e.Name), occurs)
+		w.simpleType(e.Type)
+		w.close("</xs:element>")
+	}
+}
+
+// xsdMinOccurs returns the quoted minOccurs of a node that is required if
+// required is true.
+func xsdMinOccurs(required bool) string {
+	if required {
+		return `"1"`
+	}
+	return `"0"`
+}
+
+// simpleType writes an xs:simpleType describing the values of type y.
+func (w *xsdWriter) simpleType(y *YangType) {
+	w.open("<xs:simpleType>")
+	defer w.close("</xs:simpleType>")
+
+	if y == nil {
+		w.line(`<xs:restriction base="xs:string"/>`)
+		return
+	}
+	if y.Kind == Yunion {
+		w.open("<xs:union>")
+		for _, t := range y.FlattenedUnionTypes() {
+			w.simpleType(t)
+		}
+		w.close("</xs:union>")
+		return
+	}
+
+	// A range of several intervals is described by a union of the
+	// restrictions for each interval.
+	var ranges YangRange
+	switch {
+	case isIntegerKind(y.Kind) && len(y.Range) > 0 && !y.Range.Equal(effectiveRange(&YangType{Kind: y.Kind})):
+		ranges = y.Range
+	case y.Kind == Ydecimal64 && len(y.Range) > 0 && !isDecimal64FullRange(y.Range):
+		ranges = y.Range
+	}
+	if len(ranges) > 1 {
+		w.open("<xs:union>")
+		for i := range ranges {
+			w.open("<xs:simpleType>")
+			w.restriction(y, &ranges[i])
+			w.close("</xs:simpleType>")
+		}
+		w.close("</xs:union>")
+		return
+	}
+	var r *YRange
+	if len(ranges) == 1 {
+		r = &ranges[0]
+	}
+	w.restriction(y, r)
+}
+
+// restriction writes an xs:restriction describing the values of type y,
+// limited to the range r if it is not nil. The first pattern of y is a facet
+// of the restriction, which is the base of a restriction for each further
+// pattern.
+func (w *xsdWriter) restriction(y *YangType, r *YRange) {
+	var patterns []string
+	if y.Kind == Ystring {
+		for _, p := range y.Pattern {
+			if !y.PatternInverted(p) {
+				patterns = append(patterns, fmt.Sprintf(`<xs:pattern value=%s/>`, xsdAttr(p)))
+			}
+		}
+	}
+	var outer []string
+	if len(patterns) > 1 {
+		patterns, outer = patterns[:1], patterns[1:]
+	}
+	for range outer {
+		w.open("<xs:restriction>")
+		w.open("<xs:simpleType>")
+	}
+	w.baseRestriction(y, r, patterns)
+	for _, p := range outer {
+		w.close("</xs:simpleType>")
+		w.line("%s", p)
+		w.close("</xs:restriction>")
+	}
+}
+
+// baseRestriction writes the xs:restriction of the XSD built-in type that
+// describes the values of type y, limited to the range r if it is not nil,
+// with the facets of y followed by patterns.
+func (w *xsdWriter) baseRestriction(y *YangType, r *YRange, patterns []string) {
+	base := xsdBuiltin[y.Kind]
+	if base == "" {
+		base = "xs:string"
+	}
+	var facets []string
+	if r != nil {
+		facets = append(facets,
+			fmt.Sprintf(`<xs:minInclusive value="%s"/>`, r.Min),
+			fmt.Sprintf(`<xs:maxInclusive value="%s"/>`, r.Max))
+	}
+	switch y.Kind {
+	case Ydecimal64:
+		facets = append(facets, fmt.Sprintf(`<xs:fractionDigits value="%d"/>`, y.FractionDigits))
+	case Ystring, Ybinary:
+		if len(y.Length) == 1 && !y.Length.Equal(Uint64Range) {
+			l := y.Length[0]
+			facets = append(facets, fmt.Sprintf(`<xs:minLength value="%s"/>`, l.Min))
+			if l.Max.Value != math.MaxUint64 {
+				facets = append(facets, fmt.Sprintf(`<xs:maxLength value="%s"/>`, l.Max))
+			}
+		}
+	case Yenum:
+		if y.Enum != nil {
+			for _, v := range y.Enum.Values() {
+				facets = append(facets, fmt.Sprintf(`<xs:enumeration value=%s/>`, xsdAttr(y.Enum.Name(v))))
+			}
+		}
+	}
+	facets = append(facets, patterns...)
+	if len(facets) == 0 {
+		w.line(`<xs:restriction base="%s"/>`, base)
+		return
+	}
+	w.open(`<xs:restriction base="%s">`, base)
+	for _, f := range facets {
+		w.line("%s", f)
+	}
+	w.close("</xs:restriction>")
+}
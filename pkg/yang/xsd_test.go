@@ -0,0 +1,224 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+)
+
+func TestXSDSkeleton(t *testing.T) {
+	ms := NewModules()
+	for name, src := range map[string]string{
+		"xsd.yang": `
+			module xsd {
+				prefix "x";
+				namespace "urn:xsd";
+
+				leaf code {
+					type string {
+						length "2..8";
+						pattern "[A-Z]+";
+						pattern "X.*";
+						pattern ".*Z" { modifier invert-match; }
+						pattern ".*Q";
+					}
+				}
+				container system {
+					leaf mtu {
+						type uint16 { range "68..9000"; }
+					}
+					leaf state {
+						type enumeration {
+							enum UP;
+							enum DOWN;
+						}
+					}
+					leaf-list dns {
+						type string { length "1..253"; }
+						max-elements 3;
+					}
+					list user {
+						key "name";
+						leaf name { type string; }
+						leaf enabled { type empty; }
+					}
+					choice auth {
+						leaf password { type string; }
+						leaf key { type binary; }
+					}
+				}
+				rpc reboot;
+			}`,
+		"aug.yang": `
+			module aug {
+				prefix "a";
+				namespace "urn:aug";
+				import xsd { prefix x; }
+				augment /x:system {
+					leaf extra { type string; }
+				}
+			}`,
+	} {
+		if err := ms.Parse(src, name); err != nil {
+			t.Fatalf("cannot parse %s: %v", name, err)
+		}
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process modules: %v", errs)
+	}
+	root := ToEntry(ms.Modules["xsd"])
+
+	tests := []struct {
+		desc          string
+		in            *Entry
+		want          string
+		wantErrSubstr string
+	}{{
+		desc: "module",
+		in:   root,
+		want: `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="urn:xsd" xmlns="urn:xsd" elementFormDefault="qualified">
+  <xs:element name="code" minOccurs="0">
+    <xs:simpleType>
+      <xs:restriction>
+        <xs:simpleType>
+          <xs:restriction>
+            <xs:simpleType>
+              <xs:restriction base="xs:string">
+                <xs:minLength value="2"/>
+                <xs:maxLength value="8"/>
+                <xs:pattern value="[A-Z]+"/>
+              </xs:restriction>
+            </xs:simpleType>
+            <xs:pattern value="X.*"/>
+          </xs:restriction>
+        </xs:simpleType>
+        <xs:pattern value=".*Q"/>
+      </xs:restriction>
+    </xs:simpleType>
+  </xs:element>
+  <xs:element name="system" minOccurs="0">
+    <xs:complexType>
+      <xs:sequence>
+        <xs:choice minOccurs="0">
+          <xs:sequence>
+            <xs:element name="key" minOccurs="0">
+              <xs:simpleType>
+                <xs:restriction base="xs:base64Binary"/>
+              </xs:simpleType>
+            </xs:element>
+          </xs:sequence>
+          <xs:sequence>
+            <xs:element name="password" minOccurs="0">
+              <xs:simpleType>
+                <xs:restriction base="xs:string"/>
+              </xs:simpleType>
+            </xs:element>
+          </xs:sequence>
+        </xs:choice>
+        <xs:element name="dns" minOccurs="0" maxOccurs="3">
+          <xs:simpleType>
+            <xs:restriction base="xs:string">
+              <xs:minLength value="1"/>
+              <xs:maxLength value="253"/>
+            </xs:restriction>
+          </xs:simpleType>
+        </xs:element>
+        <xs:any namespace="urn:aug" processContents="lax" minOccurs="0" maxOccurs="unbounded"/>
+        <xs:element name="mtu" minOccurs="0">
+          <xs:simpleType>
+            <xs:restriction base="xs:unsignedShort">
+              <xs:minInclusive value="68"/>
+              <xs:maxInclusive value="9000"/>
+            </xs:restriction>
+          </xs:simpleType>
+        </xs:element>
+        <xs:element name="state" minOccurs="0">
+          <xs:simpleType>
+            <xs:restriction base="xs:string">
+              <xs:enumeration value="UP"/>
+              <xs:enumeration value="DOWN"/>
+            </xs:restriction>
+          </xs:simpleType>
+        </xs:element>
+        <xs:element name="user" minOccurs="0" maxOccurs="unbounded">
+          <xs:complexType>
+            <xs:sequence>
+              <xs:element name="name" minOccurs="1">
+                <xs:simpleType>
+                  <xs:restriction base="xs:string"/>
+                </xs:simpleType>
+              </xs:element>
+              <xs:element name="enabled" minOccurs="0">
+                <xs:complexType/>
+              </xs:element>
+            </xs:sequence>
+          </xs:complexType>
+        </xs:element>
+      </xs:sequence>
+    </xs:complexType>
+  </xs:element>
+</xs:schema>
+`,
+	}, {
+		desc: "leaf",
+		in:   root.Dir["system"].Dir["mtu"],
+		want: `<?xml version="1.0" encoding="UTF-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema" targetNamespace="urn:xsd" xmlns="urn:xsd" elementFormDefault="qualified">
+  <xs:element name="mtu" minOccurs="0">
+    <xs:simpleType>
+      <xs:restriction base="xs:unsignedShort">
+        <xs:minInclusive value="68"/>
+        <xs:maxInclusive value="9000"/>
+      </xs:restriction>
+    </xs:simpleType>
+  </xs:element>
+</xs:schema>
+`,
+	}, {
+		desc:          "rpc",
+		in:            root.Dir["reboot"],
+		wantErrSubstr: "/xsd/reboot: not a data node",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			got, err := tt.in.XSDSkeleton()
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Fatalf("XSDSkeleton: %s", diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tt.want, string(got)); diff != "" {
+				t.Errorf("XSDSkeleton (-want, +got):\n%s", diff)
+			}
+			d := xml.NewDecoder(bytes.NewReader(got))
+			for {
+				if _, err := d.Token(); err == io.EOF {
+					break
+				} else if err != nil {
+					t.Fatalf("XSDSkeleton returned invalid XML: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -49,7 +49,7 @@ func (s *Value) asRangeInt(min, max int64) (int64, error) {
 	}
 	n, err := ParseInt(s.Name)
 	if err != nil {
-		return 0, err
+		return 0, fmt.Errorf("value %s is not an integer in the range [%d..%d]: %v", s.Name, min, max, err)
 	}
 	i, err := n.Int()
 	if err != nil {
@@ -159,6 +159,32 @@ func (s *Module) Current() string {
 	return rev
 }
 
+// Version returns the version of YANG of the module, as given by its
+// yang-version statement: "1.1", or "1.0" if the module has no yang-version
+// statement or its argument is "1", the only version defined by RFC6020.
+func (s *Module) Version() string {
+	if s.YangVersion == nil || s.YangVersion.Name == "1" {
+		return "1.0"
+	}
+	return s.YangVersion.Name
+}
+
+// MainModule returns the module that s belongs to: s itself if s is a
+// module, or, if s is a submodule, the module named by its belongs-to
+// statement, as found within the Modules that s was read into. An error is
+// returned if the module is not found.
+func (s *Module) MainModule() (*Module, error) {
+	if s.BelongsTo == nil {
+		return s, nil
+	}
+	if s.Modules != nil {
+		if m := s.Modules.Modules[s.BelongsTo.Name]; m != nil {
+			return m, nil
+		}
+	}
+	return nil, fmt.Errorf("%s: module %s of submodule %s not found", Source(s.BelongsTo), s.BelongsTo.Name, s.Name)
+}
+
 // FullName returns the full name of the module including the most recent
 // revision, if any.
 func (s *Module) FullName() string {
@@ -284,6 +310,10 @@ type Typedef struct {
 	Units       *Value `yang:"units"`
 
 	YangType *YangType `json:"-"`
+
+	// resolving is set while the typedef is being resolved, so that a
+	// typedef that is derived from itself can be detected.
+	resolving bool
 }
 
 func (Typedef) Kind() string             { return "typedef" }
@@ -832,11 +862,6 @@ func (s *Identity) PrefixedName() string {
 	return fmt.Sprintf("%s:%s", RootNode(s).GetPrefix(), s.Name)
 }
 
-// modulePrefixedName returns the module-qualified name for the identity.
-func (s *Identity) modulePrefixedName() string {
-	return fmt.Sprintf("%s:%s", module(s).Name, s.Name)
-}
-
 // IsDefined behaves the same as the implementation for Enum - it returns
 // true if an identity with the name is defined within the Values of the
 // identity
@@ -0,0 +1,605 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+// This file implements the serialization of entries back to YANG source.
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// YANGString returns YANG source for e and its descendants. The source
+// describes the schema tree of e, rather than the statements from which it
+// was built: groupings are expanded, augments are merged, deviations are
+// applied, and the type of each leaf and leaf-list is written as its built-in
+// type, with the restrictions, units and default of any typedefs it is
+// derived from. The config, mandatory, default, presence, key, unique,
+// ordered-by, min-elements, max-elements, when, must, if-feature, status,
+// description and reference statements of each entry are included. Children
+// are written in the order of their statements, as given by
+// OrderedChildNames. Extensions are not included.
+//
+// If e is the entry of a module, the source is of the module, including its
+// namespace, prefix, imports, revisions, features and identities, and those of
+// the submodules it includes, and may be parsed by Parse into an equivalent
+// tree. Otherwise, the source is of the statement of e, which may be placed
+// within the module of e. The nodes added to e by augments are written as
+// though they were defined by the module of e. References to identities,
+// features and nodes of other modules use the prefixes with which the module
+// written imports them. If it does not import a module that is referred to,
+// the source of a module imports it, with its own prefix or, if that is in
+// use, a prefix derived from it.
+func (e *Entry) YANGString() string {
+	var b strings.Builder
+	w := &yangWriter{b: &b, scope: newYangScope(entryModule(e))}
+	w.entry(e, "")
+	return b.String()
+}
+
+// A yangWriter writes YANG source to b, within scope.
+type yangWriter struct {
+	b     *strings.Builder
+	scope *yangScope
+}
+
+// sub returns a writer to a new builder within the scope of w.
+func (w *yangWriter) sub() *yangWriter {
+	return &yangWriter{b: &strings.Builder{}, scope: w.scope}
+}
+
+// A yangScope holds the prefixes with which the source of a module refers to
+// other modules.
+type yangScope struct {
+	// module is the name of the module written, or of the module to which
+	// the submodule written belongs.
+	module string
+	// prefixes maps the names of modules to the prefixes with which they
+	// are referred to.
+	prefixes map[string]string
+	// used holds the prefixes in use.
+	used map[string]bool
+	// imports holds the names of the modules that are referred to but not
+	// imported by the module written, in the order in which they were
+	// found.
+	imports []string
+	// ms holds the modules from which the prefixes of other modules are
+	// found.
+	ms *Modules
+}
+
+// newYangScope returns the scope of the module or submodule m, which may be
+// nil if the module is not known.
+func newYangScope(m *Module) *yangScope {
+	sc := &yangScope{prefixes: map[string]string{}, used: map[string]bool{}}
+	if m == nil {
+		return sc
+	}
+	sc.module, sc.ms = moduleName(m), m.Modules
+	sc.prefixes[sc.module] = m.GetPrefix()
+	sc.used[m.GetPrefix()] = true
+	for _, i := range m.Import {
+		if i.Prefix != nil {
+			sc.prefixes[i.Name] = i.Prefix.Name
+			sc.used[i.Prefix.Name] = true
+		}
+	}
+	return sc
+}
+
+// prefix returns the prefix with which the scope refers to the module mod,
+// choosing one if the module is not yet referred to.
+func (sc *yangScope) prefix(mod string) string {
+	if p, ok := sc.prefixes[mod]; ok {
+		return p
+	}
+	p := mod
+	if sc.ms != nil {
+		if m := sc.ms.Modules[mod]; m != nil && m.GetPrefix() != "" {
+			p = m.GetPrefix()
+		}
+	}
+	for i, base := 2, p; sc.used[p]; i++ {
+		p = fmt.Sprintf("%s%d", base, i)
+	}
+	sc.prefixes[mod], sc.used[p] = p, true
+	sc.imports = append(sc.imports, mod)
+	return p
+}
+
+// ref returns the name name, defined by the module mod, as it is referred
+// to within the scope.
+func (sc *yangScope) ref(mod, name string) string {
+	if mod == "" || mod == sc.module {
+		return name
+	}
+	return sc.prefix(mod) + ":" + name
+}
+
+// qualify returns the possibly prefixed name s, which refers to the
+// definition of an identity or feature from the statement n, as it is
+// referred to within the scope. s is returned unchanged if its prefix is not
+// known at n.
+func (sc *yangScope) qualify(n Node, s string) string {
+	if n == nil {
+		return s
+	}
+	pfx, name := getPrefix(s)
+	m := FindModuleByPrefix(n, pfx)
+	if m == nil {
+		return s
+	}
+	return sc.ref(moduleName(m), name)
+}
+
+// xpath returns the XPath expression, or if-feature expression, s, of the
+// statement n, with each prefix replaced by the prefix with which the scope
+// refers to its module. If names is true, unprefixed names, other than the
+// operators of if-feature expressions, are qualified as by qualify; this is
+// the case for the names of features, but not for the names of data nodes,
+// which are within the module of the context node.
+func (sc *yangScope) xpath(n Node, s string, names bool) string {
+	toks, err := xpathTokens(s)
+	if err != nil || n == nil {
+		return s
+	}
+	var b strings.Builder
+	last := 0
+	for _, t := range toks {
+		if t.kind != xpathName {
+			continue
+		}
+		pfx, name := getPrefix(t.text)
+		if pfx == t.text {
+			pfx, name = "", t.text
+		}
+		if pfx == "" && (!names || name == "and" || name == "or" || name == "not") {
+			continue
+		}
+		m := FindModuleByPrefix(n, pfx)
+		if m == nil {
+			continue
+		}
+		b.WriteString(s[last:t.pos])
+		b.WriteString(sc.ref(moduleName(m), name))
+		last = t.end()
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// moduleName returns the name of m or, if m is a submodule, of the module to
+// which it belongs.
+func moduleName(m *Module) string {
+	if m.BelongsTo != nil {
+		return m.BelongsTo.Name
+	}
+	return m.Name
+}
+
+// entryModule returns the module or submodule that defines e, or the nearest
+// ancestor of e with a node, or nil if there is none.
+func entryModule(e *Entry) *Module {
+	for ; e != nil; e = e.Parent {
+		if e.Node != nil {
+			return RootNode(e.Node)
+		}
+	}
+	return nil
+}
+
+// stmt writes the statement keyword, with the argument arg, quoted if quote
+// is true, as a single line indented by indent.
+func (w *yangWriter) stmt(indent, keyword, arg string, quote bool) {
+	if quote {
+		arg = yangQuote(arg)
+	}
+	fmt.Fprintf(w.b, "%s%s %s;\n", indent, keyword, arg)
+}
+
+// entry writes the statement of e, indented by indent.
+func (w *yangWriter) entry(e *Entry, indent string) {
+	keyword := yangKeyword(e)
+	arg := e.Name
+	if e.Kind == InputEntry || e.Kind == OutputEntry {
+		arg = ""
+	}
+	if arg != "" {
+		arg = " " + arg
+	}
+
+	sub := w.sub()
+	sub.substatements(e, keyword, indent+"\t")
+	if sub.b.Len() == 0 {
+		fmt.Fprintf(w.b, "%s%s%s;\n", indent, keyword, arg)
+		return
+	}
+	fmt.Fprintf(w.b, "%s%s%s {\n%s%s}\n", indent, keyword, arg, sub.b.String(), indent)
+}
+
+// substatements writes the substatements of the statement keyword of e,
+// indented by indent.
+func (w *yangWriter) substatements(e *Entry, keyword, indent string) {
+	if keyword == "module" || keyword == "submodule" {
+		w.module(e, indent)
+		return
+	}
+	if v := e.whenValue(); v != nil {
+		x, _ := e.GetWhenXPath()
+		w.stmt(indent, "when", w.scope.xpath(v, x, false), true)
+	}
+	for _, v := range e.Extra["if-feature"] {
+		if v, ok := v.(*Value); ok {
+			w.stmt(indent, "if-feature", w.scope.xpath(v, v.Name, true), true)
+		}
+	}
+	if p, ok := e.Presence(); ok {
+		w.stmt(indent, "presence", p, true)
+	}
+	if e.IsList() && e.Key != "" {
+		w.stmt(indent, "key", e.Key, true)
+	}
+	for _, v := range e.Extra["unique"] {
+		if v, ok := v.(*Value); ok {
+			w.stmt(indent, "unique", v.Name, true)
+		}
+	}
+	if e.IsLeaf() || e.IsLeafList() {
+		if e.Type != nil {
+			w.yangType(e.Type, e.leafrefTypes(), indent)
+		}
+		// The units of a leaf are only recorded by its node, those of a
+		// leaf-list by its entry, and those of a typedef by its type.
+		units := e.Units
+		if l, ok := e.Node.(*Leaf); ok && l.Units != nil {
+			units = l.Units.Name
+		}
+		if units == "" && e.Type != nil {
+			units = e.Type.Units
+		}
+		if units != "" {
+			w.stmt(indent, "units", units, true)
+		}
+	}
+	for _, v := range e.Extra["must"] {
+		if m, ok := v.(*Must); ok {
+			w.must(m, indent)
+		}
+	}
+	switch {
+	case e.IsLeaf() && e.Mandatory != TSTrue, e.IsLeafList() && e.ListAttr.MinElements == 0:
+		for _, d := range e.DefaultValues() {
+			if e.Type != nil && e.Type.Kind == Yidentityref {
+				d = w.scope.qualify(e.Node, d)
+			}
+			w.stmt(indent, "default", d, true)
+		}
+	case e.IsChoice():
+		for _, d := range e.Default {
+			w.stmt(indent, "default", d, true)
+		}
+	}
+	switch e.Config {
+	case TSTrue:
+		w.stmt(indent, "config", "true", false)
+	case TSFalse:
+		w.stmt(indent, "config", "false", false)
+	}
+	switch e.Mandatory {
+	case TSTrue:
+		w.stmt(indent, "mandatory", "true", false)
+	case TSFalse:
+		w.stmt(indent, "mandatory", "false", false)
+	}
+	if a := e.ListAttr; a != nil {
+		if a.MinElements != 0 {
+			w.stmt(indent, "min-elements", fmt.Sprint(a.MinElements), false)
+		}
+		if a.MaxElements != math.MaxUint64 {
+			w.stmt(indent, "max-elements", fmt.Sprint(a.MaxElements), false)
+		}
+		if a.OrderedByUser {
+			w.stmt(indent, "ordered-by", "user", false)
+		}
+	}
+	if s, ok := e.ownStatus(); ok {
+		w.stmt(indent, "status", s.String(), false)
+	}
+	if e.Description != "" {
+		w.stmt(indent, "description", e.Description, true)
+	}
+	for _, v := range e.Extra["reference"] {
+		if v, ok := v.(*Value); ok {
+			w.stmt(indent, "reference", v.Name, true)
+		}
+	}
+	w.children(e, indent)
+}
+
+// children writes the statements of the children of e, indented by indent.
+func (w *yangWriter) children(e *Entry, indent string) {
+	for _, name := range e.OrderedChildNames() {
+		w.entry(e.Dir[name], indent)
+	}
+	// The input or output of an operation that does not define one is
+	// empty. It is not written, as an input or output statement must
+	// define at least one data node.
+	for _, io := range e.definedOperationChildren() {
+		w.entry(io, indent)
+	}
+}
+
+// module writes the substatements of the module or submodule of the entry e,
+// indented by indent.
+func (w *yangWriter) module(e *Entry, indent string) {
+	m, ok := e.Node.(*Module)
+	if !ok {
+		w.children(e, indent)
+		return
+	}
+
+	// The body is written first, so that the modules it refers to, which
+	// must be imported, are known when the header is written.
+	body := w.sub()
+	for _, sm := range includedModules(m) {
+		for _, f := range sm.Feature {
+			body.stmt(indent, "feature", f.Name, false)
+		}
+		for _, i := range sm.Identity {
+			if len(i.Base) == 0 {
+				fmt.Fprintf(body.b, "%sidentity %s;\n", indent, i.Name)
+				continue
+			}
+			fmt.Fprintf(body.b, "%sidentity %s {\n", indent, i.Name)
+			for _, b := range i.Base {
+				body.stmt(indent+"\t", "base", w.scope.qualify(b, b.Name), false)
+			}
+			fmt.Fprintf(body.b, "%s}\n", indent)
+		}
+	}
+	body.children(e, indent)
+
+	in := indent + "\t"
+	if m.YangVersion != nil {
+		w.stmt(indent, "yang-version", m.YangVersion.Name, false)
+	}
+	if m.BelongsTo != nil {
+		fmt.Fprintf(w.b, "%sbelongs-to %s {\n", indent, m.BelongsTo.Name)
+		w.stmt(in, "prefix", m.GetPrefix(), false)
+		fmt.Fprintf(w.b, "%s}\n", indent)
+	} else {
+		if m.Namespace != nil {
+			w.stmt(indent, "namespace", m.Namespace.Name, true)
+		}
+		if m.Prefix != nil {
+			w.stmt(indent, "prefix", m.Prefix.Name, false)
+		}
+	}
+	for _, i := range m.Import {
+		fmt.Fprintf(w.b, "%simport %s {\n", indent, i.Name)
+		w.stmt(in, "prefix", w.scope.prefix(i.Name), false)
+		if i.RevisionDate != nil {
+			w.stmt(in, "revision-date", i.RevisionDate.Name, false)
+		}
+		fmt.Fprintf(w.b, "%s}\n", indent)
+	}
+	for _, name := range w.scope.imports {
+		fmt.Fprintf(w.b, "%simport %s {\n", indent, name)
+		w.stmt(in, "prefix", w.scope.prefix(name), false)
+		fmt.Fprintf(w.b, "%s}\n", indent)
+	}
+	for _, v := range []struct {
+		keyword string
+		arg     *Value
+	}{
+		{"organization", m.Organization},
+		{"contact", m.Contact},
+		{"description", m.Description},
+		{"reference", m.Reference},
+	} {
+		if v.arg != nil {
+			w.stmt(indent, v.keyword, v.arg.Name, true)
+		}
+	}
+	for _, r := range m.Revision {
+		if r.Description == nil && r.Reference == nil {
+			w.stmt(indent, "revision", r.Name, false)
+			continue
+		}
+		fmt.Fprintf(w.b, "%srevision %s {\n", indent, r.Name)
+		if r.Description != nil {
+			w.stmt(in, "description", r.Description.Name, true)
+		}
+		if r.Reference != nil {
+			w.stmt(in, "reference", r.Reference.Name, true)
+		}
+		fmt.Fprintf(w.b, "%s}\n", indent)
+	}
+	w.b.WriteString(body.b.String())
+}
+
+// includedModules returns m followed by the submodules it includes, directly
+// or indirectly.
+func includedModules(m *Module) []*Module {
+	mods := []*Module{m}
+	seen := map[*Module]bool{m: true}
+	for i := 0; i < len(mods); i++ {
+		for _, in := range mods[i].Include {
+			if in.Module != nil && !seen[in.Module] {
+				seen[in.Module] = true
+				mods = append(mods, in.Module)
+			}
+		}
+	}
+	return mods
+}
+
+// must writes the must statement m, indented by indent.
+func (w *yangWriter) must(m *Must, indent string) {
+	x := w.scope.xpath(m, m.Name, false)
+	if m.ErrorMessage == nil && m.ErrorAppTag == nil {
+		w.stmt(indent, "must", x, true)
+		return
+	}
+	fmt.Fprintf(w.b, "%smust %s {\n", indent, yangQuote(x))
+	if m.ErrorMessage != nil {
+		w.stmt(indent+"\t", "error-message", m.ErrorMessage.Name, true)
+	}
+	if m.ErrorAppTag != nil {
+		w.stmt(indent+"\t", "error-app-tag", m.ErrorAppTag.Name, true)
+	}
+	fmt.Fprintf(w.b, "%s}\n", indent)
+}
+
+// yangType writes a type statement for the built-in type of y, with its
+// restrictions, indented by indent. paths holds the type statements that
+// define the paths of any leafrefs within y, within which their prefixes are
+// resolved.
+func (w *yangWriter) yangType(y *YangType, paths []*Type, indent string) {
+	sub := w.sub()
+	in := indent + "\t"
+	if y.Kind == Ydecimal64 {
+		sub.stmt(in, "fraction-digits", fmt.Sprint(y.FractionDigits), false)
+	}
+	if len(y.Range) > 0 && !y.Range.Equal(defaultRange(y)) {
+		sub.stmt(in, "range", y.Range.String(), true)
+	}
+	if len(y.Length) > 0 && !y.Length.Equal(Uint64Range) {
+		sub.stmt(in, "length", y.Length.String(), true)
+	}
+	for _, p := range y.Pattern {
+		if !y.PatternInverted(p) {
+			sub.stmt(in, "pattern", p, true)
+			continue
+		}
+		fmt.Fprintf(sub.b, "%spattern %s {\n", in, yangQuote(p))
+		sub.stmt(in+"\t", "modifier", "invert-match", false)
+		fmt.Fprintf(sub.b, "%s}\n", in)
+	}
+	switch y.Kind {
+	case Yenum:
+		sub.enum(y.Enum, "enum", "value", in)
+	case Ybits:
+		sub.enum(y.Bit, "bit", "position", in)
+	case Yleafref:
+		path := y.Path
+		for _, t := range paths {
+			if t.Path != nil && t.Path.Name == y.Path {
+				path = w.scope.xpath(t, y.Path, false)
+				break
+			}
+		}
+		sub.stmt(in, "path", path, true)
+	case Yidentityref:
+		if b := y.IdentityBase; b != nil {
+			sub.stmt(in, "base", w.scope.ref(moduleName(RootNode(b)), b.Name), false)
+		}
+	case Yunion:
+		for _, t := range y.Type {
+			sub.yangType(t, paths, in)
+		}
+	}
+	if (y.Kind == Yleafref || y.Kind == YinstanceIdentifier) && y.OptionalInstance {
+		sub.stmt(in, "require-instance", "false", false)
+	}
+
+	name := TypeKindToName[y.Kind]
+	if sub.b.Len() == 0 {
+		fmt.Fprintf(w.b, "%stype %s;\n", indent, name)
+		return
+	}
+	fmt.Fprintf(w.b, "%stype %s {\n%s%s}\n", indent, name, sub.b.String(), indent)
+}
+
+// enum writes the names of e, in the order of their values, as statements
+// keyword, each with a substatement valueKeyword giving its value, indented
+// by indent.
+func (w *yangWriter) enum(e *EnumType, keyword, valueKeyword, indent string) {
+	if e == nil {
+		return
+	}
+	names := e.Names()
+	sort.SliceStable(names, func(i, j int) bool {
+		return e.Value(names[i]) < e.Value(names[j])
+	})
+	for _, n := range names {
+		fmt.Fprintf(w.b, "%s%s %s {\n", indent, keyword, yangQuote(n))
+		w.stmt(indent+"\t", valueKeyword, fmt.Sprint(e.Value(n)), false)
+		fmt.Fprintf(w.b, "%s}\n", indent)
+	}
+}
+
+// defaultRange returns the range of the built-in type of y, which need not be
+// given by a range statement, or nil if the type has no range.
+func defaultRange(y *YangType) YangRange {
+	if y.Kind == Ydecimal64 {
+		fd := uint8(y.FractionDigits)
+		return YangRange{{
+			Number{Value: AbsMinInt64, Negative: true, FractionDigits: fd},
+			Number{Value: MaxInt64, FractionDigits: fd},
+		}}
+	}
+	if t := baseTypes[TypeKindToName[y.Kind]]; t != nil {
+		return t.Range
+	}
+	return nil
+}
+
+// yangKeyword returns the keyword of the statement that defines e.
+func yangKeyword(e *Entry) string {
+	switch {
+	case e.RPC != nil:
+		if e.Node != nil && e.Node.Kind() == "action" {
+			return "action"
+		}
+		return "rpc"
+	case e.Parent == nil && e.Node != nil && (e.Node.Kind() == "module" || e.Node.Kind() == "submodule"):
+		return e.Node.Kind()
+	case e.IsLeaf():
+		return "leaf"
+	case e.IsLeafList():
+		return "leaf-list"
+	case e.IsList():
+		return "list"
+	case e.IsChoice():
+		return "choice"
+	case e.IsCase():
+		return "case"
+	}
+	switch e.Kind {
+	case AnyDataEntry:
+		return "anydata"
+	case AnyXMLEntry:
+		return "anyxml"
+	case InputEntry:
+		return "input"
+	case OutputEntry:
+		return "output"
+	case NotificationEntry:
+		return "notification"
+	}
+	return "container"
+}
+
+// yangQuote returns s as a double quoted YANG string.
+func yangQuote(s string) string {
+	return `"` + yangEscaper.Replace(s) + `"`
+}
+
+// yangEscaper escapes the characters of a string that must be escaped within
+// a double quoted YANG string.
+var yangEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`)
@@ -0,0 +1,349 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const yangStringModule = `
+module test {
+	yang-version 1.1;
+	namespace "urn:test";
+	prefix t;
+
+	feature fancy;
+	identity base-id;
+	identity derived { base base-id; }
+
+	typedef percent {
+		type uint8 { range "0..100"; }
+		units "percent";
+		default "50";
+	}
+
+	grouping addr {
+		leaf ip { type string { length "7..15"; pattern '[0-9.]+'; } }
+	}
+
+	container c {
+		presence "enabled";
+		description "A \"quoted\" description
+spanning lines.";
+		leaf name { type string; mandatory true; }
+		leaf load { type percent; }
+		leaf ratio { type decimal64 { fraction-digits 2; range "0..1"; } default "0.5"; }
+		leaf color { type enumeration { enum red { value 2; } enum "light blue"; } }
+		leaf perms { type bits { bit read; bit write { position 4; } } }
+		leaf id { type identityref { base derived; } }
+		leaf either { type union { type int8; type enumeration { enum none; } } }
+		leaf ref { type leafref { path "../load"; require-instance false; } }
+		leaf state { type boolean; config false; }
+		leaf nox { type string { pattern 'x.*' { modifier invert-match; } } }
+		leaf old { type empty; status deprecated; if-feature fancy; }
+		uses addr;
+		leaf-list tags { type string; max-elements 4; ordered-by user; default "a"; }
+		list l {
+			key "k";
+			unique "v";
+			min-elements 1;
+			leaf k { type string; }
+			leaf v { type int32; must ". > 0" { error-message "must be positive"; } }
+		}
+		choice ch {
+			default b;
+			leaf a { type string; }
+			case b { leaf b1 { type string; } }
+		}
+		anydata any;
+	}
+
+	rpc r {
+		input { leaf in { type string; } }
+	}
+
+	notification n {
+		leaf what { type string; }
+	}
+}
+`
+
+func TestYANGString(t *testing.T) {
+	process := func(src string) *Entry {
+		t.Helper()
+		ms := NewModules()
+		if err := ms.Parse(src, "test.yang"); err != nil {
+			t.Fatalf("cannot parse module: %v\n%s", err, src)
+		}
+		if errs := ms.Process(); len(errs) > 0 {
+			t.Fatalf("cannot process module: %v\n%s", errs, src)
+		}
+		return ToEntry(ms.Modules["test"])
+	}
+	orig := process(yangStringModule)
+	got := orig.YANGString()
+	round := process(got)
+
+	// The source of the processed round trip is the same.
+	if diff := cmp.Diff(got, round.YANGString()); diff != "" {
+		t.Errorf("YANGString of round trip (-first, +second):\n%s", diff)
+	}
+
+	// The entries of the round trip are equivalent to the originals. The
+	// units and defaults of typedefs become those of the leaves, so they are
+	// compared through the leaves rather than the types.
+	type summary struct {
+		Kind        EntryKind
+		Config      TriState
+		Mandatory   TriState
+		Key         string
+		Description string
+		Units       string
+		Defaults    []string
+		MinElements uint64
+		MaxElements uint64
+		OrderedBy   bool
+		When        string
+		Status      Status
+	}
+	summarize := func(e *Entry) summary {
+		s := summary{
+			Kind:        e.Kind,
+			Config:      e.Config,
+			Mandatory:   e.Mandatory,
+			Key:         e.Key,
+			Description: e.Description,
+			Defaults:    e.DefaultValues(),
+			OrderedBy:   e.IsOrderedByUser(),
+			Status:      e.Status(),
+		}
+		s.When, _ = e.GetWhenXPath()
+		if e.ListAttr != nil {
+			s.MinElements, s.MaxElements = e.ListAttr.MinElements, e.ListAttr.MaxElements
+		}
+		if e.Type != nil {
+			s.Units = e.Type.Units
+		}
+		if l, ok := e.Node.(*Leaf); ok && l.Units != nil {
+			s.Units = l.Units.Name
+		}
+		return s
+	}
+	// The identities of the round trip are those of its own module, so are
+	// compared by name.
+	stripType := func(y *YangType) (*YangType, string) {
+		c := *y
+		c.Units, c.Default, c.HasDefault = "", "", false
+		var base string
+		if c.IdentityBase != nil {
+			base = c.IdentityBase.PrefixedName()
+			c.IdentityBase = nil
+		}
+		return &c, base
+	}
+	origEntries, roundEntries := map[string]*Entry{}, map[string]*Entry{}
+	var walk func(m map[string]*Entry, e *Entry)
+	walk = func(m map[string]*Entry, e *Entry) {
+		m[e.Path()] = e
+		for _, ce := range e.Dir {
+			walk(m, ce)
+		}
+		if e.RPC != nil && e.RPC.Input != nil {
+			walk(m, e.RPC.Input)
+		}
+	}
+	walk(origEntries, orig)
+	walk(roundEntries, round)
+	if len(origEntries) != len(roundEntries) {
+		t.Errorf("round trip has %d entries, want %d", len(roundEntries), len(origEntries))
+	}
+	for p, oe := range origEntries {
+		re := roundEntries[p]
+		if re == nil {
+			t.Errorf("round trip is missing %s", p)
+			continue
+		}
+		if diff := cmp.Diff(summarize(oe), summarize(re)); diff != "" {
+			t.Errorf("round trip of %s (-want, +got):\n%s", p, diff)
+		}
+		if (oe.Type == nil) != (re.Type == nil) {
+			t.Errorf("round trip of %s: got type %v, want %v", p, re.Type, oe.Type)
+			continue
+		}
+		if oe.Type != nil {
+			ot, obase := stripType(oe.Type)
+			rt, rbase := stripType(re.Type)
+			if !ot.Equal(rt) || obase != rbase {
+				t.Errorf("round trip of %s: got type %v, want %v", p, re.Type, oe.Type)
+			}
+		}
+	}
+
+	// The source of a subtree is of its statement alone.
+	wantList := `list l {
+	key "k";
+	unique "v";
+	min-elements 1;
+	leaf k {
+		type string;
+	}
+	leaf v {
+		type int32;
+		must ". > 0" {
+			error-message "must be positive";
+		}
+	}
+}
+`
+	if diff := cmp.Diff(wantList, orig.Dir["c"].Dir["l"].YANGString()); diff != "" {
+		t.Errorf("YANGString of list (-want, +got):\n%s", diff)
+	}
+}
+
+func TestYANGStringImports(t *testing.T) {
+	deps := map[string]string{
+		"b.yang": `
+			module b {
+				prefix b;
+				namespace "urn:b";
+				feature bf;
+				identity base-id;
+				container target {
+					leaf k { type string; }
+				}
+			}`,
+		"c.yang": `
+			module c {
+				prefix bb;
+				namespace "urn:c";
+				identity cid;
+			}`,
+	}
+	srcs := map[string]string{
+		"test.yang": `
+			module test {
+				yang-version 1.1;
+				namespace "urn:test";
+				prefix t;
+				import b { prefix bb; }
+				include test-sub;
+				organization "Example";
+				revision 2026-01-02 { description "Second."; }
+				revision 2026-01-01;
+
+				identity own { base bb:base-id; }
+				container c {
+					leaf id { type identityref { base bb:base-id; } default bb:base-id; }
+					leaf ref { type leafref { path "/bb:target/bb:k"; } }
+					leaf opt { type string; if-feature bb:bf; must "/bb:target/bb:k = 'x'"; }
+				}
+			}`,
+		"test-sub.yang": `
+			submodule test-sub {
+				yang-version 1.1;
+				belongs-to test { prefix t; }
+				import b { prefix sb; }
+				revision 2026-01-01;
+				identity sub-id { base sb:base-id; }
+				leaf s { type identityref { base t:own; } }
+			}`,
+		"aug.yang": `
+			module aug {
+				prefix a;
+				namespace "urn:aug";
+				import test { prefix t; }
+				import b { prefix b2; }
+				import c { prefix c; }
+				augment "/t:c" {
+					leaf x { type identityref { base b2:base-id; } if-feature b2:bf; }
+					leaf y { type identityref { base c:cid; } }
+				}
+			}`,
+	}
+	process := func(srcs ...map[string]string) *Modules {
+		t.Helper()
+		ms := NewModules()
+		for _, m := range srcs {
+			for name, src := range m {
+				if err := ms.Parse(src, name); err != nil {
+					t.Fatalf("cannot parse %s: %v\n%s", name, err, src)
+				}
+			}
+		}
+		if errs := ms.Process(); len(errs) > 0 {
+			t.Fatalf("cannot process modules: %v", errs)
+		}
+		return ms
+	}
+	orig := process(deps, srcs)
+	got := ToEntry(orig.Modules["test"]).YANGString()
+
+	// The module imports the modules it refers to, with their prefixes
+	// within it, and keeps its revisions.
+	for _, want := range []string{
+		"\timport b {\n\t\tprefix bb;\n\t}\n\timport c {\n\t\tprefix bb2;\n\t}\n",
+		"\torganization \"Example\";\n\trevision 2026-01-02 {\n\t\tdescription \"Second.\";\n\t}\n\trevision 2026-01-01;\n",
+		"\tidentity sub-id {\n\t\tbase bb:base-id;\n\t}\n",
+		"path \"/bb:target/bb:k\";",
+		"if-feature \"bb:bf\";",
+		"must \"/bb:target/bb:k = 'x'\";",
+		"default \"bb:base-id\";",
+		"base bb2:cid;",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("YANGString does not contain %q:\n%s", want, got)
+		}
+	}
+
+	// The source may be processed with the modules it imports, without
+	// the submodule and augments it was built from, into an equivalent
+	// tree.
+	round := process(deps, map[string]string{"test.yang": got})
+	re := ToEntry(round.Modules["test"])
+	if diff := cmp.Diff(got, re.YANGString()); diff != "" {
+		t.Errorf("YANGString of round trip (-first, +second):\n%s", diff)
+	}
+	for _, tt := range []struct {
+		path     string
+		wantBase string
+	}{
+		{"c/id", "b:base-id"},
+		{"c/x", "b:base-id"},
+		{"c/y", "c:cid"},
+		{"s", "test:own"},
+	} {
+		e := re.Find(tt.path)
+		if e == nil || e.Type == nil || e.Type.IdentityBase == nil {
+			t.Errorf("round trip has no identityref %s", tt.path)
+			continue
+		}
+		b := e.Type.IdentityBase
+		if got := RootNode(b).Name + ":" + b.Name; got != tt.wantBase {
+			t.Errorf("round trip of %s: got base %s, want %s", tt.path, got, tt.wantBase)
+		}
+	}
+	if ids := round.Modules["test"].Identity; len(ids) != 2 {
+		t.Errorf("round trip has %d identities, want 2", len(ids))
+	}
+
+	// The source of a submodule belongs to its module.
+	sub := ToEntry(orig.SubModules["test-sub"]).YANGString()
+	if want := "submodule test-sub {\n\tyang-version 1.1;\n\tbelongs-to test {\n\t\tprefix t;\n\t}\n\timport b {\n\t\tprefix sb;\n\t}\n\trevision 2026-01-01;\n"; !strings.HasPrefix(sub, want) {
+		t.Errorf("YANGString of submodule does not start with %q:\n%s", want, sub)
+	}
+}
@@ -16,6 +16,7 @@ package yang
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/google/go-cmp/cmp"
 )
@@ -250,9 +251,22 @@ type YangType struct {
 	POSIXPattern     []string    `json:",omitempty"` // limiting POSIX ERE on strings (specified by openconfig-extensions:posix-pattern)
 	Range            YangRange   `json:",omitempty"` // range for integers
 	Type             []*YangType `json:",omitempty"` // for unions
+
+	// unionDeduped is set if members of a union were omitted from Type
+	// because they were equal to another member.
+	unionDeduped bool
+	// invertMatch holds the patterns of Pattern that have the modifier
+	// invert-match. It is replaced, rather than changed, when a type
+	// derived from y adds to it, as it is shared with y.
+	invertMatch map[string]bool
 }
 
-// Equal returns true if y and t describe the same type.
+// Equal returns true if y and t describe the same type. The kinds, units,
+// defaults, fraction-digits, identity bases, lengths, ranges, patterns, paths,
+// require-instance values, enumeration and bit values, and recursively the
+// member types of unions, of y and t are compared. The names of the types,
+// and their Base and Root, are not compared, so two leaves whose types are
+// derived from different typedefs with the same restrictions have equal types.
 func (y *YangType) Equal(t *YangType) bool {
 	switch {
 	case y == t:
@@ -273,16 +287,263 @@ func (y *YangType) Equal(t *YangType) bool {
 		y.Path != t.Path,
 		!ssEqual(y.Pattern, t.Pattern),
 		!ssEqual(y.POSIXPattern, t.POSIXPattern),
+		!sbEqual(y.invertMatch, t.invertMatch),
 		len(y.Range) != len(t.Range),
 		!y.Range.Equal(t.Range),
 		!tsEqual(y.Type, t.Type),
-		!cmp.Equal(y.Enum, t.Enum, cmp.Comparer(func(t, u EnumType) bool {
-			return cmp.Equal(t.unique, u.unique) && cmp.Equal(t.ToInt, u.ToInt) && cmp.Equal(t.ToString, u.ToString)
-		})):
+		!cmp.Equal(y.Enum, t.Enum, enumTypeComparer),
+		!cmp.Equal(y.Bit, t.Bit, enumTypeComparer):
+
+		return false
+	}
+	return true
+}
+
+// enumTypeComparer compares the values of two EnumTypes.
+var enumTypeComparer = cmp.Comparer(func(t, u EnumType) bool {
+	return cmp.Equal(t.unique, u.unique) && cmp.Equal(t.ToInt, u.ToInt) && cmp.Equal(t.ToString, u.ToString)
+})
+
+// PatternInverted returns true if the pattern p of y has the modifier
+// invert-match, in which case a value of y must not match p.
+func (y *YangType) PatternInverted(p string) bool {
+	return y != nil && y.invertMatch[p]
+}
+
+// UnionMembersDeduped returns true if y is a union from which one or more
+// member types were omitted because they were equal, according to Equal, to
+// an earlier member. In this case Type holds fewer members than were given in
+// the YANG source.
+func (y *YangType) UnionMembersDeduped() bool {
+	return y != nil && y.unionDeduped
+}
+
+// Precision returns the number of fraction digits of y and true if y is a
+// decimal64 type. The fraction digits are those of the fraction-digits
+// statement of the type, or, if y is derived from a typedef, of the typedef
+// that declares them, as fraction-digits may not be restated by derived
+// types. Precision returns 0 and false if y is not a decimal64 type.
+func (y *YangType) Precision() (int, bool) {
+	if y == nil || y.Kind != Ydecimal64 {
+		return 0, false
+	}
+	return y.FractionDigits, true
+}
+
+// IsCompatibleWith returns true if every valid value of type o is also a
+// valid value of y, i.e., if data of type o remains valid when its type is
+// changed to y.  For example, uint8 is compatible with uint16, and a string
+// with length 1..10 is compatible with a string with length 0..255, but the
+// reverse is not true in either case.
+//
+// Only numeric, string, binary, enumeration, boolean and empty types, and
+// unions of these, are compared structurally.  Other types are compatible
+// only when they are equal.
+func (y *YangType) IsCompatibleWith(o *YangType) bool {
+	switch {
+	case y.Equal(o):
+		return true
+	case y == nil || o == nil:
+		return false
+	case o.Kind == Yunion:
+		if len(o.Type) == 0 {
+			return false
+		}
+		for _, ot := range o.Type {
+			if !y.IsCompatibleWith(ot) {
+				return false
+			}
+		}
+		return true
+	case y.Kind == Yunion:
+		for _, yt := range y.Type {
+			if yt.IsCompatibleWith(o) {
+				return true
+			}
+		}
+		return false
+	}
 
+	switch {
+	case isIntegerKind(y.Kind) && isIntegerKind(o.Kind):
+		return effectiveRange(y).Contains(effectiveRange(o))
+	case y.Kind != o.Kind:
 		return false
 	}
-	// TODO(borman): Base, Bit
+
+	switch y.Kind {
+	case Ydecimal64:
+		return o.FractionDigits <= y.FractionDigits && y.Range.Contains(o.Range)
+	case Ystring:
+		// Patterns are ANDed, so o must be at least as restrictive as y.
+		return effectiveLength(y).Contains(effectiveLength(o)) &&
+			ssSubset(y.Pattern, o.Pattern) &&
+			ssSubset(y.POSIXPattern, o.POSIXPattern)
+	case Ybinary:
+		return effectiveLength(y).Contains(effectiveLength(o))
+	case Yenum:
+		if y.Enum == nil || o.Enum == nil {
+			return false
+		}
+		for name, v := range o.Enum.ToInt {
+			if yv, ok := y.Enum.ToInt[name]; !ok || yv != v {
+				return false
+			}
+		}
+		return true
+	case Ybool, Yempty:
+		return true
+	}
+	return false
+}
+
+// FlattenedUnionTypes returns the member types of union y, with the members
+// of any nested unions expanded in place. It returns nil if y is not a union.
+func (y *YangType) FlattenedUnionTypes() []*YangType {
+	if y == nil || y.Kind != Yunion {
+		return nil
+	}
+	var ts []*YangType
+	for _, t := range y.Type {
+		if t.Kind == Yunion {
+			ts = append(ts, t.FlattenedUnionTypes()...)
+			continue
+		}
+		ts = append(ts, t)
+	}
+	return ts
+}
+
+// signature returns a concise, human readable description of y and its
+// restrictions, e.g., "uint16 [1..100]" or "union<string, uint32>".
+func (y *YangType) signature() string {
+	if y == nil {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(y.Kind.String())
+
+	var facets []string
+	switch y.Kind {
+	case Ydecimal64:
+		facets = append(facets, fmt.Sprintf("fraction-digits %d", y.FractionDigits))
+	case Ystring:
+		for _, p := range y.Pattern {
+			if y.PatternInverted(p) {
+				facets = append(facets, fmt.Sprintf("pattern '%s' invert-match", p))
+				continue
+			}
+			facets = append(facets, fmt.Sprintf("pattern '%s'", p))
+		}
+		for _, p := range y.POSIXPattern {
+			facets = append(facets, fmt.Sprintf("posix-pattern '%s'", p))
+		}
+	case Yenum:
+		if y.Enum != nil {
+			for _, v := range y.Enum.Values() {
+				facets = append(facets, y.Enum.Name(v))
+			}
+		}
+	case Ybits:
+		if y.Bit != nil {
+			for _, v := range y.Bit.Values() {
+				facets = append(facets, y.Bit.Name(v))
+			}
+		}
+	case Yidentityref:
+		if y.IdentityBase != nil {
+			facets = append(facets, "base "+y.IdentityBase.Name)
+		}
+	case Yleafref:
+		if y.Path != "" {
+			facets = append(facets, "path "+y.Path)
+		}
+	case Yunion:
+		var ms []string
+		for _, t := range y.FlattenedUnionTypes() {
+			ms = append(ms, t.signature())
+		}
+		b.WriteString("<" + strings.Join(ms, ", ") + ">")
+	}
+	if len(facets) > 0 {
+		b.WriteString(" {" + strings.Join(facets, ", ") + "}")
+	}
+
+	switch {
+	case isIntegerKind(y.Kind) && len(y.Range) > 0 && !y.Range.Equal(effectiveRange(&YangType{Kind: y.Kind})):
+		b.WriteString(" [" + y.Range.String() + "]")
+	case y.Kind == Ydecimal64 && len(y.Range) > 0 && !isDecimal64FullRange(y.Range):
+		b.WriteString(" [" + y.Range.String() + "]")
+	}
+	if (y.Kind == Ystring || y.Kind == Ybinary) && len(y.Length) > 0 && !y.Length.Equal(Uint64Range) {
+		b.WriteString(" (length " + y.Length.String() + ")")
+	}
+	return b.String()
+}
+
+// isDecimal64FullRange returns true if r is the unrestricted range of a
+// decimal64.
+func isDecimal64FullRange(r YangRange) bool {
+	return len(r) == 1 &&
+		r[0].Min.Negative && r[0].Min.Value == AbsMinInt64 &&
+		!r[0].Max.Negative && r[0].Max.Value == MaxInt64
+}
+
+// isIntegerKind returns true if k is one of the built-in integer types.
+func isIntegerKind(k TypeKind) bool {
+	switch k {
+	case Yint8, Yint16, Yint32, Yint64, Yuint8, Yuint16, Yuint32, Yuint64:
+		return true
+	}
+	return false
+}
+
+// effectiveRange returns the range of the integer type y, which is the range
+// of its built-in type if y does not restrict it.
+func effectiveRange(y *YangType) YangRange {
+	if len(y.Range) > 0 {
+		return y.Range
+	}
+	switch y.Kind {
+	case Yint8:
+		return Int8Range
+	case Yint16:
+		return Int16Range
+	case Yint32:
+		return Int32Range
+	case Yint64:
+		return Int64Range
+	case Yuint8:
+		return Uint8Range
+	case Yuint16:
+		return Uint16Range
+	case Yuint32:
+		return Uint32Range
+	default:
+		return Uint64Range
+	}
+}
+
+// effectiveLength returns the length of y, which is 0..max if y does not
+// restrict it.
+func effectiveLength(y *YangType) YangRange {
+	if len(y.Length) > 0 {
+		return y.Length
+	}
+	return Uint64Range
+}
+
+// ssSubset returns true if every string in s1 is also in s2.
+func ssSubset(s1, s2 []string) bool {
+	m := make(map[string]bool, len(s2))
+	for _, s := range s2 {
+		m[s] = true
+	}
+	for _, s := range s1 {
+		if !m[s] {
+			return false
+		}
+	}
 	return true
 }
 
@@ -301,6 +562,19 @@ func (y *YangType) typedef() *Typedef {
 	}
 }
 
+// sbEqual returns true if the sets s1 and s2 hold the same strings.
+func sbEqual(s1, s2 map[string]bool) bool {
+	if len(s1) != len(s2) {
+		return false
+	}
+	for s := range s1 {
+		if !s2[s] {
+			return false
+		}
+	}
+	return true
+}
+
 // ssEqual returns true if the two slices are equivalent.
 func ssEqual(s1, s2 []string) bool {
 	if len(s1) != len(s2) {
@@ -18,6 +18,16 @@ import (
 	"testing"
 )
 
+// enumTypeOf sets the values of e, which is returned, to values.
+func enumTypeOf(e *EnumType, values map[string]int64) *EnumType {
+	for name, v := range values {
+		if err := e.Set(name, v); err != nil {
+			panic(err)
+		}
+	}
+	return e
+}
+
 func TestYangTypeEqual(t *testing.T) {
 
 	tests := []struct {
@@ -120,6 +130,73 @@ func TestYangTypeEqual(t *testing.T) {
 			HasDefault: true,
 		},
 		wantEqual: false,
+	}, {
+		name:      "enums-equal",
+		inLeft:    &YangType{Kind: Yenum, Enum: enumTypeOf(NewEnumType(), map[string]int64{"A": 0, "B": 1})},
+		inRight:   &YangType{Kind: Yenum, Enum: enumTypeOf(NewEnumType(), map[string]int64{"A": 0, "B": 1})},
+		wantEqual: true,
+	}, {
+		name:      "enum-values-unequal",
+		inLeft:    &YangType{Kind: Yenum, Enum: enumTypeOf(NewEnumType(), map[string]int64{"A": 0, "B": 1})},
+		inRight:   &YangType{Kind: Yenum, Enum: enumTypeOf(NewEnumType(), map[string]int64{"A": 0, "B": 2})},
+		wantEqual: false,
+	}, {
+		name:      "enum-names-unequal",
+		inLeft:    &YangType{Kind: Yenum, Enum: enumTypeOf(NewEnumType(), map[string]int64{"A": 0, "B": 1})},
+		inRight:   &YangType{Kind: Yenum, Enum: enumTypeOf(NewEnumType(), map[string]int64{"A": 0, "C": 1})},
+		wantEqual: false,
+	}, {
+		name:      "bits-equal",
+		inLeft:    &YangType{Kind: Ybits, Bit: enumTypeOf(NewBitfield(), map[string]int64{"X": 0, "Y": 3})},
+		inRight:   &YangType{Kind: Ybits, Bit: enumTypeOf(NewBitfield(), map[string]int64{"X": 0, "Y": 3})},
+		wantEqual: true,
+	}, {
+		name:      "bit-positions-unequal",
+		inLeft:    &YangType{Kind: Ybits, Bit: enumTypeOf(NewBitfield(), map[string]int64{"X": 0, "Y": 3})},
+		inRight:   &YangType{Kind: Ybits, Bit: enumTypeOf(NewBitfield(), map[string]int64{"X": 0, "Y": 4})},
+		wantEqual: false,
+	}, {
+		name: "union-members-equal-ignoring-base",
+		inLeft: &YangType{Kind: Yunion, Type: []*YangType{
+			{Name: "a", Kind: Ystring, Base: &Type{Name: "a"}},
+			{Kind: Ydecimal64, FractionDigits: 2},
+		}},
+		inRight: &YangType{Kind: Yunion, Type: []*YangType{
+			{Name: "b", Kind: Ystring, Base: &Type{Name: "b"}},
+			{Kind: Ydecimal64, FractionDigits: 2},
+		}},
+		wantEqual: true,
+	}, {
+		name: "union-member-fraction-digits-unequal",
+		inLeft: &YangType{Kind: Yunion, Type: []*YangType{
+			{Kind: Ystring},
+			{Kind: Ydecimal64, FractionDigits: 2},
+		}},
+		inRight: &YangType{Kind: Yunion, Type: []*YangType{
+			{Kind: Ystring},
+			{Kind: Ydecimal64, FractionDigits: 3},
+		}},
+		wantEqual: false,
+	}, {
+		name: "union-member-order-unequal",
+		inLeft: &YangType{Kind: Yunion, Type: []*YangType{
+			{Kind: Ystring},
+			{Kind: Yint8},
+		}},
+		inRight: &YangType{Kind: Yunion, Type: []*YangType{
+			{Kind: Yint8},
+			{Kind: Ystring},
+		}},
+		wantEqual: false,
+	}, {
+		name: "nested-union-unequal",
+		inLeft: &YangType{Kind: Yunion, Type: []*YangType{
+			{Kind: Yunion, Type: []*YangType{{Kind: Ystring, Pattern: []string{"a.*"}}}},
+		}},
+		inRight: &YangType{Kind: Yunion, Type: []*YangType{
+			{Kind: Yunion, Type: []*YangType{{Kind: Ystring, Pattern: []string{"b.*"}}}},
+		}},
+		wantEqual: false,
 	}}
 
 	for _, tt := range tests {
@@ -134,3 +211,133 @@ func TestYangTypeEqual(t *testing.T) {
 		})
 	}
 }
+
+func TestYangTypeIsCompatibleWith(t *testing.T) {
+	enumType := func(names ...string) *EnumType {
+		e := NewEnumType()
+		for _, n := range names {
+			if err := e.SetNext(n); err != nil {
+				t.Fatalf("cannot set enum %s: %v", n, err)
+			}
+		}
+		return e
+	}
+
+	tests := []struct {
+		name           string
+		inType         *YangType
+		inOther        *YangType
+		wantCompatible bool
+	}{{
+		name:           "both-nil",
+		wantCompatible: true,
+	}, {
+		name:           "other-nil",
+		inType:         &YangType{Kind: Ystring},
+		wantCompatible: false,
+	}, {
+		name:           "uint8-into-uint16",
+		inType:         &YangType{Kind: Yuint16, Range: Uint16Range},
+		inOther:        &YangType{Kind: Yuint8, Range: Uint8Range},
+		wantCompatible: true,
+	}, {
+		name:           "uint16-into-uint8",
+		inType:         &YangType{Kind: Yuint8, Range: Uint8Range},
+		inOther:        &YangType{Kind: Yuint16, Range: Uint16Range},
+		wantCompatible: false,
+	}, {
+		name:           "uint32-into-int64",
+		inType:         &YangType{Kind: Yint64},
+		inOther:        &YangType{Kind: Yuint32},
+		wantCompatible: true,
+	}, {
+		name:           "int8-into-uint64",
+		inType:         &YangType{Kind: Yuint64, Range: Uint64Range},
+		inOther:        &YangType{Kind: Yint8, Range: Int8Range},
+		wantCompatible: false,
+	}, {
+		name:           "narrower-range",
+		inType:         &YangType{Kind: Yint32, Range: YangRange{{Min: FromInt(-10), Max: FromInt(10)}}},
+		inOther:        &YangType{Kind: Yint32, Range: YangRange{{Min: FromInt(0), Max: FromInt(5)}, {Min: FromInt(7), Max: FromInt(8)}}},
+		wantCompatible: true,
+	}, {
+		name:           "wider-range",
+		inType:         &YangType{Kind: Yint32, Range: YangRange{{Min: FromInt(0), Max: FromInt(5)}}},
+		inOther:        &YangType{Kind: Yint32, Range: YangRange{{Min: FromInt(-10), Max: FromInt(10)}}},
+		wantCompatible: false,
+	}, {
+		name:           "decimal64-fewer-fraction-digits",
+		inType:         &YangType{Kind: Ydecimal64, FractionDigits: 5},
+		inOther:        &YangType{Kind: Ydecimal64, FractionDigits: 2},
+		wantCompatible: true,
+	}, {
+		name:           "decimal64-more-fraction-digits",
+		inType:         &YangType{Kind: Ydecimal64, FractionDigits: 2},
+		inOther:        &YangType{Kind: Ydecimal64, FractionDigits: 5},
+		wantCompatible: false,
+	}, {
+		name:           "kind-change",
+		inType:         &YangType{Kind: Ystring},
+		inOther:        &YangType{Kind: Yint8, Range: Int8Range},
+		wantCompatible: false,
+	}, {
+		name:           "string-narrower-length",
+		inType:         &YangType{Kind: Ystring, Length: YangRange{{Min: FromInt(0), Max: FromInt(255)}}},
+		inOther:        &YangType{Kind: Ystring, Length: YangRange{{Min: FromInt(1), Max: FromInt(10)}}},
+		wantCompatible: true,
+	}, {
+		name:           "string-wider-length",
+		inType:         &YangType{Kind: Ystring, Length: YangRange{{Min: FromInt(1), Max: FromInt(10)}}},
+		inOther:        &YangType{Kind: Ystring},
+		wantCompatible: false,
+	}, {
+		name:           "string-added-pattern",
+		inType:         &YangType{Kind: Ystring},
+		inOther:        &YangType{Kind: Ystring, Pattern: []string{"a.*"}},
+		wantCompatible: true,
+	}, {
+		name:           "string-removed-pattern",
+		inType:         &YangType{Kind: Ystring, Pattern: []string{"a.*"}},
+		inOther:        &YangType{Kind: Ystring},
+		wantCompatible: false,
+	}, {
+		name:           "enum-subset",
+		inType:         &YangType{Kind: Yenum, Enum: enumType("a", "b", "c")},
+		inOther:        &YangType{Kind: Yenum, Enum: enumType("a", "b")},
+		wantCompatible: true,
+	}, {
+		name:           "enum-superset",
+		inType:         &YangType{Kind: Yenum, Enum: enumType("a", "b")},
+		inOther:        &YangType{Kind: Yenum, Enum: enumType("a", "b", "c")},
+		wantCompatible: false,
+	}, {
+		name:           "enum-different-values",
+		inType:         &YangType{Kind: Yenum, Enum: enumType("a", "b")},
+		inOther:        &YangType{Kind: Yenum, Enum: enumType("b")},
+		wantCompatible: false,
+	}, {
+		name: "member-of-union",
+		inType: &YangType{Kind: Yunion, Type: []*YangType{
+			{Kind: Ystring},
+			{Kind: Yuint32, Range: Uint32Range},
+		}},
+		inOther:        &YangType{Kind: Yuint8, Range: Uint8Range},
+		wantCompatible: true,
+	}, {
+		name:   "union-into-single-type",
+		inType: &YangType{Kind: Yuint32, Range: Uint32Range},
+		inOther: &YangType{Kind: Yunion, Type: []*YangType{
+			{Kind: Ystring},
+			{Kind: Yuint8, Range: Uint8Range},
+		}},
+		wantCompatible: false,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.inType.IsCompatibleWith(tt.inOther); got != tt.wantCompatible {
+				t.Errorf("IsCompatibleWith: got %v, want %v", got, tt.wantCompatible)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+// This file implements the serialization of modules to YIN, the XML encoding
+// of YANG defined in RFC6020 section 11 and RFC7950 section 13.
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// YINNamespace is the XML namespace of the elements of YIN.
+const YINNamespace = "urn:ietf:params:xml:ns:yang:yin:1"
+
+// yinArgument describes how the argument of a YANG statement is encoded in
+// YIN: name is the name of the attribute, or, if element is true, of the
+// child element, that holds the argument.
+type yinArgument struct {
+	name    string
+	element bool
+}
+
+// yinArguments maps each YANG keyword that takes an argument to how its
+// argument is encoded, as given by the table of RFC7950 section 13.1.
+// Keywords that do not take an argument, such as input and output, are not
+// included.
+var yinArguments = map[string]yinArgument{
+	"action":           {name: "name"},
+	"anydata":          {name: "name"},
+	"anyxml":           {name: "name"},
+	"argument":         {name: "name"},
+	"augment":          {name: "target-node"},
+	"base":             {name: "name"},
+	"belongs-to":       {name: "module"},
+	"bit":              {name: "name"},
+	"case":             {name: "name"},
+	"choice":           {name: "name"},
+	"config":           {name: "value"},
+	"contact":          {name: "text", element: true},
+	"container":        {name: "name"},
+	"default":          {name: "value"},
+	"description":      {name: "text", element: true},
+	"deviate":          {name: "value"},
+	"deviation":        {name: "target-node"},
+	"enum":             {name: "name"},
+	"error-app-tag":    {name: "value"},
+	"error-message":    {name: "value", element: true},
+	"extension":        {name: "name"},
+	"feature":          {name: "name"},
+	"fraction-digits":  {name: "value"},
+	"grouping":         {name: "name"},
+	"identity":         {name: "name"},
+	"if-feature":       {name: "name"},
+	"import":           {name: "module"},
+	"include":          {name: "module"},
+	"key":              {name: "value"},
+	"leaf":             {name: "name"},
+	"leaf-list":        {name: "name"},
+	"length":           {name: "value"},
+	"list":             {name: "name"},
+	"mandatory":        {name: "value"},
+	"max-elements":     {name: "value"},
+	"min-elements":     {name: "value"},
+	"modifier":         {name: "value"},
+	"module":           {name: "name"},
+	"must":             {name: "condition"},
+	"namespace":        {name: "uri"},
+	"notification":     {name: "name"},
+	"ordered-by":       {name: "value"},
+	"organization":     {name: "text", element: true},
+	"path":             {name: "value"},
+	"pattern":          {name: "value"},
+	"position":         {name: "value"},
+	"prefix":           {name: "value"},
+	"presence":         {name: "value"},
+	"range":            {name: "value"},
+	"reference":        {name: "text", element: true},
+	"refine":           {name: "target-node"},
+	"require-instance": {name: "value"},
+	"revision":         {name: "date"},
+	"revision-date":    {name: "date"},
+	"rpc":              {name: "name"},
+	"status":           {name: "value"},
+	"submodule":        {name: "name"},
+	"type":             {name: "name"},
+	"typedef":          {name: "name"},
+	"unique":           {name: "tag"},
+	"units":            {name: "name"},
+	"uses":             {name: "name"},
+	"value":            {name: "value"},
+	"when":             {name: "condition"},
+	"yang-version":     {name: "value"},
+	"yin-element":      {name: "value"},
+}
+
+// ToYIN writes m, which must be a module or submodule within a Modules on
+// which Process has been called, to w in YIN. The statements of m are written
+// in the order of its source, each as an element named by its keyword, with
+// its argument as an attribute or a child element as given by RFC7950 section
+// 13.1. The element of the module declares the namespaces of the prefixes of
+// the module and of its imports, which qualify the elements of the uses of
+// extensions. The argument of the use of an extension is encoded as given by
+// the argument statement of the extension's definition. An error is returned
+// if an imported module or the definition of an extension cannot be found.
+func (m *Module) ToYIN(w io.Writer) error {
+	if m.Source == nil {
+		return fmt.Errorf("module %s has no source statement", m.Name)
+	}
+	y := &yinWriter{m: m}
+	if err := y.namespaces(); err != nil {
+		return err
+	}
+	y.buf.WriteString(xml.Header)
+	if err := y.statement(m.Source, ""); err != nil {
+		return err
+	}
+	_, err := w.Write(y.buf.Bytes())
+	return err
+}
+
+// A yinWriter accumulates the YIN encoding of the statements of m.
+type yinWriter struct {
+	m   *Module
+	buf bytes.Buffer
+	// xmlns holds the namespace declarations of the root element, in the
+	// order in which they are written.
+	xmlns [][2]string
+}
+
+// namespaces finds the namespaces of the prefixes that are in scope within
+// y.m: its own prefix, or, for a submodule, that of the module it belongs to,
+// and the prefixes of its imports.
+func (y *yinWriter) namespaces() error {
+	mod := y.m
+	if y.m.BelongsTo != nil {
+		if mod = module(y.m); mod == nil {
+			return fmt.Errorf("%s: module %s not found", Source(y.m.BelongsTo), y.m.BelongsTo.Name)
+		}
+	}
+	if mod.Namespace == nil {
+		return fmt.Errorf("module %s has no namespace", mod.Name)
+	}
+	y.xmlns = append(y.xmlns, [2]string{y.m.GetPrefix(), mod.Namespace.Name})
+	for _, i := range y.m.Import {
+		im := y.m.Modules.FindModule(i)
+		if im == nil || im.Namespace == nil {
+			return fmt.Errorf("%s: module %s not found", Source(i), i.Name)
+		}
+		y.xmlns = append(y.xmlns, [2]string{i.Prefix.Name, im.Namespace.Name})
+	}
+	return nil
+}
+
+// statement writes s and its substatements, indented by indent.
+func (y *yinWriter) statement(s *Statement, indent string) error {
+	var arg yinArgument
+	var argPrefix string
+	if pfx, name := getPrefix(s.Keyword); pfx != "" {
+		// The use of an extension, whose argument is described by its
+		// definition.
+		var ext *Extension
+		if mod := FindModuleByPrefix(y.m, pfx); mod != nil {
+			ext = extensionDef(module(mod), name)
+		}
+		if ext == nil {
+			return fmt.Errorf("%s: extension %s not found", s.Location(), s.Keyword)
+		}
+		if ext.Argument != nil {
+			arg = yinArgument{name: ext.Argument.Name, element: ext.YinElement()}
+		}
+		// The element holding the argument is in the namespace of the
+		// extension.
+		argPrefix = pfx + ":"
+	} else {
+		var ok bool
+		if arg, ok = yinArguments[s.Keyword]; !ok && s.HasArgument {
+			return fmt.Errorf("%s: unknown statement %s", s.Location(), s.Keyword)
+		}
+	}
+	if arg.name == "" && s.HasArgument {
+		return fmt.Errorf("%s: statement %s does not take an argument", s.Location(), s.Keyword)
+	}
+
+	fmt.Fprintf(&y.buf, "%s<%s", indent, s.Keyword)
+	if s == y.m.Source {
+		fmt.Fprintf(&y.buf, ` %s="%s" xmlns="%s"`, arg.name, yinAttr(s.Argument), YINNamespace)
+		for _, ns := range y.xmlns {
+			fmt.Fprintf(&y.buf, ` xmlns:%s="%s"`, ns[0], yinAttr(ns[1]))
+		}
+	} else if s.HasArgument && !arg.element {
+		fmt.Fprintf(&y.buf, ` %s="%s"`, arg.name, yinAttr(s.Argument))
+	}
+	if len(s.statements) == 0 && !(s.HasArgument && arg.element) {
+		y.buf.WriteString("/>\n")
+		return nil
+	}
+	y.buf.WriteString(">\n")
+	if s.HasArgument && arg.element {
+		fmt.Fprintf(&y.buf, "%s  <%s%s>%s</%[2]s%[3]s>\n", indent, argPrefix, arg.name, yinText.Replace(s.Argument))
+	}
+	for _, ss := range s.statements {
+		if err := y.statement(ss, indent+"  "); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(&y.buf, "%s</%s>\n", indent, s.Keyword)
+	return nil
+}
+
+// yinText escapes the characters that are special within XML character data.
+var yinText = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// yinAttr returns s escaped for use within an XML attribute value. Unlike in
+// character data, whitespace other than spaces is escaped so that it is not
+// normalized when the attribute is read.
+func yinAttr(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
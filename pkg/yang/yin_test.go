@@ -0,0 +1,113 @@
+// Copyright 2026 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package yang
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io/ioutil"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/openconfig/gnmi/errdiff"
+)
+
+func TestToYIN(t *testing.T) {
+	ms := NewModules()
+	for _, name := range []string{"testdata/yin-ext.yang", "testdata/yin.yang"} {
+		if err := ms.Read(name); err != nil {
+			t.Fatalf("cannot read %s: %v", name, err)
+		}
+	}
+	if errs := ms.Process(); len(errs) > 0 {
+		t.Fatalf("cannot process modules: %v", errs)
+	}
+
+	var buf bytes.Buffer
+	if err := ms.Modules["yin"].ToYIN(&buf); err != nil {
+		t.Fatalf("ToYIN: %v", err)
+	}
+	want, err := ioutil.ReadFile("testdata/yin.xml")
+	if err != nil {
+		t.Fatalf("cannot read golden file: %v", err)
+	}
+	if diff := cmp.Diff(string(want), buf.String()); diff != "" {
+		t.Errorf("ToYIN (-want, +got):\n%s", diff)
+	}
+
+	// The output is well formed XML.
+	d := xml.NewDecoder(&buf)
+	for {
+		if _, err := d.Token(); err != nil {
+			if err.Error() != "EOF" {
+				t.Errorf("ToYIN output is not well formed: %v", err)
+			}
+			break
+		}
+	}
+}
+
+func TestToYINErrors(t *testing.T) {
+	tests := []struct {
+		desc          string
+		in            string
+		wantErrSubstr string
+	}{{
+		desc: "submodule",
+		in: `
+			submodule test-sub {
+				belongs-to test { prefix "t"; }
+				leaf l { type string; }
+			}`,
+	}, {
+		desc: "unknown extension",
+		in: `
+			submodule test-sub {
+				belongs-to test { prefix "t"; }
+				leaf l { type string; t:unknown "x"; }
+			}`,
+		wantErrSubstr: "test-sub.yang:4:27: extension t:unknown not found",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			ms := NewModules()
+			for name, src := range map[string]string{
+				"test.yang": `
+					module test {
+						prefix "t";
+						namespace "urn:t";
+						include test-sub;
+					}`,
+				"test-sub.yang": tt.in,
+			} {
+				if err := ms.Parse(src, name); err != nil {
+					t.Fatalf("cannot parse %s: %v", name, err)
+				}
+			}
+			if errs := ms.Process(); len(errs) > 0 {
+				t.Fatalf("cannot process modules: %v", errs)
+			}
+			var buf bytes.Buffer
+			err := ms.SubModules["test-sub"].ToYIN(&buf)
+			if diff := errdiff.Substring(err, tt.wantErrSubstr); diff != "" {
+				t.Fatalf("ToYIN: %s", diff)
+			}
+			if err == nil && !bytes.Contains(buf.Bytes(), []byte(`<submodule name="test-sub" xmlns="urn:ietf:params:xml:ns:yang:yin:1" xmlns:t="urn:t">`)) {
+				t.Errorf("ToYIN: got %s, want the prefix t declared with the namespace of test", buf.String())
+			}
+		})
+	}
+}